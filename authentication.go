@@ -40,12 +40,13 @@ func (cc *AuthenticationClient) CreateToken(ctx context.Context, request *TokenC
 	return &response, nil
 }
 
-func decodeTokenCreateError(data []byte) error {
+func decodeTokenCreateError(res *http.Response, data []byte) error {
 	var apiErr TokenCreateError
-	err := json.Unmarshal(data, &apiErr)
-	if err != nil {
-		return err
+	if err := json.Unmarshal(data, &apiErr); err != nil || apiErr.Code == "" {
+		apiErr = TokenCreateError{Code: errorCodeForStatus(res.StatusCode), Message: res.Status}
 	}
+	apiErr.Status = res.StatusCode
+	apiErr.RequestID = res.Header.Get("X-Request-Id")
 	return &apiErr
 }
 
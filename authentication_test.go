@@ -50,6 +50,7 @@ func TestAuthenticationClient(t *testing.T) {
 				wantErr: &notionapi.TokenCreateError{
 					Code:    "invalid_grant",
 					Message: "Invalid code.",
+					Status:  http.StatusBadRequest,
 				},
 			},
 		}
@@ -0,0 +1,296 @@
+package notionapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// BackupVersion is the version of the format of the archives written by
+// Backup. OpenBackup only reads archives of this version.
+const BackupVersion = 1
+
+// An archive written by Backup is a directory holding:
+//
+//	manifest.json          the BackupManifest, written last
+//	pages/<id>.json        a PageBackup per page
+//	databases/<id>.json    a DatabaseBackup per database
+//	files/<owner id>/...   the downloaded files of pages, databases and blocks
+const (
+	backupManifestFile = "manifest.json"
+	backupPagesDir     = "pages"
+	backupDatabasesDir = "databases"
+	backupFilesDir     = "files"
+)
+
+// BackupManifest describes a backup archive. Archives without a manifest are
+// incomplete.
+type BackupManifest struct {
+	Version     int          `json:"version"`
+	CreatedTime time.Time    `json:"created_time"`
+	Pages       []PageID     `json:"pages"`
+	Databases   []DatabaseID `json:"databases"`
+}
+
+// PageBackup is a page of a backup, with its content and comments.
+type PageBackup struct {
+	Page *Page `json:"page"`
+	// The blocks of the page, with their children.
+	Blocks Blocks `json:"blocks"`
+	// The comments of the page and of its blocks.
+	Comments []Comment    `json:"comments,omitempty"`
+	Files    []BackupFile `json:"files,omitempty"`
+}
+
+// DatabaseBackup is a database of a backup. Its pages are backed up as other
+// pages.
+type DatabaseBackup struct {
+	Database *Database    `json:"database"`
+	Files    []BackupFile `json:"files,omitempty"`
+}
+
+// BackupFile is a file of a page, a database or a block, downloaded to Path,
+// relative to the archive.
+type BackupFile struct {
+	FileRef
+	Path string `json:"path"`
+}
+
+// BackupOptions configures Backup.
+type BackupOptions struct {
+	// SkipBlockComments only fetches the comments of pages, instead of making
+	// a request per block for the comments of blocks.
+	SkipBlockComments bool
+	// SkipFiles does not download files.
+	SkipFiles bool
+	// ExternalFiles also downloads files hosted outside of Notion, which are
+	// otherwise only referenced by URL.
+	ExternalFiles bool
+	// TreeOptions configures the fetching of the content of pages.
+	TreeOptions []TreeOption
+	// Progress, if set, is called with every page or database once saved.
+	Progress func(Object)
+}
+
+// Backup writes a snapshot of all the pages and databases shared with the
+// integration to dir: their properties, the content and the comments of
+// pages, and the files attached to them.
+//
+// Pages and databases are found with SearchClient, so that dir holds a
+// consistent snapshot only if the workspace is not edited during the backup.
+func Backup(ctx context.Context, client *Client, dir string, opts BackupOptions) (*BackupManifest, error) {
+	for _, sub := range []string{backupPagesDir, backupDatabasesDir, backupFilesDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, err
+		}
+	}
+	b := &backup{client: client, downloader: NewDownloader(client), dir: dir, opts: opts}
+	manifest := &BackupManifest{Version: BackupVersion, CreatedTime: time.Now().UTC()}
+
+	seen := map[ObjectID]bool{}
+	it := client.Search.DoAll(ctx, nil, WithPageSize(100))
+	for it.Next() {
+		switch o := it.Value().(type) {
+		case *Page:
+			if seen[o.ID] {
+				continue
+			}
+			seen[o.ID] = true
+			if err := b.page(ctx, o); err != nil {
+				return nil, fmt.Errorf("notionapi: cannot back up page %s: %w", o.ID, err)
+			}
+			manifest.Pages = append(manifest.Pages, PageID(o.ID))
+		case *Database:
+			if seen[o.ID] {
+				continue
+			}
+			seen[o.ID] = true
+			if err := b.database(ctx, o); err != nil {
+				return nil, fmt.Errorf("notionapi: cannot back up database %s: %w", o.ID, err)
+			}
+			manifest.Databases = append(manifest.Databases, DatabaseID(o.ID))
+		default:
+			continue
+		}
+		if opts.Progress != nil {
+			opts.Progress(it.Value())
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(manifest.Pages, func(i, j int) bool { return manifest.Pages[i] < manifest.Pages[j] })
+	sort.Slice(manifest.Databases, func(i, j int) bool { return manifest.Databases[i] < manifest.Databases[j] })
+	if err := writeJSONFile(filepath.Join(dir, backupManifestFile), manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+type backup struct {
+	client     *Client
+	downloader *Downloader
+	dir        string
+	opts       BackupOptions
+}
+
+func (b *backup) page(ctx context.Context, page *Page) error {
+	if err := b.fullProperties(ctx, page); err != nil {
+		return err
+	}
+	blocks, err := b.client.Block.GetTree(ctx, BlockID(page.ID), b.opts.TreeOptions...)
+	if err != nil {
+		return err
+	}
+	pb := &PageBackup{Page: page, Blocks: blocks}
+
+	commented := []BlockID{BlockID(page.ID)}
+	if !b.opts.SkipBlockComments {
+		walkBlocks(blocks, func(block Block) {
+			commented = append(commented, block.GetID())
+		})
+	}
+	for _, id := range commented {
+		comments, err := b.client.Comment.GetAll(ctx, id).All()
+		if err != nil {
+			return err
+		}
+		pb.Comments = append(pb.Comments, comments...)
+	}
+
+	if pb.Files, err = b.files(ctx, append(PageFileRefs(page), BlocksFileRefs(blocks)...)); err != nil {
+		return err
+	}
+	return writeJSONFile(filepath.Join(b.dir, backupPagesDir, page.ID.String()+".json"), pb)
+}
+
+// fullProperties replaces the properties of the page whose values may have
+// been truncated to the 25 references returned with pages.
+func (b *backup) fullProperties(ctx context.Context, page *Page) error {
+	for name, prop := range page.Properties {
+		n := -1
+		switch p := prop.(type) {
+		case *TitleProperty:
+			n = len(p.Title)
+		case *RichTextProperty:
+			n = len(p.RichText)
+		case *RelationProperty:
+			n = len(p.Relation)
+		case *PeopleProperty:
+			n = len(p.People)
+		}
+		if n < 25 || prop.GetID() == "" {
+			continue
+		}
+		full, err := b.client.Page.GetFullProperty(ctx, PageID(page.ID), PropertyID(prop.GetID()))
+		if err != nil {
+			return err
+		}
+		page.Properties[name] = full
+	}
+	return nil
+}
+
+func (b *backup) database(ctx context.Context, db *Database) error {
+	files, err := b.files(ctx, DatabaseFileRefs(db))
+	if err != nil {
+		return err
+	}
+	return writeJSONFile(filepath.Join(b.dir, backupDatabasesDir, db.ID.String()+".json"), &DatabaseBackup{Database: db, Files: files})
+}
+
+// files downloads the files hosted by Notion, and external ones if enabled.
+func (b *backup) files(ctx context.Context, refs []FileRef) ([]BackupFile, error) {
+	if b.opts.SkipFiles {
+		return nil, nil
+	}
+	var files []BackupFile
+	for _, ref := range refs {
+		if ref.ExpiryTime == nil && !b.opts.ExternalFiles {
+			continue
+		}
+		dir := filepath.Join(b.dir, backupFilesDir, ref.ownerID())
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+		name, err := b.downloader.DownloadToDir(ctx, ref, dir)
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(b.dir, name)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, BackupFile{FileRef: ref, Path: filepath.ToSlash(rel)})
+	}
+	return files, nil
+}
+
+// BackupArchive is a backup read by OpenBackup.
+type BackupArchive struct {
+	Dir       string
+	Manifest  BackupManifest
+	Pages     []*PageBackup
+	Databases []*DatabaseBackup
+}
+
+// OpenBackup reads the archive written by Backup to dir.
+func OpenBackup(dir string) (*BackupArchive, error) {
+	a := &BackupArchive{Dir: dir}
+	if err := readJSONFile(filepath.Join(dir, backupManifestFile), &a.Manifest); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("notionapi: %s is not a complete backup: %w", dir, err)
+		}
+		return nil, err
+	}
+	if a.Manifest.Version != BackupVersion {
+		return nil, fmt.Errorf("notionapi: unsupported backup version %d, want %d", a.Manifest.Version, BackupVersion)
+	}
+	for _, id := range a.Manifest.Databases {
+		var db DatabaseBackup
+		if err := readJSONFile(filepath.Join(dir, backupDatabasesDir, id.String()+".json"), &db); err != nil {
+			return nil, err
+		}
+		a.Databases = append(a.Databases, &db)
+	}
+	for _, id := range a.Manifest.Pages {
+		var p PageBackup
+		if err := readJSONFile(filepath.Join(dir, backupPagesDir, id.String()+".json"), &p); err != nil {
+			return nil, err
+		}
+		a.Pages = append(a.Pages, &p)
+	}
+	return a, nil
+}
+
+// walkBlocks calls fn for the blocks and their children, in document order.
+func walkBlocks(blocks Blocks, fn func(Block)) {
+	for _, b := range blocks {
+		fn(b)
+		walkBlocks(blockChildren(b), fn)
+	}
+}
+
+func writeJSONFile(name string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, append(b, '\n'), 0o644)
+}
+
+func readJSONFile(name string, v interface{}) error {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("notionapi: cannot read %s: %w", name, err)
+	}
+	return nil
+}
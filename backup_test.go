@@ -0,0 +1,346 @@
+package notionapi_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jomei/notionapi"
+	"github.com/jomei/notionapi/notionapitest"
+)
+
+func richText(s string) []notionapi.RichText {
+	return []notionapi.RichText{{Type: notionapi.ObjectTypeText, Text: &notionapi.Text{Content: s}, PlainText: s}}
+}
+
+func paragraph(s string, children ...notionapi.Block) *notionapi.ParagraphBlock {
+	return &notionapi.ParagraphBlock{
+		BasicBlock: notionapi.BasicBlock{Object: notionapi.ObjectTypeBlock, Type: notionapi.BlockTypeParagraph},
+		Paragraph:  notionapi.Paragraph{RichText: richText(s), Children: children},
+	}
+}
+
+// seedWorkspace creates a database of tasks related to each other and a page
+// with nested content, a sub-page and a comment.
+func seedWorkspace(t *testing.T, ctx context.Context, srv *notionapitest.Server) {
+	client := srv.Client()
+	root := notionapi.Parent{Type: notionapi.ParentTypePageID, PageID: srv.RootPageID()}
+
+	db, err := client.Database.Create(ctx, &notionapi.DatabaseCreateRequest{
+		Parent: root,
+		Title:  richText("Tasks"),
+		Properties: notionapi.PropertyConfigs{
+			"Name": notionapi.TitlePropertyConfig{Type: notionapi.PropertyConfigTypeTitle},
+			"Tags": notionapi.MultiSelectPropertyConfig{Type: notionapi.PropertyConfigTypeMultiSelect, MultiSelect: notionapi.Select{Options: []notionapi.Option{
+				{Name: "bug", Color: notionapi.ColorRed},
+			}}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Database.Update(ctx, notionapi.DatabaseID(db.ID), &notionapi.DatabaseUpdateRequest{Properties: notionapi.PropertyConfigs{
+		"Blocked by": notionapi.RelationPropertyConfig{Type: notionapi.PropertyConfigTypeRelation, Relation: notionapi.RelationConfig{
+			DatabaseID: notionapi.DatabaseID(db.ID), SingleProperty: &notionapi.SingleProperty{},
+		}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task := func(name string, properties notionapi.Properties) *notionapi.Page {
+		properties["Name"] = notionapi.TitleProperty{Title: richText(name)}
+		page, err := client.Page.Create(ctx, &notionapi.PageCreateRequest{
+			Parent:     notionapi.Parent{Type: notionapi.ParentTypeDatabaseID, DatabaseID: notionapi.DatabaseID(db.ID)},
+			Properties: properties,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return page
+	}
+	design := task("Design", notionapi.Properties{
+		"Tags": notionapi.MultiSelectProperty{MultiSelect: []notionapi.Option{{Name: "bug"}}},
+	})
+	task("Build", notionapi.Properties{
+		"Blocked by": notionapi.RelationProperty{Relation: []notionapi.Relation{{ID: notionapi.PageID(design.ID)}}},
+	})
+
+	notes, err := client.Page.Create(ctx, &notionapi.PageCreateRequest{
+		Parent:     root,
+		Properties: notionapi.Properties{"title": notionapi.TitleProperty{Title: richText("Notes")}},
+		Children:   []notionapi.Block{paragraph("Intro", paragraph("Details"))},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Page.Create(ctx, &notionapi.PageCreateRequest{
+		Parent:     notionapi.Parent{Type: notionapi.ParentTypePageID, PageID: notionapi.PageID(notes.ID)},
+		Properties: notionapi.Properties{"title": notionapi.TitleProperty{Title: richText("Sub-page")}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Comment.Create(ctx, &notionapi.CommentCreateRequest{
+		Parent:   notionapi.Parent{Type: notionapi.ParentTypePageID, PageID: notionapi.PageID(notes.ID)},
+		RichText: richText("Looks good"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBackupRestore(t *testing.T) {
+	ctx := context.Background()
+	source := notionapitest.NewServer()
+	defer source.Close()
+	seedWorkspace(t, ctx, source)
+
+	var searches []string
+	client := source.Client(notionapi.WithMiddleware(func(next notionapi.Handler) notionapi.Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/v1/search" {
+				body, _ := ioutil.ReadAll(req.Body)
+				req.Body = ioutil.NopCloser(bytes.NewReader(body))
+				searches = append(searches, string(body))
+			}
+			return next(req)
+		}
+	}))
+	dir := t.TempDir()
+	manifest, err := notionapi.Backup(ctx, client, dir, notionapi.BackupOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Notion rejects a search filter without value and property.
+	if want := `{"page_size":100}`; len(searches) != 1 || searches[0] != want {
+		t.Errorf("Backup() searches = %v, want %s", searches, want)
+	}
+	// The root page, the notes, the sub-page and the two tasks.
+	if len(manifest.Pages) != 5 || len(manifest.Databases) != 1 {
+		t.Fatalf("Backup() manifest = %+v, want 5 pages and 1 database", manifest)
+	}
+
+	archive, err := notionapi.OpenBackup(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var notes *notionapi.PageBackup
+	for _, p := range archive.Pages {
+		if title := p.Page.Properties["title"]; title != nil && title.(*notionapi.TitleProperty).Title[0].PlainText == "Notes" {
+			notes = p
+		}
+	}
+	// The paragraph and the block of the sub-page.
+	if notes == nil || len(notes.Blocks) != 2 || len(notes.Comments) != 1 {
+		t.Fatalf("Backup() notes = %+v, want 2 blocks and a comment", notes)
+	}
+
+	target := notionapitest.NewServer()
+	defer target.Close()
+	client = target.Client()
+	result, err := notionapi.Restore(ctx, client, archive, notionapi.RestoreOptions{Parent: target.RootPageID()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dbID := result.IDs[archive.Databases[0].Database.ID.String()]
+	if dbID == "" {
+		t.Fatal("Restore() did not restore the database")
+	}
+	tasks, err := client.Database.QueryAll(ctx, notionapi.DatabaseID(dbID), nil).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]notionapi.Page{}
+	for _, p := range tasks {
+		byName[p.Properties["Name"].(*notionapi.TitleProperty).Title[0].PlainText] = p
+	}
+	if len(byName) != 2 {
+		t.Fatalf("restored tasks = %v, want Design and Build", byName)
+	}
+	blockedBy := byName["Build"].Properties["Blocked by"].(*notionapi.RelationProperty).Relation
+	if len(blockedBy) != 1 || blockedBy[0].ID.String() != byName["Design"].ID.String() {
+		t.Errorf("restored relation = %+v, want the restored Design task %s", blockedBy, byName["Design"].ID)
+	}
+	if tags := byName["Design"].Properties["Tags"].(*notionapi.MultiSelectProperty).MultiSelect; len(tags) != 1 || tags[0].Name != "bug" {
+		t.Errorf("restored tags = %+v", tags)
+	}
+
+	notesID := notionapi.BlockID(result.IDs[notes.Page.ID.String()])
+	blocks, err := client.Block.GetTree(ctx, notesID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 2 || blocks[1].GetType() != notionapi.BlockTypeChildPage {
+		t.Fatalf("restored notes blocks = %+v, want a paragraph and the sub-page", blocks)
+	}
+	intro, ok := blocks[0].(*notionapi.ParagraphBlock)
+	if !ok || len(intro.Paragraph.Children) != 1 || intro.Paragraph.Children[0].GetRichTextString() != "Details" {
+		t.Errorf("restored notes blocks = %+v, want a paragraph with a child", blocks)
+	}
+	sub, err := client.Page.Get(ctx, notionapi.PageID(result.IDs[childPageID(t, archive, "Sub-page")]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.Parent.PageID.String() != notesID.String() {
+		t.Errorf("restored sub-page parent = %+v, want the restored notes %s", sub.Parent, notesID)
+	}
+
+	comments, err := client.Comment.GetAll(ctx, notesID).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 1 || comments[0].RichText[0].PlainText != "Looks good" {
+		t.Errorf("restored comments = %+v", comments)
+	}
+}
+
+func childPageID(t *testing.T, archive *notionapi.BackupArchive, title string) string {
+	for _, p := range archive.Pages {
+		if prop, ok := p.Page.Properties["title"].(*notionapi.TitleProperty); ok && len(prop.Title) > 0 && prop.Title[0].PlainText == title {
+			return p.Page.ID.String()
+		}
+	}
+	t.Fatalf("no page %q in the backup", title)
+	return ""
+}
+
+func TestOpenBackup(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := notionapi.OpenBackup(dir); err == nil {
+		t.Error("OpenBackup() of an incomplete backup error = nil")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(`{"version": 99}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := notionapi.OpenBackup(dir); err == nil {
+		t.Error("OpenBackup() of a future version error = nil")
+	}
+}
+
+func TestRestore_ColumnWithMissingFile(t *testing.T) {
+	ctx := context.Background()
+	srv := notionapitest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	basic := func(id notionapi.BlockID, t notionapi.BlockType) notionapi.BasicBlock {
+		return notionapi.BasicBlock{Object: notionapi.ObjectTypeBlock, ID: id, Type: t}
+	}
+	intro := paragraph("Intro", paragraph("Details"))
+	intro.ID = "intro"
+	archive := &notionapi.BackupArchive{
+		Dir: t.TempDir(),
+		Pages: []*notionapi.PageBackup{{
+			Page: &notionapi.Page{
+				Object:     notionapi.ObjectTypePage,
+				ID:         "notes",
+				Parent:     notionapi.Parent{Type: notionapi.ParentTypeWorkspace, Workspace: true},
+				Properties: notionapi.Properties{"title": &notionapi.TitleProperty{Type: notionapi.PropertyTypeTitle, Title: richText("Notes")}},
+			},
+			Blocks: notionapi.Blocks{
+				&notionapi.ColumnListBlock{BasicBlock: basic("columns", notionapi.BlockTypeColumnList), ColumnList: notionapi.ColumnList{Children: notionapi.Blocks{
+					&notionapi.ColumnBlock{BasicBlock: basic("left", notionapi.BlockTypeColumn), Column: notionapi.Column{Children: notionapi.Blocks{
+						// The file was not downloaded, so the block is left out.
+						&notionapi.FileBlock{BasicBlock: basic("file", notionapi.BlockTypeFile), File: notionapi.BlockFile{
+							Type: notionapi.FileTypeFile,
+							File: &notionapi.FileObject{URL: "https://files.example.com/report.pdf"},
+						}},
+						intro,
+					}}},
+					&notionapi.ColumnBlock{BasicBlock: basic("right", notionapi.BlockTypeColumn), Column: notionapi.Column{Children: notionapi.Blocks{
+						paragraph("Right"),
+					}}},
+				}}},
+			},
+		}},
+	}
+
+	result, err := notionapi.Restore(ctx, client, archive, notionapi.RestoreOptions{Parent: srv.RootPageID()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := result.IDs["file"]; ok {
+		t.Errorf("Restore() IDs = %v, want no copy of the file block", result.IDs)
+	}
+	blocks, err := client.Block.GetTree(ctx, notionapi.BlockID(result.IDs["notes"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 1 {
+		t.Fatalf("restored blocks = %+v, want a column list", blocks)
+	}
+	left := blocks[0].(*notionapi.ColumnListBlock).ColumnList.Children[0].(*notionapi.ColumnBlock).Column.Children
+	if len(left) != 1 || left[0].GetID().String() != result.IDs["intro"] {
+		t.Fatalf("restored left column = %+v, want the copy %s of the intro", left, result.IDs["intro"])
+	}
+	details := left[0].(*notionapi.ParagraphBlock).Paragraph.Children
+	if len(details) != 1 || details[0].GetRichTextString() != "Details" {
+		t.Errorf("restored intro children = %+v, want the details", details)
+	}
+}
+
+func TestRestore_PageOfMissingDatabase(t *testing.T) {
+	ctx := context.Background()
+	srv := notionapitest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	notes := &notionapi.PageBackup{Page: &notionapi.Page{
+		Object:     notionapi.ObjectTypePage,
+		ID:         "notes",
+		Parent:     notionapi.Parent{Type: notionapi.ParentTypeWorkspace, Workspace: true},
+		Properties: notionapi.Properties{"title": &notionapi.TitleProperty{Type: notionapi.PropertyTypeTitle, Title: richText("Notes")}},
+	}}
+	mention := paragraph("See ")
+	mention.ID = "mention"
+	mention.Paragraph.RichText = append(mention.Paragraph.RichText, notionapi.RichText{
+		Type:    "mention",
+		Mention: &notionapi.Mention{Type: notionapi.MentionTypePage, Page: &notionapi.PageMention{ID: "notes"}},
+	})
+	task := &notionapi.PageBackup{
+		Page: &notionapi.Page{
+			Object: notionapi.ObjectTypePage,
+			ID:     "task",
+			// The database is not in the backup.
+			Parent: notionapi.Parent{Type: notionapi.ParentTypeDatabaseID, DatabaseID: "tasks"},
+			Properties: notionapi.Properties{
+				"Name":   &notionapi.TitleProperty{Type: notionapi.PropertyTypeTitle, Title: richText("Write docs")},
+				"Points": &notionapi.NumberProperty{Type: notionapi.PropertyTypeNumber, Number: 2},
+			},
+		},
+		Blocks: notionapi.Blocks{
+			&notionapi.LinkToPageBlock{
+				BasicBlock: notionapi.BasicBlock{Object: notionapi.ObjectTypeBlock, ID: "link", Type: notionapi.BlockTypeLinkToPage},
+				LinkToPage: notionapi.LinkToPage{Type: "page_id", PageID: "notes"},
+			},
+			mention,
+		},
+	}
+	archive := &notionapi.BackupArchive{Dir: t.TempDir(), Pages: []*notionapi.PageBackup{notes, task}}
+
+	result, err := notionapi.Restore(ctx, client, archive, notionapi.RestoreOptions{Parent: srv.RootPageID()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := client.Page.Get(ctx, notionapi.PageID(result.IDs["task"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title, ok := page.Properties["title"].(*notionapi.TitleProperty); !ok || title.Title[0].PlainText != "Write docs" {
+		t.Errorf("restored task properties = %+v, want the title", page.Properties)
+	}
+	want := []string{
+		"block link links to notes, which is not remapped to its copy",
+		"block mention links to notes, which is not remapped to its copy",
+	}
+	if !reflect.DeepEqual(result.Warnings, want) {
+		t.Errorf("Restore() warnings = %q, want %q", result.Warnings, want)
+	}
+}
@@ -21,6 +21,8 @@ type BlockService interface {
 	GetChildren(context.Context, BlockID, *Pagination) (*GetChildrenResponse, error)
 	Update(ctx context.Context, id BlockID, request *BlockUpdateRequest) (Block, error)
 	Delete(context.Context, BlockID) (Block, error)
+	GetAllChildren(context.Context, BlockID, ...IteratorOption) *BlockIterator
+	GetTree(context.Context, BlockID, ...TreeOption) (Blocks, error)
 }
 
 type BlockClient struct {
@@ -116,6 +118,21 @@ func (bc *BlockClient) GetChildren(ctx context.Context, id BlockID, pagination *
 	return response, nil
 }
 
+// GetAllChildren returns an iterator over all the direct children of the block,
+// following next_cursor until every page of results has been read.
+func (bc *BlockClient) GetAllChildren(ctx context.Context, id BlockID, opts ...IteratorOption) *BlockIterator {
+	it := &BlockIterator{}
+	it.cursorIterator = newCursorIterator(ctx, "", opts, func(ctx context.Context, cursor Cursor, pageSize int) (int, Cursor, bool, error) {
+		res, err := bc.GetChildren(ctx, id, &Pagination{StartCursor: cursor, PageSize: pageSize})
+		if err != nil {
+			return 0, "", false, err
+		}
+		it.results = res.Results
+		return len(res.Results), Cursor(res.NextCursor), res.HasMore, nil
+	})
+	return it
+}
+
 type GetChildrenResponse struct {
 	Object     ObjectType `json:"object"`
 	Results    Blocks     `json:"results"`
@@ -529,10 +546,11 @@ type ImageBlock struct {
 }
 
 type Image struct {
-	Caption  []RichText  `json:"caption,omitempty"`
-	Type     FileType    `json:"type,omitempty"`
-	File     *FileObject `json:"file,omitempty"`
-	External *FileObject `json:"external,omitempty"`
+	Caption    []RichText        `json:"caption,omitempty"`
+	Type       FileType          `json:"type,omitempty"`
+	File       *FileObject       `json:"file,omitempty"`
+	External   *FileObject       `json:"external,omitempty"`
+	FileUpload *FileUploadObject `json:"file_upload,omitempty"`
 }
 
 // GetURL returns the external or internal URL depending on the image type.
@@ -552,10 +570,11 @@ type AudioBlock struct {
 }
 
 type Audio struct {
-	Caption  []RichText  `json:"caption,omitempty"`
-	Type     FileType    `json:"type"`
-	File     *FileObject `json:"file,omitempty"`
-	External *FileObject `json:"external,omitempty"`
+	Caption    []RichText        `json:"caption,omitempty"`
+	Type       FileType          `json:"type"`
+	File       *FileObject       `json:"file,omitempty"`
+	External   *FileObject       `json:"external,omitempty"`
+	FileUpload *FileUploadObject `json:"file_upload,omitempty"`
 }
 
 // GetURL returns the external or internal URL depending on the image type.
@@ -586,10 +605,22 @@ type VideoBlock struct {
 }
 
 type Video struct {
-	Caption  []RichText  `json:"caption,omitempty"`
-	Type     FileType    `json:"type"`
-	File     *FileObject `json:"file,omitempty"`
-	External *FileObject `json:"external,omitempty"`
+	Caption    []RichText        `json:"caption,omitempty"`
+	Type       FileType          `json:"type"`
+	File       *FileObject       `json:"file,omitempty"`
+	External   *FileObject       `json:"external,omitempty"`
+	FileUpload *FileUploadObject `json:"file_upload,omitempty"`
+}
+
+// GetURL returns the external or internal URL depending on the video type.
+func (i Video) GetURL() string {
+	if i.File != nil {
+		return i.File.URL
+	}
+	if i.External != nil {
+		return i.External.URL
+	}
+	return ""
 }
 
 type FileBlock struct {
@@ -598,10 +629,12 @@ type FileBlock struct {
 }
 
 type BlockFile struct {
-	Caption  []RichText  `json:"caption,omitempty"`
-	Type     FileType    `json:"type"`
-	File     *FileObject `json:"file,omitempty"`
-	External *FileObject `json:"external,omitempty"`
+	Caption    []RichText        `json:"caption,omitempty"`
+	Name       string            `json:"name,omitempty"`
+	Type       FileType          `json:"type"`
+	File       *FileObject       `json:"file,omitempty"`
+	External   *FileObject       `json:"external,omitempty"`
+	FileUpload *FileUploadObject `json:"file_upload,omitempty"`
 }
 
 type PdfBlock struct {
@@ -610,10 +643,11 @@ type PdfBlock struct {
 }
 
 type Pdf struct {
-	Caption  []RichText  `json:"caption,omitempty"`
-	Type     FileType    `json:"type,omitempty"`
-	File     *FileObject `json:"file,omitempty"`
-	External *FileObject `json:"external,omitempty"`
+	Caption    []RichText        `json:"caption,omitempty"`
+	Type       FileType          `json:"type,omitempty"`
+	File       *FileObject       `json:"file,omitempty"`
+	External   *FileObject       `json:"external,omitempty"`
+	FileUpload *FileUploadObject `json:"file_upload,omitempty"`
 }
 
 type BookmarkBlock struct {
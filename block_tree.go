@@ -0,0 +1,206 @@
+package notionapi
+
+import (
+	"context"
+	"sync"
+)
+
+const defaultTreeWorkers = 3
+
+// TreeOption to configure BlockClient.GetTree.
+type TreeOption func(*treeConfig)
+
+type treeConfig struct {
+	workers  int
+	maxDepth int
+}
+
+// WithWorkers sets the maximum number of children listings fetched in
+// parallel. Defaults to 3, which matches the average rate limit of the API.
+func WithWorkers(workers int) TreeOption {
+	return func(c *treeConfig) {
+		c.workers = workers
+	}
+}
+
+// WithMaxDepth limits the number of levels of descendants fetched. A depth of
+// 1 only fetches the direct children. Zero, the default, means no limit.
+func WithMaxDepth(depth int) TreeOption {
+	return func(c *treeConfig) {
+		c.maxDepth = depth
+	}
+}
+
+// GetTree fetches all the descendants of a page or block and returns its
+// direct children with the Children field of every nested block populated.
+//
+// Child pages and child databases are not descended into, as their content
+// belongs to another page.
+func (bc *BlockClient) GetTree(ctx context.Context, id BlockID, opts ...TreeOption) (Blocks, error) {
+	cfg := treeConfig{workers: defaultTreeWorkers}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.workers < 1 {
+		cfg.workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	f := &treeFetcher{
+		client: bc,
+		cfg:    cfg,
+		sem:    make(chan struct{}, cfg.workers),
+		cancel: cancel,
+	}
+
+	root, err := f.children(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	f.expand(ctx, root, 1)
+	f.wg.Wait()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	return root, nil
+}
+
+type treeFetcher struct {
+	client *BlockClient
+	cfg    treeConfig
+	sem    chan struct{}
+	cancel context.CancelFunc
+
+	wg  sync.WaitGroup
+	mu  sync.Mutex
+	err error
+}
+
+func (f *treeFetcher) children(ctx context.Context, id BlockID) (Blocks, error) {
+	select {
+	case f.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-f.sem }()
+
+	return f.client.GetAllChildren(ctx, id, WithPageSize(100)).All()
+}
+
+// expand fetches the children of every block of the given level in the
+// background.
+func (f *treeFetcher) expand(ctx context.Context, blocks Blocks, depth int) {
+	if f.cfg.maxDepth > 0 && depth >= f.cfg.maxDepth {
+		return
+	}
+
+	for _, b := range blocks {
+		if !b.GetHasChildren() {
+			continue
+		}
+		switch b.GetType() {
+		case BlockTypeChildPage, BlockTypeChildDatabase:
+			continue
+		}
+
+		f.wg.Add(1)
+		go func(b Block) {
+			defer f.wg.Done()
+
+			children, err := f.children(ctx, b.GetID())
+			if err != nil {
+				f.fail(err)
+				return
+			}
+			setBlockChildren(b, children)
+			f.expand(ctx, children, depth+1)
+		}(b)
+	}
+}
+
+func (f *treeFetcher) fail(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err == nil {
+		f.err = err
+		f.cancel()
+	}
+}
+
+// setBlockChildren stores children in the Children field of the block, if its
+// type has one.
+func setBlockChildren(b Block, children Blocks) {
+	switch b := b.(type) {
+	case *ParagraphBlock:
+		b.Paragraph.Children = children
+	case *Heading1Block:
+		b.Heading1.Children = children
+	case *Heading2Block:
+		b.Heading2.Children = children
+	case *Heading3Block:
+		b.Heading3.Children = children
+	case *CalloutBlock:
+		b.Callout.Children = children
+	case *QuoteBlock:
+		b.Quote.Children = children
+	case *TableBlock:
+		b.Table.Children = children
+	case *BulletedListItemBlock:
+		b.BulletedListItem.Children = children
+	case *NumberedListItemBlock:
+		b.NumberedListItem.Children = children
+	case *ToDoBlock:
+		b.ToDo.Children = children
+	case *ToggleBlock:
+		b.Toggle.Children = children
+	case *ColumnBlock:
+		b.Column.Children = children
+	case *ColumnListBlock:
+		b.ColumnList.Children = children
+	case *TemplateBlock:
+		b.Template.Children = children
+	case *SyncedBlock:
+		b.SyncedBlock.Children = children
+	}
+}
+
+// blockChildren returns the content of the Children field of the block, if
+// its type has one.
+func blockChildren(b Block) Blocks {
+	switch b := b.(type) {
+	case *ParagraphBlock:
+		return b.Paragraph.Children
+	case *Heading1Block:
+		return b.Heading1.Children
+	case *Heading2Block:
+		return b.Heading2.Children
+	case *Heading3Block:
+		return b.Heading3.Children
+	case *CalloutBlock:
+		return b.Callout.Children
+	case *QuoteBlock:
+		return b.Quote.Children
+	case *TableBlock:
+		return b.Table.Children
+	case *BulletedListItemBlock:
+		return b.BulletedListItem.Children
+	case *NumberedListItemBlock:
+		return b.NumberedListItem.Children
+	case *ToDoBlock:
+		return b.ToDo.Children
+	case *ToggleBlock:
+		return b.Toggle.Children
+	case *ColumnBlock:
+		return b.Column.Children
+	case *ColumnListBlock:
+		return b.ColumnList.Children
+	case *TemplateBlock:
+		return b.Template.Children
+	case *SyncedBlock:
+		return b.SyncedBlock.Children
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package notionapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+)
+
+func blockJSON(id, blockType string, hasChildren bool) map[string]interface{} {
+	return map[string]interface{}{
+		"object":       "block",
+		"id":           id,
+		"type":         blockType,
+		"has_children": hasChildren,
+		blockType:      map[string]interface{}{"rich_text": []interface{}{}},
+	}
+}
+
+// newTreeClient returns *http.Client which serves the children of the blocks
+// from the given map and counts the requests made for each block.
+func newTreeClient(t *testing.T, tree map[string][]map[string]interface{}, calls *sync.Map, inFlight, maxInFlight *int32) *http.Client {
+	return newTestClient(func(req *http.Request) *http.Response {
+		n := atomic.AddInt32(inFlight, 1)
+		defer atomic.AddInt32(inFlight, -1)
+		for {
+			max := atomic.LoadInt32(maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		id := strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/v1/blocks/"), "/children")
+		calls.Store(id, true)
+
+		children, ok := tree[id]
+		if !ok {
+			t.Errorf("unexpected request for children of %s", id)
+		}
+		b, err := json.Marshal(map[string]interface{}{
+			"object":   "list",
+			"results":  children,
+			"has_more": false,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			Header:     make(http.Header),
+		}
+	})
+}
+
+func TestBlockClient_GetTree(t *testing.T) {
+	tree := map[string][]map[string]interface{}{
+		"page": {
+			blockJSON("toggle", "toggle", true),
+			blockJSON("columns", "column_list", true),
+			blockJSON("child", "child_page", true),
+			blockJSON("paragraph", "paragraph", false),
+		},
+		"toggle": {
+			blockJSON("nested", "bulleted_list_item", true),
+		},
+		"nested": {
+			blockJSON("deep", "paragraph", false),
+		},
+		"columns": {
+			blockJSON("column_1", "column", true),
+			blockJSON("column_2", "column", true),
+		},
+		"column_1": {blockJSON("left", "paragraph", false)},
+		"column_2": {blockJSON("right", "paragraph", false)},
+	}
+
+	t.Run("fetches the whole tree", func(t *testing.T) {
+		var calls sync.Map
+		var inFlight, maxInFlight int32
+		c := newTreeClient(t, tree, &calls, &inFlight, &maxInFlight)
+		client := notionapi.NewClient("some_token", notionapi.WithHTTPClient(c))
+
+		got, err := client.Block.GetTree(context.Background(), "page", notionapi.WithWorkers(2))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 4 {
+			t.Fatalf("GetTree() got %d blocks, want 4", len(got))
+		}
+
+		toggle := got[0].(*notionapi.ToggleBlock)
+		if len(toggle.Toggle.Children) != 1 {
+			t.Fatalf("toggle has %d children, want 1", len(toggle.Toggle.Children))
+		}
+		item := toggle.Toggle.Children[0].(*notionapi.BulletedListItemBlock)
+		if len(item.BulletedListItem.Children) != 1 || item.BulletedListItem.Children[0].GetID() != "deep" {
+			t.Errorf("nested list item children = %v", item.BulletedListItem.Children)
+		}
+
+		columns := got[1].(*notionapi.ColumnListBlock)
+		if len(columns.ColumnList.Children) != 2 {
+			t.Fatalf("column list has %d children, want 2", len(columns.ColumnList.Children))
+		}
+		right := columns.ColumnList.Children[1].(*notionapi.ColumnBlock)
+		if len(right.Column.Children) != 1 || right.Column.Children[0].GetID() != "right" {
+			t.Errorf("column children = %v", right.Column.Children)
+		}
+
+		if _, ok := calls.Load("child"); ok {
+			t.Errorf("GetTree() descended into a child page")
+		}
+		if maxInFlight > 2 {
+			t.Errorf("GetTree() made %d parallel requests, want at most 2", maxInFlight)
+		}
+	})
+
+	t.Run("stops at max depth", func(t *testing.T) {
+		var calls sync.Map
+		var inFlight, maxInFlight int32
+		c := newTreeClient(t, tree, &calls, &inFlight, &maxInFlight)
+		client := notionapi.NewClient("some_token", notionapi.WithHTTPClient(c))
+
+		got, err := client.Block.GetTree(context.Background(), "page", notionapi.WithMaxDepth(2))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		toggle := got[0].(*notionapi.ToggleBlock)
+		item := toggle.Toggle.Children[0].(*notionapi.BulletedListItemBlock)
+		if item.BulletedListItem.Children != nil {
+			t.Errorf("GetTree() fetched children beyond max depth")
+		}
+		if _, ok := calls.Load("nested"); ok {
+			t.Errorf("GetTree() requested children beyond max depth")
+		}
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -24,7 +25,7 @@ const (
 
 type Token string
 
-type errJsonDecodeFunc func(data []byte) error
+type errJsonDecodeFunc func(res *http.Response, data []byte) error
 
 func (it Token) String() string {
 	return string(it)
@@ -33,13 +34,25 @@ func (it Token) String() string {
 // ClientOption to configure API client
 type ClientOption func(*Client)
 
+// Handler sends a request to the Notion API and returns its response.
+type Handler func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the Handler sending requests to the Notion API, to run code
+// before and after every request, for example for logging, metrics or custom
+// authentication. It is called for each attempt when requests are retried.
+type Middleware func(next Handler) Handler
+
 type Client struct {
 	httpClient    *http.Client
 	baseUrl       *url.URL
+	baseURLErr    error
 	apiVersion    string
 	notionVersion string
 
-	maxRetries int
+	maxRetries  int
+	retryPolicy RetryPolicy
+	rateLimiter *RateLimiter
+	middlewares []Middleware
 
 	Token Token
 
@@ -54,6 +67,7 @@ type Client struct {
 	Search         SearchService
 	Comment        CommentService
 	Authentication AuthenticationService
+	FileUpload     FileUploadService
 }
 
 func NewClient(token Token, opts ...ClientOption) *Client {
@@ -77,6 +91,7 @@ func NewClient(token Token, opts ...ClientOption) *Client {
 	c.Search = &SearchClient{apiClient: c}
 	c.Comment = &CommentClient{apiClient: c}
 	c.Authentication = &AuthenticationClient{apiClient: c}
+	c.FileUpload = &FileUploadClient{apiClient: c}
 
 	for _, opt := range opts {
 		opt(c)
@@ -92,6 +107,71 @@ func WithHTTPClient(client *http.Client) ClientOption {
 	}
 }
 
+// WithBaseURL overrides the URL of the Notion API, for example to send requests
+// through a proxy or to a fake server in tests. The API version is appended to
+// the path of the URL. If the URL is not a valid absolute URL, requests fail
+// with an error.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		u, err := url.Parse(baseURL)
+		if err == nil && (u.Scheme == "" || u.Host == "") {
+			err = fmt.Errorf("%q is not an absolute URL", baseURL)
+		}
+		if err != nil {
+			c.baseURLErr = fmt.Errorf("notionapi: invalid WithBaseURL option: %w", err)
+			return
+		}
+		if !strings.HasSuffix(u.Path, "/") {
+			u.Path += "/"
+		}
+		c.baseUrl = u
+		c.baseURLErr = nil
+	}
+}
+
+// WithMiddleware adds middlewares around the requests sent to the API. The
+// first middleware added is the outermost one, it sees requests first and
+// responses last.
+func WithMiddleware(middlewares ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// WithRequestHook adds a middleware calling hook with every request before it
+// is sent, after the Authorization and Notion-Version headers are set. The
+// hook may modify the request, and the request is not sent if it returns an
+// error.
+func WithRequestHook(hook func(req *http.Request) error) ClientOption {
+	return WithMiddleware(func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			if err := hook(req); err != nil {
+				return nil, err
+			}
+			return next(req)
+		}
+	})
+}
+
+// WithResponseHook adds a middleware calling hook with every response
+// received, whatever its status code. If the hook returns an error, the
+// response body is closed and the error is returned by the request.
+func WithResponseHook(hook func(res *http.Response) error) ClientOption {
+	return WithMiddleware(func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			res, err := next(req)
+			if err != nil {
+				return nil, err
+			}
+			if err := hook(res); err != nil {
+				res.Body.Close()
+				return nil, err
+			}
+			return res, nil
+		}
+	})
+}
+
 // WithVersion overrides the Notion API version
 func WithVersion(version string) ClientOption {
 	return func(c *Client) {
@@ -99,13 +179,31 @@ func WithVersion(version string) ClientOption {
 	}
 }
 
-// WithRetry overrides the default number of max retry attempts on 429 errors
+// WithRetry overrides the default maximum number of attempts of requests,
+// including the first one, when using the default retry policy.
 func WithRetry(retries int) ClientOption {
 	return func(c *Client) {
 		c.maxRetries = retries
 	}
 }
 
+// WithRetryPolicy overrides the policy deciding which failed requests are
+// retried and when. See BackoffPolicy for the default policy.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithRateLimiter limits the rate of the requests sent by the client, including
+// retries. The limiter may be shared with other clients, see
+// SharedRateLimiter.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.rateLimiter = limiter
+	}
+}
+
 // WithOAuthAppCredentials sets the OAuth app ID and secret to use when fetching a token from Notion.
 func WithOAuthAppCredentials(id, secret string) ClientOption {
 	return func(c *Client) {
@@ -119,18 +217,23 @@ func (c *Client) request(ctx context.Context, method string, urlStr string, quer
 }
 
 func (c *Client) requestImpl(ctx context.Context, method string, urlStr string, queryParams map[string]string, requestBody interface{}, basicAuth bool, errDecoder errJsonDecodeFunc) (*http.Response, error) {
+	if c.baseURLErr != nil {
+		return nil, c.baseURLErr
+	}
 	u, err := c.baseUrl.Parse(fmt.Sprintf("%s/%s", c.apiVersion, urlStr))
 	if err != nil {
 		return nil, err
 	}
 
-	var buf io.ReadWriter
-	if requestBody != nil && !reflect.ValueOf(requestBody).IsNil() {
-		body, err := json.Marshal(requestBody)
+	var body []byte
+	contentType := "application/json"
+	if raw, ok := requestBody.(*rawBody); ok {
+		body, contentType = raw.data, raw.contentType
+	} else if requestBody != nil && !reflect.ValueOf(requestBody).IsNil() {
+		body, err = json.Marshal(requestBody)
 		if err != nil {
 			return nil, err
 		}
-		buf = bytes.NewBuffer(body)
 	}
 
 	if len(queryParams) > 0 {
@@ -140,73 +243,96 @@ func (c *Client) requestImpl(ctx context.Context, method string, urlStr string,
 		}
 		u.RawQuery = q.Encode()
 	}
-	req, err := http.NewRequest(method, u.String(), buf)
-	if err != nil {
-		return nil, err
-	}
 
-	if basicAuth {
-		cred := base64.StdEncoding.EncodeToString([]byte(c.oauthID + ":" + c.oauthSecret))
-		req.Header.Add("Authorization", fmt.Sprintf("Basic %s", cred))
-	} else {
-		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.Token.String()))
+	do := c.handler()
+	policy := c.retryPolicy
+	if policy == nil {
+		policy = &BackoffPolicy{MaxAttempts: c.maxRetries}
 	}
-	req.Header.Add("Notion-Version", c.notionVersion)
-	req.Header.Add("Content-Type", "application/json")
-
-	failedAttempts := 0
-	var res *http.Response
-	for {
-		var err error
-		res, err = c.httpClient.Do(req.WithContext(ctx))
+	for attempt := 1; ; attempt++ {
+		// The request is built for every attempt so that its body can be
+		// read again.
+		req, err := c.newRequest(ctx, method, u.String(), body, contentType, basicAuth)
 		if err != nil {
 			return nil, err
 		}
 
-		if res.StatusCode != http.StatusTooManyRequests {
-			break
+		if c.rateLimiter != nil {
+			if err := c.rateLimiter.Wait(ctx); err != nil {
+				return nil, err
+			}
 		}
-
-		failedAttempts++
-		if failedAttempts == c.maxRetries {
-			return nil, &RateLimitedError{Message: fmt.Sprintf("Retry request with 429 response failed after %d retries", failedAttempts)}
+		res, err := do(req)
+		if err == nil && c.rateLimiter != nil {
+			c.rateLimiter.observe(res)
+		}
+		if err == nil && res.StatusCode == http.StatusOK {
+			return res, nil
 		}
-		// https://developers.notion.com/reference/request-limits#rate-limits
-		retryAfterHeader := res.Header["Retry-After"]
-		if len(retryAfterHeader) == 0 {
-			return nil, &RateLimitedError{Message: "Retry-After header missing from Notion API response headers for 429 response"}
+		if err == nil {
+			data, readErr := ioutil.ReadAll(res.Body)
+			res.Body.Close()
+			if readErr != nil {
+				return nil, readErr
+			}
+			err = errDecoder(res, data)
+			res.Body = ioutil.NopCloser(bytes.NewReader(data))
 		}
-		retryAfter := retryAfterHeader[0]
 
-		waitSeconds, err := strconv.Atoi(retryAfter)
-		if err != nil {
-			break // should not happen
+		wait, retry := policy.Retry(attempt, req, res, err)
+		if !retry {
+			if res != nil && res.StatusCode == http.StatusTooManyRequests {
+				return nil, &RateLimitedError{Message: fmt.Sprintf("Retry request with 429 response failed after %d retries", attempt), Err: err}
+			}
+			return nil, err
 		}
+
+		timer := time.NewTimer(wait)
 		select {
 		case <-ctx.Done():
+			timer.Stop()
 			return nil, ctx.Err()
-		case <-time.After(time.Duration(waitSeconds) * time.Second):
+		case <-timer.C:
 		}
 	}
+}
 
-	if res.StatusCode != http.StatusOK {
-		data, err := ioutil.ReadAll(res.Body)
-		if err != nil {
-			return nil, err
-		}
-		return nil, errDecoder(data)
+// rawBody is a request body sent as is rather than encoded as JSON, such as a
+// multipart form.
+type rawBody struct {
+	contentType string
+	data        []byte
+}
+
+func (c *Client) newRequest(ctx context.Context, method, u string, body []byte, contentType string, basicAuth bool) (*http.Request, error) {
+	var buf io.Reader
+	if body != nil {
+		buf = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, u, buf)
+	if err != nil {
+		return nil, err
 	}
 
-	return res, nil
+	if basicAuth {
+		cred := base64.StdEncoding.EncodeToString([]byte(c.oauthID + ":" + c.oauthSecret))
+		req.Header.Add("Authorization", fmt.Sprintf("Basic %s", cred))
+	} else {
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.Token.String()))
+	}
+	req.Header.Add("Notion-Version", c.notionVersion)
+	req.Header.Add("Content-Type", contentType)
+	return req.WithContext(ctx), nil
 }
 
-func decodeClientError(data []byte) error {
-	var apiErr Error
-	err := json.Unmarshal(data, &apiErr)
-	if err != nil {
-		return err
+// handler returns the Handler sending requests through the middlewares of the
+// client.
+func (c *Client) handler() Handler {
+	h := Handler(c.httpClient.Do)
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		h = c.middlewares[i](h)
 	}
-	return &apiErr
+	return h
 }
 
 type Pagination struct {
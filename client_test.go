@@ -2,10 +2,13 @@ package notionapi_test
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/jomei/notionapi"
@@ -115,3 +118,81 @@ func TestBasicAuthHeader(t *testing.T) {
 	client := notionapi.NewClient("some_token", opts...)
 	_, _ = client.Authentication.CreateToken(context.Background(), &notionapi.TokenCreateRequest{})
 }
+
+func TestMiddleware(t *testing.T) {
+	var traceHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/notion/v1/users/me" {
+			t.Errorf("request path = %s, want /notion/v1/users/me", r.URL.Path)
+		}
+		traceHeader = r.Header.Get("X-Trace")
+		w.Header().Set("X-Request-Id", "42")
+		_, _ = w.Write([]byte(`{"object":"user","id":"some_id","type":"bot"}`))
+	}))
+	defer srv.Close()
+
+	var calls []string
+	trace := func(name string) notionapi.Middleware {
+		return func(next notionapi.Handler) notionapi.Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" before")
+				res, err := next(req)
+				calls = append(calls, name+" after")
+				return res, err
+			}
+		}
+	}
+
+	var requestID string
+	client := notionapi.NewClient("some_token",
+		notionapi.WithBaseURL(srv.URL+"/notion"),
+		notionapi.WithMiddleware(trace("outer"), trace("inner")),
+		notionapi.WithRequestHook(func(req *http.Request) error {
+			req.Header.Set("X-Trace", "abc")
+			return nil
+		}),
+		notionapi.WithResponseHook(func(res *http.Response) error {
+			requestID = res.Header.Get("X-Request-Id")
+			return nil
+		}),
+	)
+	user, err := client.User.Me(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != "some_id" {
+		t.Errorf("Me() got %+v", user)
+	}
+	if traceHeader != "abc" {
+		t.Errorf("X-Trace header = %q, want abc", traceHeader)
+	}
+	if requestID != "42" {
+		t.Errorf("response hook got request id %q, want 42", requestID)
+	}
+	want := []string{"outer before", "inner before", "inner after", "outer after"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("middleware calls = %v, want %v", calls, want)
+	}
+
+	t.Run("hook errors abort the request", func(t *testing.T) {
+		hookErr := errors.New("denied")
+		client := notionapi.NewClient("some_token",
+			notionapi.WithBaseURL(srv.URL+"/notion/"),
+			notionapi.WithResponseHook(func(res *http.Response) error {
+				return hookErr
+			}),
+		)
+		if _, err := client.User.Me(context.Background()); !errors.Is(err, hookErr) {
+			t.Errorf("Me() error = %v, want %v", err, hookErr)
+		}
+	})
+
+	t.Run("invalid base URLs fail requests", func(t *testing.T) {
+		for _, baseURL := range []string{"http://[::1", "localhost:8080"} {
+			client := notionapi.NewClient("some_token", notionapi.WithBaseURL(baseURL))
+			if _, err := client.User.Me(context.Background()); err == nil || !strings.Contains(err.Error(), "WithBaseURL") {
+				t.Errorf("Me() with base URL %q error = %v, want an invalid WithBaseURL option", baseURL, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,131 @@
+// Command notionbackup backs up the pages and databases shared with an
+// integration to a directory, and restores them under a page. See
+// notionapi.Backup and notionapi.Restore.
+//
+// Usage:
+//
+//	NOTION_TOKEN=secret_... notionbackup backup [-o dir] [-skip-files] [-external-files] [-skip-block-comments]
+//	NOTION_TOKEN=secret_... notionbackup restore -parent <page id> [-skip-comments] [-skip-files] <dir>
+//
+// Every backup is written to a new directory, named after the time of the
+// backup by default.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jomei/notionapi"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "backup":
+		err = backup(os.Args[2:])
+	case "restore":
+		err = restore(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "notionbackup:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: notionbackup backup [flags] | notionbackup restore -parent <page id> [flags] <dir>")
+	os.Exit(2)
+}
+
+func newClient() (*notionapi.Client, error) {
+	token := os.Getenv("NOTION_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("NOTION_TOKEN is not set")
+	}
+	return notionapi.NewClient(notionapi.Token(token),
+		notionapi.WithRetry(5),
+		notionapi.WithRateLimiter(notionapi.NewRateLimiter(3, 3)),
+	), nil
+}
+
+func backup(args []string) error {
+	flags := flag.NewFlagSet("backup", flag.ExitOnError)
+	out := flags.String("o", "", "directory to write, notion-backup-<time> by default")
+	skipFiles := flags.Bool("skip-files", false, "do not download files")
+	externalFiles := flags.Bool("external-files", false, "also download files hosted outside of Notion")
+	skipBlockComments := flags.Bool("skip-block-comments", false, "only back up the comments of pages")
+	flags.Parse(args)
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	dir := *out
+	if dir == "" {
+		dir = "notion-backup-" + time.Now().UTC().Format("20060102T150405Z")
+	}
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("%s already exists", dir)
+	}
+
+	manifest, err := notionapi.Backup(context.Background(), client, dir, notionapi.BackupOptions{
+		SkipFiles:         *skipFiles,
+		ExternalFiles:     *externalFiles,
+		SkipBlockComments: *skipBlockComments,
+		Progress: func(o notionapi.Object) {
+			fmt.Fprint(os.Stderr, ".")
+		},
+	})
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("backed up %d pages and %d databases to %s\n", len(manifest.Pages), len(manifest.Databases), dir)
+	return nil
+}
+
+func restore(args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ExitOnError)
+	parent := flags.String("parent", "", "ID of the page to restore to")
+	skipComments := flags.Bool("skip-comments", false, "do not restore comments")
+	skipFiles := flags.Bool("skip-files", false, "do not upload files")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("the directory of the backup is required")
+	}
+	if *parent == "" {
+		return fmt.Errorf("-parent is required")
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	archive, err := notionapi.OpenBackup(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	result, err := notionapi.Restore(context.Background(), client, archive, notionapi.RestoreOptions{
+		Parent:       notionapi.PageID(*parent),
+		SkipComments: *skipComments,
+		SkipFiles:    *skipFiles,
+	})
+	if result != nil {
+		for _, w := range result.Warnings {
+			fmt.Fprintln(os.Stderr, "warning:", w)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("restored %d objects\n", len(result.IDs))
+	return nil
+}
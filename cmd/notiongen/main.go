@@ -0,0 +1,60 @@
+// Command notiongen generates a Go struct modelling the pages of a Notion
+// database, with constants for the options of its properties and helpers to
+// filter and query it. See notionapi.GenerateStruct.
+//
+// Usage:
+//
+//	NOTION_TOKEN=secret_... notiongen -database <id> [-package models] [-type Task] [-o task.go]
+//
+// It is meant to be run by go:generate:
+//
+//	//go:generate notiongen -database 0123456789abcdef0123456789abcdef -type Task -o task.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/jomei/notionapi"
+)
+
+func main() {
+	database := flag.String("database", "", "ID of the database")
+	pkg := flag.String("package", "models", "name of the package of the generated file")
+	typeName := flag.String("type", "", "name of the generated struct, derived from the title of the database by default")
+	out := flag.String("o", "", "file to write, standard output by default")
+	flag.Parse()
+
+	if err := run(*database, *pkg, *typeName, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "notiongen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(database, pkg, typeName, out string) error {
+	if database == "" {
+		return fmt.Errorf("-database is required")
+	}
+	token := os.Getenv("NOTION_TOKEN")
+	if token == "" {
+		return fmt.Errorf("NOTION_TOKEN is not set")
+	}
+
+	client := notionapi.NewClient(notionapi.Token(token))
+	db, err := client.Database.Get(context.Background(), notionapi.DatabaseID(database))
+	if err != nil {
+		return err
+	}
+	src, err := notionapi.GenerateStruct(db, notionapi.GenerateOptions{Package: pkg, TypeName: typeName})
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return ioutil.WriteFile(out, src, 0644)
+}
@@ -0,0 +1,383 @@
+package notionapi
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GenerateOptions configures the code written by GenerateStruct.
+type GenerateOptions struct {
+	// Package is the name of the package of the generated file, models by
+	// default.
+	Package string
+	// TypeName is the name of the generated struct, derived from the title of
+	// the database by default.
+	TypeName string
+}
+
+// GenerateStruct writes the source of a Go file modelling the pages of a
+// database, as returned by DatabaseClient.Get. The file declares:
+//   - a constant with the ID of the database,
+//   - a struct with the ID of the page and a field tagged for UnmarshalPage
+//     and MarshalProperties for each property, to be used with Repository,
+//   - a string type and constants for the options of each select,
+//     multi-select and status property,
+//   - a filter type with a method returning the conditions of each property,
+//     and typed methods matching options,
+//   - a function querying the database into structs, and one returning a
+//     Repository of the database.
+//
+// Properties whose names cannot be written in a notion tag, and properties
+// without values such as buttons, are left out.
+func GenerateStruct(db *Database, opts GenerateOptions) ([]byte, error) {
+	if db == nil {
+		return nil, errors.New("notionapi: cannot generate a struct without a database")
+	}
+	g := &generator{db: db, title: richTextString(db.Title), pkg: opts.Package, names: map[string]bool{}}
+	if g.pkg == "" {
+		g.pkg = "models"
+	}
+	typeName := opts.TypeName
+	if typeName == "" {
+		typeName = exportedIdentifier(g.title)
+	}
+	if typeName == "" {
+		typeName = "Page"
+	}
+	if !isExportedIdentifier(typeName) {
+		return nil, fmt.Errorf("notionapi: %q is not an exported Go identifier", typeName)
+	}
+	g.typeName = g.name(typeName)
+
+	src := g.generate()
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("notionapi: generated invalid code: %w\n%s", err, src)
+	}
+	return formatted, nil
+}
+
+type generator struct {
+	db       *Database
+	title    string
+	pkg      string
+	typeName string
+	// names are the identifiers declared in the package.
+	names map[string]bool
+	b     strings.Builder
+}
+
+// generatedField is the field of a property in the generated struct.
+type generatedField struct {
+	property string
+	name     string
+	goType   string
+	tag      string
+	// omitEmpty tells whether zero values are left out of requests, for
+	// properties that cannot be empty strings.
+	omitEmpty bool
+	// builder is the method of PropertyFilterBuilder for the property, and
+	// builderType the type it returns.
+	builder     string
+	builderType string
+	// enum is the type of the options of select, multi-select and status
+	// properties, whose values are options.
+	enum    string
+	options []Option
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.b, format, args...)
+}
+
+// name reserves a unique identifier in the package.
+func (g *generator) name(id string) string {
+	return uniqueName(g.names, id)
+}
+
+func uniqueName(names map[string]bool, id string) string {
+	name := id
+	for i := 2; names[name]; i++ {
+		name = id + strconv.Itoa(i)
+	}
+	names[name] = true
+	return name
+}
+
+func (g *generator) generate() []byte {
+	fields := g.fields()
+	usesTime := false
+	for _, f := range fields {
+		if strings.Contains(f.goType, "time.") {
+			usesTime = true
+		}
+	}
+	dbName := strconv.Quote(g.title)
+	idConst := g.name(g.typeName + "DatabaseID")
+	filterType := g.name(g.typeName + "Filter")
+	queryFunc := g.name("Query" + g.typeName)
+	repoFunc := g.name("New" + g.typeName + "Repository")
+
+	g.printf("// Code generated by notiongen from the %s database. DO NOT EDIT.\n\n", dbName)
+	g.printf("package %s\n\n", g.pkg)
+	g.printf("import (\n\t\"context\"\n")
+	if usesTime {
+		g.printf("\t\"time\"\n")
+	}
+	g.printf("\n\t\"github.com/jomei/notionapi\"\n)\n\n")
+
+	g.printf("// %s is the ID of the %s database.\n", idConst, dbName)
+	g.printf("const %s notionapi.DatabaseID = %q\n\n", idConst, g.db.ID)
+
+	g.printf("// %s is a page of the %s database.\n", g.typeName, dbName)
+	g.printf("type %s struct {\n", g.typeName)
+	g.printf("\tPageID notionapi.PageID `notion:\",id\"`\n")
+	for _, f := range fields {
+		g.printf("\t%s %s %s\n", f.name, f.goType, f.tag)
+	}
+	g.printf("}\n\n")
+
+	for _, f := range fields {
+		if f.enum == "" {
+			continue
+		}
+		g.printf("// %s is an option of the %q property.\n", f.enum, f.property)
+		g.printf("type %s string\n\n", f.enum)
+		if len(f.options) == 0 {
+			continue
+		}
+		g.printf("// Options of the %q property.\nconst (\n", f.property)
+		for _, o := range f.options {
+			g.printf("\t%s %s = %q\n", g.name(f.enum+exportedIdentifier(o.Name)), f.enum, o.Name)
+		}
+		g.printf(")\n\n")
+	}
+
+	g.printf("// %s builds filters on the properties of the %s database.\n", filterType, dbName)
+	g.printf("type %s struct{}\n\n", filterType)
+	methods := map[string]bool{}
+	for _, f := range fields {
+		methods[f.name] = true
+	}
+	for _, f := range fields {
+		prop := fmt.Sprintf("notionapi.Prop(%q).%s()", f.property, f.builder)
+		g.printf("// %s returns the conditions of the %q property.\n", f.name, f.property)
+		g.printf("func (%s) %s() notionapi.%s {\n\treturn %s\n}\n\n", filterType, f.name, f.builderType, prop)
+		switch f.builder {
+		case "Select", "Status":
+			method := uniqueName(methods, f.name+"Equals")
+			g.printf("// %s matches pages whose %q property is the option.\n", method, f.property)
+			g.printf("func (%s) %s(option %s) notionapi.FilterBuilder {\n\treturn %s.Equals(string(option))\n}\n\n",
+				filterType, method, f.enum, prop)
+		case "MultiSelect":
+			method := uniqueName(methods, f.name+"Contains")
+			g.printf("// %s matches pages whose %q property contains the option.\n", method, f.property)
+			g.printf("func (%s) %s(option %s) notionapi.FilterBuilder {\n\treturn %s.Contains(string(option))\n}\n\n",
+				filterType, method, f.enum, prop)
+		}
+	}
+
+	g.printf("// %s returns the pages of the %s database matching the request.\n", queryFunc, dbName)
+	g.printf(`func %[1]s(ctx context.Context, client *notionapi.Client, req *notionapi.DatabaseQueryRequest) ([]%[2]s, error) {
+	it := client.Database.QueryAll(ctx, %[3]s, req)
+	var pages []%[2]s
+	for it.Next() {
+		var page %[2]s
+		if err := notionapi.UnmarshalPage(it.Value(), &page); err != nil {
+			return nil, err
+		}
+		pages = append(pages, page)
+	}
+	return pages, it.Err()
+}
+`, queryFunc, g.typeName, idConst)
+
+	g.printf("\n// %s returns a repository of the pages of the %s database.\n", repoFunc, dbName)
+	g.printf("func %s(client *notionapi.Client) *notionapi.Repository[%s] {\n\treturn notionapi.NewRepository[%s](client, %s)\n}\n",
+		repoFunc, g.typeName, g.typeName, idConst)
+	return []byte(g.b.String())
+}
+
+// fields returns the fields of the properties of the database, the title
+// first and the others by name.
+func (g *generator) fields() []generatedField {
+	names := make([]string, 0, len(g.db.Properties))
+	for name := range g.db.Properties {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ti := g.db.Properties[names[i]].GetType() == PropertyConfigTypeTitle
+		tj := g.db.Properties[names[j]].GetType() == PropertyConfigTypeTitle
+		if ti != tj {
+			return ti
+		}
+		return names[i] < names[j]
+	})
+
+	fieldNames := map[string]bool{"PageID": true}
+	var fields []generatedField
+	for _, name := range names {
+		if name == "" || strings.Contains(name, ",") {
+			continue
+		}
+		config := g.db.Properties[name]
+		f, ok := propertyField(config)
+		if !ok {
+			continue
+		}
+		f.property = name
+		id := exportedIdentifier(name)
+		if id == "" {
+			id = "Property"
+		}
+		f.name = uniqueName(fieldNames, id)
+
+		kind := string(config.GetType())
+		switch config.GetType() {
+		case PropertyConfigTypeSelect, PropertyConfigTypeMultiSelect, PropertyConfigStatus:
+			f.enum = g.name(g.typeName + f.name)
+			f.options = propertyOptions(config)
+			f.goType = strings.Replace(f.goType, "string", f.enum, 1)
+		}
+		options := name + "," + kind
+		if f.omitEmpty {
+			options += ",omitempty"
+		}
+		f.tag = "notion:" + strconv.Quote(options)
+		if strings.Contains(f.tag, "`") {
+			f.tag = strconv.Quote(f.tag)
+		} else {
+			f.tag = "`" + f.tag + "`"
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// propertyField returns the type of the field of a property and the filter
+// conditions of the property, or false for properties without values.
+func propertyField(config PropertyConfig) (generatedField, bool) {
+	text := func(builder string, omitEmpty bool) generatedField {
+		return generatedField{goType: "string", omitEmpty: omitEmpty, builder: builder, builderType: "TextConditionBuilder"}
+	}
+	switch config.GetType() {
+	case PropertyConfigTypeTitle:
+		return text("Title", false), true
+	case PropertyConfigTypeRichText:
+		return text("RichText", false), true
+	case PropertyConfigTypeURL:
+		return text("URL", true), true
+	case PropertyConfigTypeEmail:
+		return text("Email", true), true
+	case PropertyConfigTypePhoneNumber:
+		return text("PhoneNumber", true), true
+	case PropertyConfigTypeNumber:
+		return generatedField{goType: "float64", builder: "Number", builderType: "NumberConditionBuilder"}, true
+	case PropertyConfigTypeCheckbox:
+		return generatedField{goType: "bool", builder: "Checkbox", builderType: "CheckboxConditionBuilder"}, true
+	case PropertyConfigTypeSelect:
+		return generatedField{goType: "string", omitEmpty: true, builder: "Select", builderType: "SelectConditionBuilder"}, true
+	case PropertyConfigStatus:
+		return generatedField{goType: "string", omitEmpty: true, builder: "Status", builderType: "StatusConditionBuilder"}, true
+	case PropertyConfigTypeMultiSelect:
+		return generatedField{goType: "[]string", builder: "MultiSelect", builderType: "MultiSelectConditionBuilder"}, true
+	case PropertyConfigTypeDate:
+		return generatedField{goType: "*time.Time", builder: "Date", builderType: "DateConditionBuilder"}, true
+	case PropertyConfigCreatedTime:
+		return generatedField{goType: "time.Time", builder: "CreatedTime", builderType: "DateConditionBuilder"}, true
+	case PropertyConfigLastEditedTime:
+		return generatedField{goType: "time.Time", builder: "LastEditedTime", builderType: "DateConditionBuilder"}, true
+	case PropertyConfigTypePeople:
+		return generatedField{goType: "[]string", builder: "People", builderType: "PeopleConditionBuilder"}, true
+	case PropertyConfigCreatedBy:
+		return generatedField{goType: "notionapi.User", builder: "CreatedBy", builderType: "PeopleConditionBuilder"}, true
+	case PropertyConfigLastEditedBy:
+		return generatedField{goType: "notionapi.User", builder: "LastEditedBy", builderType: "PeopleConditionBuilder"}, true
+	case PropertyConfigTypeFiles:
+		return generatedField{goType: "[]string", builder: "Files", builderType: "FilesConditionBuilder"}, true
+	case PropertyConfigTypeRelation:
+		return generatedField{goType: "[]string", builder: "Relation", builderType: "RelationConditionBuilder"}, true
+	case PropertyConfigUniqueID:
+		return generatedField{goType: "string", builder: "UniqueID", builderType: "UniqueIDConditionBuilder"}, true
+	case PropertyConfigTypeFormula:
+		return generatedField{goType: "*notionapi.FormulaProperty", builder: "Formula", builderType: "FormulaConditionBuilder"}, true
+	case PropertyConfigTypeRollup:
+		f := generatedField{goType: "*notionapi.RollupProperty", builder: "Rollup", builderType: "RollupConditionBuilder"}
+		switch result := rollupResult(rollupFunction(config)); {
+		case containsString(result, "number"):
+			f.goType = "float64"
+		case containsString(result, "date"):
+			f.goType = "*time.Time"
+		}
+		return f, true
+	}
+	return generatedField{}, false
+}
+
+func rollupFunction(config PropertyConfig) FunctionType {
+	switch c := config.(type) {
+	case *RollupPropertyConfig:
+		return c.Rollup.Function
+	case RollupPropertyConfig:
+		return c.Rollup.Function
+	}
+	return ""
+}
+
+func propertyOptions(config PropertyConfig) []Option {
+	switch c := config.(type) {
+	case *SelectPropertyConfig:
+		return c.Select.Options
+	case SelectPropertyConfig:
+		return c.Select.Options
+	case *MultiSelectPropertyConfig:
+		return c.MultiSelect.Options
+	case MultiSelectPropertyConfig:
+		return c.MultiSelect.Options
+	case *StatusPropertyConfig:
+		return c.Status.Options
+	case StatusPropertyConfig:
+		return c.Status.Options
+	}
+	return nil
+}
+
+// exportedIdentifier turns a name into an exported Go identifier, such as
+// DueDate for "due date", or returns "" if it has no letters nor digits.
+func exportedIdentifier(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	id := b.String()
+	if id != "" && !isExportedIdentifier(id) {
+		id = "X" + id
+	}
+	return id
+}
+
+func isExportedIdentifier(id string) bool {
+	for i, r := range id {
+		if i == 0 && !unicode.IsUpper(r) {
+			return false
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+	return id != ""
+}
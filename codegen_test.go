@@ -0,0 +1,70 @@
+package notionapi_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/jomei/notionapi"
+)
+
+const tasksDatabase = `{
+	"object": "database",
+	"id": "d9824bdc-8445-4327-be8b-5b47500af6ce",
+	"title": [{"type": "text", "text": {"content": "Tasks"}, "plain_text": "Tasks"}],
+	"properties": {
+		"Name": {"id": "title", "type": "title", "title": {}},
+		"Notes": {"id": "a", "type": "rich_text", "rich_text": {}},
+		"Status": {"id": "b", "type": "status", "status": {"options": [
+			{"id": "1", "name": "Not started", "color": "default"},
+			{"id": "2", "name": "Done", "color": "green"}
+		], "groups": []}},
+		"Priority": {"id": "c", "type": "select", "select": {"options": [{"id": "3", "name": "P1", "color": "red"}]}},
+		"Tags": {"id": "d", "type": "multi_select", "multi_select": {"options": [{"id": "4", "name": "bug fix", "color": "blue"}]}},
+		"Points": {"id": "e", "type": "number", "number": {"format": "number"}},
+		"Due date": {"id": "f", "type": "date", "date": {}},
+		"Done": {"id": "g", "type": "checkbox", "checkbox": {}},
+		"Website": {"id": "h", "type": "url", "url": {}},
+		"Total": {"id": "i", "type": "formula", "formula": {"expression": "1"}},
+		"Sum": {"id": "j", "type": "rollup", "rollup": {"relation_property_name": "Blocked by", "rollup_property_name": "Points", "function": "sum"}},
+		"Blocked by": {"id": "k", "type": "relation", "relation": {"database_id": "d9824bdc-8445-4327-be8b-5b47500af6ce"}},
+		"Created": {"id": "l", "type": "created_time", "created_time": {}},
+		"a,b": {"id": "m", "type": "checkbox", "checkbox": {}}
+	}
+}`
+
+func TestGenerateStruct(t *testing.T) {
+	var db notionapi.Database
+	if err := json.Unmarshal([]byte(tasksDatabase), &db); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := notionapi.GenerateStruct(&db, notionapi.GenerateOptions{Package: "tasks", TypeName: "Task"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := ioutil.ReadFile("testdata/codegen_task.go.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("GenerateStruct() =\n%s\nwant\n%s", got, want)
+	}
+
+	t.Run("type name from the title", func(t *testing.T) {
+		src, err := notionapi.GenerateStruct(&db, notionapi.GenerateOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(src), "type Tasks struct {") || !strings.Contains(string(src), "package models") {
+			t.Errorf("GenerateStruct() without options =\n%s", src)
+		}
+	})
+
+	t.Run("invalid type name", func(t *testing.T) {
+		if _, err := notionapi.GenerateStruct(&db, notionapi.GenerateOptions{TypeName: "task"}); err == nil {
+			t.Error("GenerateStruct() error = nil for an unexported type name")
+		}
+	})
+}
@@ -17,6 +17,7 @@ func (cID CommentID) String() string {
 type CommentService interface {
 	Create(ctx context.Context, request *CommentCreateRequest) (*Comment, error)
 	Get(context.Context, BlockID, *Pagination) (*CommentQueryResponse, error)
+	GetAll(context.Context, BlockID, ...IteratorOption) *CommentIterator
 }
 
 type CommentClient struct {
@@ -95,6 +96,21 @@ func (cc *CommentClient) Get(ctx context.Context, id BlockID, pagination *Pagina
 	return &response, nil
 }
 
+// GetAll returns an iterator over all the un-resolved comments of a page or
+// block, following next_cursor until every page of results has been read.
+func (cc *CommentClient) GetAll(ctx context.Context, id BlockID, opts ...IteratorOption) *CommentIterator {
+	it := &CommentIterator{}
+	it.cursorIterator = newCursorIterator(ctx, "", opts, func(ctx context.Context, cursor Cursor, pageSize int) (int, Cursor, bool, error) {
+		res, err := cc.Get(ctx, id, &Pagination{StartCursor: cursor, PageSize: pageSize})
+		if err != nil {
+			return 0, "", false, err
+		}
+		it.results = res.Results
+		return len(res.Results), res.NextCursor, res.HasMore, nil
+	})
+	return it
+}
+
 type DiscussionID string
 
 func (dID DiscussionID) String() string {
@@ -1,14 +1,18 @@
 package notionapi
 
 const (
-	ObjectTypeDatabase ObjectType = "database"
-	ObjectTypeBlock    ObjectType = "block"
-	ObjectTypePage     ObjectType = "page"
-	ObjectTypeList     ObjectType = "list"
-	ObjectTypeText     ObjectType = "text"
-	ObjectTypeUser     ObjectType = "user"
-	ObjectTypeError    ObjectType = "error"
-	ObjectTypeComment  ObjectType = "comment"
+	ObjectTypeDatabase   ObjectType = "database"
+	ObjectTypeBlock      ObjectType = "block"
+	ObjectTypePage       ObjectType = "page"
+	ObjectTypeList       ObjectType = "list"
+	ObjectTypeText       ObjectType = "text"
+	ObjectTypeUser       ObjectType = "user"
+	ObjectTypeError      ObjectType = "error"
+	ObjectTypeComment    ObjectType = "comment"
+	ObjectTypeFileUpload ObjectType = "file_upload"
+
+	ObjectTypePropertyItem ObjectType = "property_item"
+	ObjectTypeEquation     ObjectType = "equation"
 )
 
 const (
@@ -148,6 +152,11 @@ const (
 	FunctionMin               FunctionType = "min"
 	FunctionMax               FunctionType = "max"
 	FunctionRange             FunctionType = "range"
+	FunctionShowOriginal      FunctionType = "show_original"
+	FunctionShowUnique        FunctionType = "show_unique"
+	FunctionEarliestDate      FunctionType = "earliest_date"
+	FunctionLatestDate        FunctionType = "latest_date"
+	FunctionDateRange         FunctionType = "date_range"
 )
 
 const (
@@ -243,8 +252,9 @@ const (
 )
 
 const (
-	FileTypeFile     FileType = "file"
-	FileTypeExternal FileType = "external"
+	FileTypeFile       FileType = "file"
+	FileTypeExternal   FileType = "external"
+	FileTypeFileUpload FileType = "file_upload"
 )
 
 const (
@@ -21,6 +21,7 @@ type DatabaseService interface {
 	Query(context.Context, DatabaseID, *DatabaseQueryRequest) (*DatabaseQueryResponse, error)
 	Get(context.Context, DatabaseID) (*Database, error)
 	Update(context.Context, DatabaseID, *DatabaseUpdateRequest) (*Database, error)
+	QueryAll(context.Context, DatabaseID, *DatabaseQueryRequest, ...IteratorOption) *PageIterator
 }
 
 type DatabaseClient struct {
@@ -102,6 +103,31 @@ func (dc *DatabaseClient) Query(ctx context.Context, id DatabaseID, requestBody
 	return &response, nil
 }
 
+// QueryAll returns an iterator over all the pages matching the request,
+// following next_cursor until every page of results has been read. The request
+// is not modified; its StartCursor, if any, is used as the starting point.
+func (dc *DatabaseClient) QueryAll(ctx context.Context, id DatabaseID, requestBody *DatabaseQueryRequest, opts ...IteratorOption) *PageIterator {
+	var request DatabaseQueryRequest
+	if requestBody != nil {
+		request = *requestBody
+	}
+
+	it := &PageIterator{}
+	it.cursorIterator = newCursorIterator(ctx, request.StartCursor, opts, func(ctx context.Context, cursor Cursor, pageSize int) (int, Cursor, bool, error) {
+		request.StartCursor = cursor
+		if pageSize != 0 {
+			request.PageSize = pageSize
+		}
+		res, err := dc.Query(ctx, id, &request)
+		if err != nil {
+			return 0, "", false, err
+		}
+		it.results = res.Results
+		return len(res.Results), res.NextCursor, res.HasMore, nil
+	})
+	return it
+}
+
 // DatabaseQueryRequest represents the request body for DatabaseClient.Query.
 type DatabaseQueryRequest struct {
 	// When supplied, limits which pages are returned based on the filter
@@ -174,7 +200,49 @@ type DatabaseUpdateRequest struct {
 	// names or IDs of the properties as they appear in Notion, and the values are
 	// property schema objects. If adding a new property, then the key is the name
 	// of the new database property and the value is a property schema object.
+	//
+	// To remove a property, set its value to DeletePropertyConfig. To rename a
+	// property, set its value to RenamePropertyConfig, which may also change
+	// its configuration.
 	Properties PropertyConfigs `json:"properties,omitempty"`
+	// An array of rich text objects that represents the description of the
+	// database. If nil, the description remains unchanged; an empty array
+	// removes it.
+	Description []RichText `json:"description"`
+	// The icon of the database. If omitted, the icon remains unchanged.
+	Icon *Icon `json:"icon,omitempty"`
+	// The cover image of the database. If omitted, the cover remains unchanged.
+	Cover *Image `json:"cover,omitempty"`
+	// Whether the database is archived (deleted). If omitted, it remains
+	// unchanged.
+	Archived *bool `json:"archived,omitempty"`
+	// Whether the database is displayed inline in its parent page rather than
+	// as a full page. If omitted, it remains unchanged.
+	IsInline *bool `json:"is_inline,omitempty"`
+}
+
+func (ur DatabaseUpdateRequest) MarshalJSON() ([]byte, error) {
+	var description *[]RichText
+	if ur.Description != nil {
+		description = &ur.Description
+	}
+	return json.Marshal(struct {
+		Title       []RichText      `json:"title,omitempty"`
+		Description *[]RichText     `json:"description,omitempty"`
+		Properties  PropertyConfigs `json:"properties,omitempty"`
+		Icon        *Icon           `json:"icon,omitempty"`
+		Cover       *Image          `json:"cover,omitempty"`
+		Archived    *bool           `json:"archived,omitempty"`
+		IsInline    *bool           `json:"is_inline,omitempty"`
+	}{
+		Title:       ur.Title,
+		Description: description,
+		Properties:  ur.Properties,
+		Icon:        ur.Icon,
+		Cover:       ur.Cover,
+		Archived:    ur.Archived,
+		IsInline:    ur.IsInline,
+	})
 }
 
 type Database struct {
@@ -2,6 +2,7 @@ package notionapi_test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"reflect"
 	"testing"
@@ -568,3 +569,76 @@ func TestDatabaseQueryRequest_MarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestDatabaseUpdateRequest_MarshalJSON(t *testing.T) {
+	archived := true
+	inline := false
+	emoji := notionapi.Emoji("📝")
+	tests := []struct {
+		name    string
+		req     *notionapi.DatabaseUpdateRequest
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name: "delete and rename properties",
+			req: &notionapi.DatabaseUpdateRequest{
+				Properties: notionapi.PropertyConfigs{
+					"Old":   notionapi.DeletePropertyConfig{},
+					"a%3Bb": notionapi.RenamePropertyConfig{Name: "New name"},
+					"Tags": notionapi.RenamePropertyConfig{
+						Name: "Labels",
+						Config: &notionapi.SelectPropertyConfig{
+							ID:     "c%3Dd",
+							Type:   notionapi.PropertyConfigTypeSelect,
+							Select: notionapi.Select{Options: []notionapi.Option{{Name: "bug"}}},
+						},
+					},
+				},
+			},
+			want: []byte(`{"properties":{"Old":null,"Tags":{"name":"Labels","select":{"options":[{"name":"bug"}]},"type":"select"},"a%3Bb":{"name":"New name"}}}`),
+		},
+		{
+			name: "database attributes",
+			req: &notionapi.DatabaseUpdateRequest{
+				Description: []notionapi.RichText{{Type: notionapi.ObjectTypeText, Text: &notionapi.Text{Content: "Tasks"}}},
+				Icon:        &notionapi.Icon{Type: "emoji", Emoji: &emoji},
+				Cover:       &notionapi.Image{Type: "external", External: &notionapi.FileObject{URL: "https://example.com/cover.png"}},
+				Archived:    &archived,
+				IsInline:    &inline,
+			},
+			want: []byte(`{"description":[{"type":"text","text":{"content":"Tasks"}}],"icon":{"type":"emoji","emoji":"📝"},"cover":{"type":"external","external":{"url":"https://example.com/cover.png"}},"archived":true,"is_inline":false}`),
+		},
+		{
+			name: "remove the description",
+			req:  &notionapi.DatabaseUpdateRequest{Description: []notionapi.RichText{}},
+			want: []byte(`{"description":[]}`),
+		},
+		{
+			name: "leave everything unchanged",
+			req:  &notionapi.DatabaseUpdateRequest{},
+			want: []byte(`{}`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.req.MarshalJSON()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MarshalJSON() got = %s, want %s", got, tt.want)
+			}
+
+			// Values and pointers are marshalled the same.
+			got, err = json.Marshal(*tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("json.Marshal() of a value got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
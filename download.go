@@ -0,0 +1,409 @@
+package notionapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FileSource tells which part of its owner a file comes from.
+type FileSource string
+
+const (
+	FileSourceBlock    FileSource = "block"
+	FileSourceIcon     FileSource = "icon"
+	FileSourceCover    FileSource = "cover"
+	FileSourceProperty FileSource = "property"
+)
+
+// FileRef is a file attached to a block, a page or a database, along with the
+// location of the file in its owner, which is re-fetched to get a fresh URL
+// once the URL of a file hosted by Notion expires.
+type FileRef struct {
+	Source FileSource `json:"source"`
+	// The owner of the file: a block for files of file blocks and icons of
+	// callouts, and otherwise a page or a database.
+	BlockID    BlockID    `json:"block_id,omitempty"`
+	PageID     PageID     `json:"page_id,omitempty"`
+	DatabaseID DatabaseID `json:"database_id,omitempty"`
+	// The name of the files property of FileSourceProperty files and the
+	// position of the file in it.
+	Property string `json:"property,omitempty"`
+	Index    int    `json:"index,omitempty"`
+
+	// The name of the file, if Notion knows it.
+	Name       string     `json:"name,omitempty"`
+	URL        string     `json:"url"`
+	ExpiryTime *time.Time `json:"expiry_time,omitempty"`
+}
+
+// ExpiresBefore reports whether the URL of the file expires before t. URLs of
+// external files never expire.
+func (f FileRef) ExpiresBefore(t time.Time) bool {
+	return f.ExpiryTime != nil && f.ExpiryTime.Before(t)
+}
+
+func (f FileRef) ownerID() string {
+	switch {
+	case f.BlockID != "":
+		return f.BlockID.String()
+	case f.PageID != "":
+		return f.PageID.String()
+	default:
+		return f.DatabaseID.String()
+	}
+}
+
+// BlockFileRef returns the file of an image, audio, video, file or pdf block,
+// or the file icon of a callout.
+func BlockFileRef(b Block) (FileRef, bool) {
+	ref := FileRef{Source: FileSourceBlock, BlockID: b.GetID()}
+	switch b := b.(type) {
+	case *FileBlock:
+		ref.Name = b.File.Name
+	case *CalloutBlock:
+		if b.Callout.Icon == nil {
+			return FileRef{}, false
+		}
+		ref.Source = FileSourceIcon
+		ref.URL, ref.ExpiryTime = iconFile(b.Callout.Icon)
+		return ref, ref.URL != ""
+	}
+	f, ok := b.(DownloadableFileBlock)
+	if !ok {
+		return FileRef{}, false
+	}
+	ref.URL, ref.ExpiryTime = f.GetURL(), f.GetExpiryTime()
+	return ref, ref.URL != ""
+}
+
+// BlocksFileRefs returns the files of the blocks and of their children, in
+// document order.
+func BlocksFileRefs(blocks Blocks) []FileRef {
+	var refs []FileRef
+	for _, b := range blocks {
+		if ref, ok := BlockFileRef(b); ok {
+			refs = append(refs, ref)
+		}
+		refs = append(refs, BlocksFileRefs(blockChildren(b))...)
+	}
+	return refs
+}
+
+// PageFileRefs returns the icon, the cover and the files of the files
+// properties of a page, the properties being sorted by name.
+func PageFileRefs(p *Page) []FileRef {
+	var refs []FileRef
+	if u, expiry := iconFile(p.Icon); u != "" {
+		refs = append(refs, FileRef{Source: FileSourceIcon, PageID: PageID(p.ID), URL: u, ExpiryTime: expiry})
+	}
+	if u, expiry := coverFile(p.Cover); u != "" {
+		refs = append(refs, FileRef{Source: FileSourceCover, PageID: PageID(p.ID), URL: u, ExpiryTime: expiry})
+	}
+
+	names := make([]string, 0, len(p.Properties))
+	for name := range p.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var files []File
+		switch prop := p.Properties[name].(type) {
+		case *FilesProperty:
+			files = prop.Files
+		case FilesProperty:
+			files = prop.Files
+		}
+		for i, f := range files {
+			ref := FileRef{Source: FileSourceProperty, PageID: PageID(p.ID), Property: name, Index: i, Name: f.Name}
+			if f.File != nil {
+				ref.URL, ref.ExpiryTime = f.File.URL, f.File.ExpiryTime
+			} else if f.External != nil {
+				ref.URL = f.External.URL
+			}
+			if ref.URL != "" {
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs
+}
+
+// DatabaseFileRefs returns the icon and the cover of a database.
+func DatabaseFileRefs(db *Database) []FileRef {
+	var refs []FileRef
+	if u, expiry := iconFile(db.Icon); u != "" {
+		refs = append(refs, FileRef{Source: FileSourceIcon, DatabaseID: DatabaseID(db.ID), URL: u, ExpiryTime: expiry})
+	}
+	if u, expiry := coverFile(db.Cover); u != "" {
+		refs = append(refs, FileRef{Source: FileSourceCover, DatabaseID: DatabaseID(db.ID), URL: u, ExpiryTime: expiry})
+	}
+	return refs
+}
+
+func iconFile(icon *Icon) (string, *time.Time) {
+	if icon == nil {
+		return "", nil
+	}
+	if icon.File != nil {
+		return icon.File.URL, icon.File.ExpiryTime
+	}
+	return icon.GetURL(), nil
+}
+
+func coverFile(cover *Image) (string, *time.Time) {
+	if cover == nil {
+		return "", nil
+	}
+	if cover.File != nil {
+		return cover.File.URL, cover.File.ExpiryTime
+	}
+	return cover.GetURL(), nil
+}
+
+// Downloader downloads the files attached to blocks, pages and databases.
+//
+// The URLs of files hosted by Notion are signed and expire an hour after
+// they are fetched. The downloader re-fetches the owner of files whose URL
+// expired, or is rejected, to get a fresh one.
+type Downloader struct {
+	client *Client
+
+	// ExpiryMargin is the time left before the expiry of a URL under which it
+	// is refreshed before downloading. Defaults to a minute.
+	ExpiryMargin time.Duration
+}
+
+// NewDownloader returns a downloader fetching files with the HTTP client of
+// client, and fresh URLs with its API.
+func NewDownloader(client *Client) *Downloader {
+	return &Downloader{client: client, ExpiryMargin: time.Minute}
+}
+
+// Refresh re-fetches the owner of a file and returns the file with its
+// current URL.
+func (d *Downloader) Refresh(ctx context.Context, ref FileRef) (FileRef, error) {
+	var refs []FileRef
+	switch {
+	case ref.BlockID != "":
+		b, err := d.client.Block.Get(ctx, ref.BlockID)
+		if err != nil {
+			return ref, err
+		}
+		if fresh, ok := BlockFileRef(b); ok {
+			refs = append(refs, fresh)
+		}
+	case ref.PageID != "":
+		p, err := d.client.Page.Get(ctx, ref.PageID)
+		if err != nil {
+			return ref, err
+		}
+		refs = PageFileRefs(p)
+	case ref.DatabaseID != "":
+		db, err := d.client.Database.Get(ctx, ref.DatabaseID)
+		if err != nil {
+			return ref, err
+		}
+		refs = DatabaseFileRefs(db)
+	default:
+		return ref, fmt.Errorf("notionapi: file %s has no owner to refresh it from", ref.URL)
+	}
+
+	for _, fresh := range refs {
+		if fresh.Source == ref.Source && fresh.Property == ref.Property && fresh.Index == ref.Index {
+			return fresh, nil
+		}
+	}
+	return ref, fmt.Errorf("notionapi: file %s of %s no longer exists", ref.Source, ref.ownerID())
+}
+
+// Download writes the content of a file to w and returns its content type.
+func (d *Downloader) Download(ctx context.Context, ref FileRef, w io.Writer) (string, error) {
+	res, ref, err := d.get(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if _, err := io.Copy(w, res.Body); err != nil {
+		return "", fmt.Errorf("notionapi: cannot download %s: %w", ref.URL, err)
+	}
+	return responseContentType(res, ref), nil
+}
+
+// DownloadToDir writes the content of a file to a new file of dir and
+// returns its path. The file is named after the name of the file, or after
+// the URL, with the extension of its content type, and is suffixed with a
+// number if a file of the same name exists.
+func (d *Downloader) DownloadToDir(ctx context.Context, ref FileRef, dir string) (string, error) {
+	res, ref, err := d.get(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	tmp, err := os.CreateTemp(dir, ".download-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = io.Copy(tmp, res.Body)
+	if errClose := tmp.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return "", fmt.Errorf("notionapi: cannot download %s: %w", ref.URL, err)
+	}
+
+	stem, ext := downloadName(ref, responseContentType(res, ref))
+	name := filepath.Join(dir, stem+ext)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			break
+		}
+		name = filepath.Join(dir, fmt.Sprintf("%s-%d%s", stem, i, ext))
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// get requests the content of a file, refreshing its URL first if it is
+// about to expire, or once if it is rejected.
+func (d *Downloader) get(ctx context.Context, ref FileRef) (*http.Response, FileRef, error) {
+	refreshed := false
+	if ref.ExpiresBefore(time.Now().Add(d.ExpiryMargin)) {
+		var err error
+		if ref, err = d.Refresh(ctx, ref); err != nil {
+			return nil, ref, err
+		}
+		refreshed = true
+	}
+
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ref.URL, nil)
+		if err != nil {
+			return nil, ref, err
+		}
+		res, err := d.client.httpClient.Do(req)
+		if err != nil {
+			return nil, ref, err
+		}
+		if res.StatusCode >= 200 && res.StatusCode < 300 {
+			return res, ref, nil
+		}
+		res.Body.Close()
+
+		// Signed URLs are rejected with 400 or 403 once expired.
+		expired := res.StatusCode == http.StatusForbidden || res.StatusCode == http.StatusBadRequest
+		if refreshed || !expired || ref.ExpiryTime == nil {
+			return nil, ref, fmt.Errorf("notionapi: cannot download %s: %s", ref.URL, res.Status)
+		}
+		if ref, err = d.Refresh(ctx, ref); err != nil {
+			return nil, ref, err
+		}
+		refreshed = true
+	}
+}
+
+func responseContentType(res *http.Response, ref FileRef) string {
+	if ct := res.Header.Get("Content-Type"); ct != "" {
+		return ct
+	}
+	return mime.TypeByExtension(path.Ext(urlPath(ref.URL)))
+}
+
+// downloadName returns the stem and the extension of the name of a
+// downloaded file.
+func downloadName(ref FileRef, contentType string) (string, string) {
+	name := ref.Name
+	if name == "" {
+		name = path.Base(urlPath(ref.URL))
+	}
+	name = strings.Map(func(r rune) rune {
+		if r < ' ' || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	if stem == "" || stem == "." {
+		stem = ref.ownerID()
+	}
+
+	// The extension of the name is kept unless the content type is specific
+	// and disagrees with it.
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "" || mediaType == "application/octet-stream" || mediaType == "binary/octet-stream" {
+		return stem, ext
+	}
+	preferred := preferredExtensions[mediaType]
+	exts, _ := mime.ExtensionsByType(mediaType)
+	if ext != "" {
+		if strings.EqualFold(ext, preferred) {
+			return stem, ext
+		}
+		for _, e := range exts {
+			if strings.EqualFold(e, ext) {
+				return stem, ext
+			}
+		}
+	}
+	switch {
+	case preferred != "":
+		return stem, preferred
+	case len(exts) == 1:
+		return stem, exts[0]
+	}
+	return stem, ext
+}
+
+// preferredExtensions are the extensions given to downloaded files of common
+// types, as mime.ExtensionsByType sorts the extensions of a type
+// alphabetically rather than by use.
+var preferredExtensions = map[string]string{
+	"application/json":              ".json",
+	"application/msword":            ".doc",
+	"application/pdf":               ".pdf",
+	"application/vnd.ms-excel":      ".xls",
+	"application/vnd.ms-powerpoint": ".ppt",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": ".pptx",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         ".xlsx",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   ".docx",
+	"application/zip": ".zip",
+	"audio/mpeg":      ".mp3",
+	"audio/ogg":       ".ogg",
+	"audio/wav":       ".wav",
+	"image/gif":       ".gif",
+	"image/heic":      ".heic",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/svg+xml":   ".svg",
+	"image/webp":      ".webp",
+	"text/csv":        ".csv",
+	"text/html":       ".html",
+	"text/markdown":   ".md",
+	"text/plain":      ".txt",
+	"video/mp4":       ".mp4",
+	"video/quicktime": ".mov",
+	"video/webm":      ".webm",
+}
+
+func urlPath(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return ""
+	}
+	return parsed.Path
+}
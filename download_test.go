@@ -0,0 +1,210 @@
+package notionapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+)
+
+// newFileServer returns *http.Client serving the given API responses, keyed by
+// path, and file contents, keyed by URL. URLs listed in expired are rejected
+// as expired signed URLs.
+func newFileServer(t *testing.T, api map[string]string, files map[string]string, expired map[string]bool, requests *[]string) *http.Client {
+	return newTestClient(func(req *http.Request) *http.Response {
+		*requests = append(*requests, req.Method+" "+req.URL.String())
+		res := &http.Response{StatusCode: http.StatusOK, Header: make(http.Header)}
+		if req.URL.Host == "api.notion.com" {
+			body, ok := api[req.URL.Path]
+			if !ok {
+				t.Fatalf("unexpected request %s", req.URL)
+			}
+			res.Body = ioutil.NopCloser(strings.NewReader(body))
+			return res
+		}
+		content, ok := files[req.URL.String()]
+		if expired[req.URL.String()] || !ok {
+			res.StatusCode = http.StatusForbidden
+			res.Status = "403 Forbidden"
+			content = "<Error><Code>AccessDenied</Code></Error>"
+		} else if strings.HasSuffix(req.URL.Path, ".png") || strings.Contains(req.URL.Path, "cat") {
+			res.Header.Set("Content-Type", "image/png")
+		}
+		res.Body = ioutil.NopCloser(strings.NewReader(content))
+		return res
+	})
+}
+
+func TestDownloadName(t *testing.T) {
+	tests := []struct {
+		name        string
+		ref         notionapi.FileRef
+		contentType string
+		want        string
+	}{
+		{"generic type", notionapi.FileRef{Name: "report.pdf"}, "application/octet-stream", "report.pdf"},
+		{"generic S3 type", notionapi.FileRef{Name: "report.pdf"}, "binary/octet-stream", "report.pdf"},
+		{"matching type", notionapi.FileRef{Name: "photo.JPEG"}, "image/jpeg", "photo.JPEG"},
+		{"preferred extension", notionapi.FileRef{URL: "https://example.com/photo"}, "image/jpeg", "photo.jpg"},
+		{"disagreeing type", notionapi.FileRef{Name: "notes.txt"}, "application/pdf; charset=binary", "notes.pdf"},
+		{"unknown type", notionapi.FileRef{Name: "data.bin"}, "application/x-unknown", "data.bin"},
+		{"no type", notionapi.FileRef{URL: "https://example.com/a/data"}, "", "data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := notionapi.DownloadName(tt.ref, tt.contentType); got != tt.want {
+				t.Errorf("DownloadName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageFileRefs(t *testing.T) {
+	var page notionapi.Page
+	err := json.Unmarshal([]byte(`{
+		"object": "page",
+		"id": "page",
+		"icon": {"type": "file", "file": {"url": "https://s3.example.com/icon.png", "expiry_time": "2022-01-01T00:00:00Z"}},
+		"cover": {"type": "external", "external": {"url": "https://example.com/cover.jpg"}},
+		"properties": {
+			"Attachments": {"id": "a", "type": "files", "files": [
+				{"name": "spec.pdf", "type": "file", "file": {"url": "https://s3.example.com/spec.pdf", "expiry_time": "2022-01-01T00:00:00Z"}},
+				{"name": "logo", "type": "external", "external": {"url": "https://example.com/logo.svg"}}
+			]},
+			"Name": {"id": "title", "type": "title", "title": []}
+		}
+	}`), &page)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiry := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	want := []notionapi.FileRef{
+		{Source: notionapi.FileSourceIcon, PageID: "page", URL: "https://s3.example.com/icon.png", ExpiryTime: &expiry},
+		{Source: notionapi.FileSourceCover, PageID: "page", URL: "https://example.com/cover.jpg"},
+		{Source: notionapi.FileSourceProperty, PageID: "page", Property: "Attachments", Name: "spec.pdf", URL: "https://s3.example.com/spec.pdf", ExpiryTime: &expiry},
+		{Source: notionapi.FileSourceProperty, PageID: "page", Property: "Attachments", Index: 1, Name: "logo", URL: "https://example.com/logo.svg"},
+	}
+	if got := notionapi.PageFileRefs(&page); !reflect.DeepEqual(got, want) {
+		t.Errorf("PageFileRefs() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestDownloader_Download(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	freshBlock := `{"object": "block", "id": "block", "type": "file", "file": {"name": "notes.txt", "type": "file", "file": {"url": "https://s3.example.com/fresh", "expiry_time": "` + future.Format(time.RFC3339) + `"}}}`
+	freshPage := `{"object": "page", "id": "page", "properties": {"Files": {"id": "f", "type": "files", "files": [
+		{"name": "a.txt", "type": "external", "external": {"url": "https://example.com/a.txt"}},
+		{"name": "b.txt", "type": "file", "file": {"url": "https://s3.example.com/fresh", "expiry_time": "` + future.Format(time.RFC3339) + `"}}
+	]}}}`
+	files := map[string]string{"https://s3.example.com/fresh": "content", "https://s3.example.com/stale": "stale"}
+
+	tests := []struct {
+		name         string
+		ref          notionapi.FileRef
+		expired      map[string]bool
+		wantRequests []string
+		wantErr      bool
+	}{
+		{
+			name: "valid URL",
+			ref:  notionapi.FileRef{Source: notionapi.FileSourceBlock, BlockID: "block", URL: "https://s3.example.com/fresh", ExpiryTime: &future},
+			wantRequests: []string{
+				"GET https://s3.example.com/fresh",
+			},
+		},
+		{
+			name: "expired URL",
+			ref:  notionapi.FileRef{Source: notionapi.FileSourceBlock, BlockID: "block", URL: "https://s3.example.com/stale", ExpiryTime: &past},
+			wantRequests: []string{
+				"GET https://api.notion.com/v1/blocks/block",
+				"GET https://s3.example.com/fresh",
+			},
+		},
+		{
+			name:    "rejected URL",
+			ref:     notionapi.FileRef{Source: notionapi.FileSourceProperty, PageID: "page", Property: "Files", Index: 1, URL: "https://s3.example.com/stale", ExpiryTime: &future},
+			expired: map[string]bool{"https://s3.example.com/stale": true},
+			wantRequests: []string{
+				"GET https://s3.example.com/stale",
+				"GET https://api.notion.com/v1/pages/page",
+				"GET https://s3.example.com/fresh",
+			},
+		},
+		{
+			name:    "rejected external URL",
+			ref:     notionapi.FileRef{Source: notionapi.FileSourceBlock, BlockID: "block", URL: "https://example.com/missing"},
+			wantErr: true,
+			wantRequests: []string{
+				"GET https://example.com/missing",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			api := map[string]string{"/v1/blocks/block": freshBlock, "/v1/pages/page": freshPage}
+			client := notionapi.NewClient("some_token", notionapi.WithHTTPClient(newFileServer(t, api, files, tt.expired, &requests)))
+
+			var buf bytes.Buffer
+			_, err := notionapi.NewDownloader(client).Download(context.Background(), tt.ref, &buf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Download() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && buf.String() != "content" {
+				t.Errorf("Download() content = %q, want %q", buf.String(), "content")
+			}
+			if !reflect.DeepEqual(requests, tt.wantRequests) {
+				t.Errorf("Download() requests = %v, want %v", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestDownloader_DownloadToDir(t *testing.T) {
+	var requests []string
+	files := map[string]string{"https://example.com/images/cat": "meow", "https://example.com/notes.txt": "text"}
+	client := notionapi.NewClient("some_token", notionapi.WithHTTPClient(newFileServer(t, nil, files, nil, &requests)))
+	downloader := notionapi.NewDownloader(client)
+	dir := t.TempDir()
+
+	refs := []notionapi.FileRef{
+		{Source: notionapi.FileSourceBlock, BlockID: "b1", URL: "https://example.com/images/cat"},
+		{Source: notionapi.FileSourceBlock, BlockID: "b2", URL: "https://example.com/images/cat"},
+		{Source: notionapi.FileSourceProperty, PageID: "p", Property: "Files", Name: "My notes.txt", URL: "https://example.com/notes.txt"},
+	}
+	var got []string
+	for _, ref := range refs {
+		name, err := downloader.DownloadToDir(context.Background(), ref, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, filepath.Base(name))
+	}
+
+	want := []string{"cat.png", "cat-1.png", "My notes.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DownloadToDir() names = %v, want %v", got, want)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("DownloadToDir() left %d files, want 3", len(entries))
+	}
+	if b, _ := os.ReadFile(filepath.Join(dir, "cat.png")); string(b) != "meow" {
+		t.Errorf("cat.png = %q, want %q", b, "meow")
+	}
+}
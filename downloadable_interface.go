@@ -3,7 +3,7 @@ package notionapi
 import "time"
 
 // DownloadableFileBlock is an interface for blocks that can be downloaded
-// such as Pdf, FileBlock, and Image
+// such as Pdf, FileBlock, Image, Audio and Video
 type DownloadableFileBlock interface {
 	Block
 	GetURL() string
@@ -61,9 +61,37 @@ func (b *ImageBlock) GetExpiryTime() *time.Time {
 	return nil
 }
 
+// GetURL implements DownloadableFileBlock interface for AudioBlock
+func (b *AudioBlock) GetURL() string {
+	return b.Audio.GetURL()
+}
+
+// GetExpiryTime implements DownloadableFileBlock interface for AudioBlock
+func (b *AudioBlock) GetExpiryTime() *time.Time {
+	if b.Audio.File != nil {
+		return b.Audio.File.ExpiryTime
+	}
+	return nil
+}
+
+// GetURL implements DownloadableFileBlock interface for VideoBlock
+func (b *VideoBlock) GetURL() string {
+	return b.Video.GetURL()
+}
+
+// GetExpiryTime implements DownloadableFileBlock interface for VideoBlock
+func (b *VideoBlock) GetExpiryTime() *time.Time {
+	if b.Video.File != nil {
+		return b.Video.File.ExpiryTime
+	}
+	return nil
+}
+
 // Verify that types implement DownloadableFileBlock interface
 var (
 	_ DownloadableFileBlock = (*PdfBlock)(nil)
 	_ DownloadableFileBlock = (*FileBlock)(nil)
 	_ DownloadableFileBlock = (*ImageBlock)(nil)
+	_ DownloadableFileBlock = (*AudioBlock)(nil)
+	_ DownloadableFileBlock = (*VideoBlock)(nil)
 )
@@ -1,31 +1,228 @@
 package notionapi
 
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ErrorCode is the code of an error returned by the Notion API. It implements
+// error so that codes can be used as targets of errors.Is:
+//
+//	if errors.Is(err, notionapi.ErrorCodeObjectNotFound) { ... }
+//
+// See https://developers.notion.com/reference/status-codes#error-codes
 type ErrorCode string
 
+const (
+	ErrorCodeInvalidJSON                   ErrorCode = "invalid_json"
+	ErrorCodeInvalidRequestURL             ErrorCode = "invalid_request_url"
+	ErrorCodeInvalidRequest                ErrorCode = "invalid_request"
+	ErrorCodeInvalidGrant                  ErrorCode = "invalid_grant"
+	ErrorCodeValidationError               ErrorCode = "validation_error"
+	ErrorCodeMissingVersion                ErrorCode = "missing_version"
+	ErrorCodeUnauthorized                  ErrorCode = "unauthorized"
+	ErrorCodeRestrictedResource            ErrorCode = "restricted_resource"
+	ErrorCodeObjectNotFound                ErrorCode = "object_not_found"
+	ErrorCodeConflictError                 ErrorCode = "conflict_error"
+	ErrorCodeRateLimited                   ErrorCode = "rate_limited"
+	ErrorCodeInternalServerError           ErrorCode = "internal_server_error"
+	ErrorCodeBadGateway                    ErrorCode = "bad_gateway"
+	ErrorCodeServiceUnavailable            ErrorCode = "service_unavailable"
+	ErrorCodeDatabaseConnectionUnavailable ErrorCode = "database_connection_unavailable"
+	ErrorCodeGatewayTimeout                ErrorCode = "gateway_timeout"
+
+	// Codes of errors returned when creating an OAuth token.
+	ErrorCodeInvalidClient        ErrorCode = "invalid_client"
+	ErrorCodeUnauthorizedClient   ErrorCode = "unauthorized_client"
+	ErrorCodeUnsupportedGrantType ErrorCode = "unsupported_grant_type"
+	ErrorCodeInvalidScope         ErrorCode = "invalid_scope"
+)
+
+func (c ErrorCode) Error() string {
+	return string(c)
+}
+
+// Sentinel errors for the classes of errors returned by the API, to be used
+// with errors.Is. Every *Error, *TokenCreateError and *RateLimitedError
+// matches the class of its code, or of its HTTP status when the code is
+// unknown.
+var (
+	// ErrInvalidRequest matches errors caused by an invalid request, with a
+	// 400 status.
+	ErrInvalidRequest = errors.New("notionapi: invalid request")
+	// ErrUnauthorized matches errors caused by an invalid token.
+	ErrUnauthorized = errors.New("notionapi: unauthorized")
+	// ErrRestrictedResource matches errors caused by a token without
+	// permission to perform the operation.
+	ErrRestrictedResource = errors.New("notionapi: restricted resource")
+	// ErrNotFound matches errors caused by an object that does not exist or
+	// is not shared with the integration.
+	ErrNotFound = errors.New("notionapi: object not found")
+	// ErrConflict matches errors caused by a conflict with another request.
+	ErrConflict = errors.New("notionapi: conflict")
+	// ErrRateLimited matches errors caused by exceeding the rate limit.
+	ErrRateLimited = errors.New("notionapi: rate limited")
+	// ErrServer matches errors with a 5xx status.
+	ErrServer = errors.New("notionapi: server error")
+)
+
+// errorClass returns the sentinel error matching an error code, or the HTTP
+// status of the response if the code is unknown.
+func errorClass(code ErrorCode, status int) error {
+	switch code {
+	case ErrorCodeInvalidJSON, ErrorCodeInvalidRequestURL, ErrorCodeInvalidRequest,
+		ErrorCodeInvalidGrant, ErrorCodeValidationError, ErrorCodeMissingVersion,
+		ErrorCodeUnsupportedGrantType, ErrorCodeInvalidScope:
+		return ErrInvalidRequest
+	case ErrorCodeUnauthorized, ErrorCodeInvalidClient, ErrorCodeUnauthorizedClient:
+		return ErrUnauthorized
+	case ErrorCodeRestrictedResource:
+		return ErrRestrictedResource
+	case ErrorCodeObjectNotFound:
+		return ErrNotFound
+	case ErrorCodeConflictError:
+		return ErrConflict
+	case ErrorCodeRateLimited:
+		return ErrRateLimited
+	case ErrorCodeInternalServerError, ErrorCodeBadGateway, ErrorCodeServiceUnavailable,
+		ErrorCodeDatabaseConnectionUnavailable, ErrorCodeGatewayTimeout:
+		return ErrServer
+	}
+	switch {
+	case status == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case status == http.StatusForbidden:
+		return ErrRestrictedResource
+	case status == http.StatusNotFound:
+		return ErrNotFound
+	case status == http.StatusConflict:
+		return ErrConflict
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status >= 500:
+		return ErrServer
+	case status >= 400:
+		return ErrInvalidRequest
+	}
+	return nil
+}
+
+// errorCodeForStatus returns the code of the errors returned by the API with
+// the HTTP status, used when the body of a response is not a Notion error.
+func errorCodeForStatus(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrorCodeInvalidRequest
+	case http.StatusUnauthorized:
+		return ErrorCodeUnauthorized
+	case http.StatusForbidden:
+		return ErrorCodeRestrictedResource
+	case http.StatusNotFound:
+		return ErrorCodeObjectNotFound
+	case http.StatusConflict:
+		return ErrorCodeConflictError
+	case http.StatusTooManyRequests:
+		return ErrorCodeRateLimited
+	case http.StatusInternalServerError:
+		return ErrorCodeInternalServerError
+	case http.StatusBadGateway:
+		return ErrorCodeBadGateway
+	case http.StatusServiceUnavailable:
+		return ErrorCodeServiceUnavailable
+	case http.StatusGatewayTimeout:
+		return ErrorCodeGatewayTimeout
+	}
+	return ""
+}
+
+// Error is an error returned by the Notion API.
 type Error struct {
 	Object  ObjectType `json:"object"`
 	Status  int        `json:"status"`
 	Code    ErrorCode  `json:"code"`
 	Message string     `json:"message"`
+	// RequestID identifies the request for Notion support, from the body of
+	// the response or its x-request-id header.
+	RequestID string `json:"request_id,omitempty"`
 }
 
 func (e *Error) Error() string {
 	return e.Message
 }
 
+// Is reports whether the error has the target code or belongs to the target
+// class of errors.
+func (e *Error) Is(target error) bool {
+	if code, ok := target.(ErrorCode); ok {
+		return e.Code == code
+	}
+	return target != nil && target == errorClass(e.Code, e.Status)
+}
+
+// RateLimitedError is returned when requests are still rate limited after the
+// last attempt allowed by the retry policy.
 type RateLimitedError struct {
 	Message string
+	// Err is the error decoded from the last response, if any.
+	Err error
 }
 
 func (e *RateLimitedError) Error() string {
 	return e.Message
 }
 
+// Is reports whether target is ErrRateLimited or ErrorCodeRateLimited.
+func (e *RateLimitedError) Is(target error) bool {
+	return target == ErrRateLimited || target == ErrorCodeRateLimited
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return e.Err
+}
+
+// TokenCreateError is an error returned when creating an OAuth token.
 type TokenCreateError struct {
 	Code    ErrorCode `json:"error"`
 	Message string    `json:"error_description"`
+	// Status is the HTTP status of the response.
+	Status int `json:"-"`
+	// RequestID identifies the request for Notion support, from the
+	// x-request-id header of the response.
+	RequestID string `json:"-"`
 }
 
 func (e *TokenCreateError) Error() string {
 	return e.Message
 }
+
+// Is reports whether the error has the target code or belongs to the target
+// class of errors.
+func (e *TokenCreateError) Is(target error) bool {
+	if code, ok := target.(ErrorCode); ok {
+		return e.Code == code
+	}
+	return target != nil && target == errorClass(e.Code, e.Status)
+}
+
+// decodeClientError decodes the error in the body of a response. Bodies that
+// are not Notion errors, such as the pages of proxies, are reported as an
+// *Error with the status of the response.
+func decodeClientError(res *http.Response, data []byte) error {
+	var apiErr Error
+	if err := json.Unmarshal(data, &apiErr); err != nil || apiErr.Code == "" {
+		apiErr = Error{
+			Object:  ObjectTypeError,
+			Status:  res.StatusCode,
+			Code:    errorCodeForStatus(res.StatusCode),
+			Message: res.Status,
+		}
+	}
+	if apiErr.Status == 0 {
+		apiErr.Status = res.StatusCode
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = res.Header.Get("X-Request-Id")
+	}
+	return &apiErr
+}
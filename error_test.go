@@ -0,0 +1,131 @@
+package notionapi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jomei/notionapi"
+)
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		want       *notionapi.Error
+		is         []error
+		isNot      []error
+	}{
+		{
+			name:       "decodes Notion errors",
+			statusCode: http.StatusNotFound,
+			body:       `{"object":"error","status":404,"code":"object_not_found","message":"Could not find block.","request_id":"body-id"}`,
+			want: &notionapi.Error{
+				Object:    notionapi.ObjectTypeError,
+				Status:    http.StatusNotFound,
+				Code:      notionapi.ErrorCodeObjectNotFound,
+				Message:   "Could not find block.",
+				RequestID: "body-id",
+			},
+			is:    []error{notionapi.ErrNotFound, notionapi.ErrorCodeObjectNotFound},
+			isNot: []error{notionapi.ErrInvalidRequest, notionapi.ErrorCodeValidationError},
+		},
+		{
+			name:       "takes the request ID from the header",
+			statusCode: http.StatusForbidden,
+			body:       `{"object":"error","status":403,"code":"restricted_resource","message":"Forbidden."}`,
+			want: &notionapi.Error{
+				Object:    notionapi.ObjectTypeError,
+				Status:    http.StatusForbidden,
+				Code:      notionapi.ErrorCodeRestrictedResource,
+				Message:   "Forbidden.",
+				RequestID: "header-id",
+			},
+			is: []error{notionapi.ErrRestrictedResource},
+		},
+		{
+			name:       "reports bodies that are not Notion errors",
+			statusCode: http.StatusBadGateway,
+			body:       `<html>Bad Gateway</html>`,
+			want: &notionapi.Error{
+				Object:    notionapi.ObjectTypeError,
+				Status:    http.StatusBadGateway,
+				Code:      notionapi.ErrorCodeBadGateway,
+				Message:   "502 Bad Gateway",
+				RequestID: "header-id",
+			},
+			is: []error{notionapi.ErrServer, notionapi.ErrorCodeBadGateway},
+		},
+		{
+			name:       "classifies validation errors",
+			statusCode: http.StatusBadRequest,
+			body:       `{"object":"error","status":400,"code":"validation_error","message":"Invalid."}`,
+			want: &notionapi.Error{
+				Object:    notionapi.ObjectTypeError,
+				Status:    http.StatusBadRequest,
+				Code:      notionapi.ErrorCodeValidationError,
+				Message:   "Invalid.",
+				RequestID: "header-id",
+			},
+			is:    []error{notionapi.ErrInvalidRequest, notionapi.ErrorCodeValidationError},
+			isNot: []error{notionapi.ErrNotFound, notionapi.ErrServer},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(func(*http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: tt.statusCode,
+					Status:     fmt.Sprintf("%d %s", tt.statusCode, http.StatusText(tt.statusCode)),
+					Header:     http.Header{"X-Request-Id": []string{"header-id"}},
+					Body:       ioutil.NopCloser(strings.NewReader(tt.body)),
+				}
+			})
+			client := notionapi.NewClient("some_token", notionapi.WithHTTPClient(c), notionapi.WithRetry(1))
+			_, err := client.Block.Get(context.Background(), "some_id")
+
+			var apiErr *notionapi.Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Get() error = %#v, want *notionapi.Error", err)
+			}
+			if *apiErr != *tt.want {
+				t.Errorf("Get() error = %+v, want %+v", apiErr, tt.want)
+			}
+			for _, target := range tt.is {
+				if !errors.Is(err, target) {
+					t.Errorf("errors.Is(%v, %v) = false, want true", err, target)
+				}
+			}
+			for _, target := range tt.isNot {
+				if errors.Is(err, target) {
+					t.Errorf("errors.Is(%v, %v) = true, want false", err, target)
+				}
+			}
+		})
+	}
+
+	t.Run("rate limited errors wrap the last response", func(t *testing.T) {
+		c := newTestClient(func(*http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{"0"}},
+				Body:       ioutil.NopCloser(strings.NewReader(`{"object":"error","status":429,"code":"rate_limited","message":"Slow down."}`)),
+			}
+		})
+		client := notionapi.NewClient("some_token", notionapi.WithHTTPClient(c), notionapi.WithRetry(1))
+		_, err := client.Block.Get(context.Background(), "some_id")
+		if !errors.Is(err, notionapi.ErrRateLimited) || !errors.Is(err, notionapi.ErrorCodeRateLimited) {
+			t.Errorf("Get() error = %v, want a rate limited error", err)
+		}
+		var apiErr *notionapi.Error
+		if !errors.As(err, &apiErr) || apiErr.Message != "Slow down." {
+			t.Errorf("Get() error = %v, want to wrap the Notion error", err)
+		}
+	})
+}
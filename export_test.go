@@ -0,0 +1,23 @@
+package notionapi
+
+import (
+	"context"
+	"time"
+)
+
+// SetRateLimiterClock replaces the clock of a limiter, for tests to check its
+// delays without waiting.
+func SetRateLimiterClock(l *RateLimiter, now func() time.Time, sleep func(ctx context.Context, d time.Duration) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.now = now
+	l.sleep = sleep
+	l.last = now()
+}
+
+// DownloadName returns the name given to a file downloaded with the content
+// type.
+func DownloadName(ref FileRef, contentType string) string {
+	stem, ext := downloadName(ref, contentType)
+	return stem + ext
+}
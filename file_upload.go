@@ -0,0 +1,329 @@
+package notionapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+type FileUploadID string
+
+func (fID FileUploadID) String() string {
+	return string(fID)
+}
+
+type FileUploadMode string
+
+const (
+	FileUploadModeSinglePart  FileUploadMode = "single_part"
+	FileUploadModeMultiPart   FileUploadMode = "multi_part"
+	FileUploadModeExternalURL FileUploadMode = "external_url"
+)
+
+type FileUploadStatus string
+
+const (
+	FileUploadStatusPending  FileUploadStatus = "pending"
+	FileUploadStatusUploaded FileUploadStatus = "uploaded"
+	FileUploadStatusExpired  FileUploadStatus = "expired"
+	FileUploadStatusFailed   FileUploadStatus = "failed"
+)
+
+// MaxFileUploadPartSize is the largest file sent in a single part, and the
+// largest part of files sent in multiple parts.
+const MaxFileUploadPartSize = 20 << 20
+
+type FileUploadService interface {
+	Create(context.Context, *FileUploadCreateRequest) (*FileUpload, error)
+	Send(context.Context, FileUploadID, *FileUploadSendRequest) (*FileUpload, error)
+	Complete(context.Context, FileUploadID) (*FileUpload, error)
+	Get(context.Context, FileUploadID) (*FileUpload, error)
+	Upload(ctx context.Context, filename string, r io.Reader, opts *FileUploadOptions) (*FileUpload, error)
+}
+
+type FileUploadClient struct {
+	apiClient *Client
+}
+
+// FileUpload is a file uploaded to Notion. Once uploaded, it is attached to
+// blocks, pages and properties by referencing its ID in a file of type
+// FileTypeFileUpload:
+//
+//	notionapi.Image{
+//		Type:       notionapi.FileTypeFileUpload,
+//		FileUpload: &notionapi.FileUploadObject{ID: upload.ID},
+//	}
+//
+// Uploads that are not attached before their expiry time are deleted.
+type FileUpload struct {
+	Object         ObjectType       `json:"object"`
+	ID             FileUploadID     `json:"id"`
+	CreatedTime    time.Time        `json:"created_time"`
+	LastEditedTime time.Time        `json:"last_edited_time"`
+	CreatedBy      *User            `json:"created_by,omitempty"`
+	ExpiryTime     *time.Time       `json:"expiry_time,omitempty"`
+	UploadURL      string           `json:"upload_url,omitempty"`
+	Archived       bool             `json:"archived"`
+	Status         FileUploadStatus `json:"status"`
+	Filename       string           `json:"filename,omitempty"`
+	ContentType    string           `json:"content_type,omitempty"`
+	ContentLength  int64            `json:"content_length,omitempty"`
+	NumberOfParts  *FileUploadParts `json:"number_of_parts,omitempty"`
+}
+
+// FileUploadParts tells how many parts of a multi-part upload were sent.
+type FileUploadParts struct {
+	Total int `json:"total"`
+	Sent  int `json:"sent"`
+}
+
+// FileUploadCreateRequest represents the request body for
+// FileUploadClient.Create.
+type FileUploadCreateRequest struct {
+	// How the file is uploaded, single_part by default.
+	Mode FileUploadMode `json:"mode,omitempty"`
+	// The name of the file, required for multi_part uploads.
+	Filename string `json:"filename,omitempty"`
+	// The MIME type of the file, inferred from the name of the file if
+	// omitted.
+	ContentType string `json:"content_type,omitempty"`
+	// The number of parts of multi_part uploads.
+	NumberOfParts int `json:"number_of_parts,omitempty"`
+	// The URL of the file for external_url uploads, which Notion imports.
+	ExternalURL string `json:"external_url,omitempty"`
+}
+
+// Creates a file upload, to which the content of the file is then sent.
+//
+// See https://developers.notion.com/reference/create-a-file-upload
+func (fc *FileUploadClient) Create(ctx context.Context, requestBody *FileUploadCreateRequest) (*FileUpload, error) {
+	res, err := fc.apiClient.request(ctx, http.MethodPost, "file_uploads", nil, requestBody)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFileUpload(res)
+}
+
+// FileUploadSendRequest represents the content sent by FileUploadClient.Send.
+type FileUploadSendRequest struct {
+	// The content of the file, or of the part of the file. It is read in
+	// memory before being sent, so that the request can be retried.
+	Data io.Reader
+	// The name of the file, "file" by default.
+	Filename string
+	// The MIME type of the file, application/octet-stream by default.
+	ContentType string
+	// The number of the part, from 1, for multi_part uploads.
+	PartNumber int
+}
+
+// Sends the content of a file upload, or a part of it for multi_part uploads.
+// Single part uploads are uploaded once their content is sent.
+//
+// See https://developers.notion.com/reference/send-a-file-upload
+func (fc *FileUploadClient) Send(ctx context.Context, id FileUploadID, requestBody *FileUploadSendRequest) (*FileUpload, error) {
+	if requestBody == nil || requestBody.Data == nil {
+		return nil, errors.New("notionapi: no data to send")
+	}
+	body, err := requestBody.multipart()
+	if err != nil {
+		return nil, err
+	}
+	res, err := fc.apiClient.request(ctx, http.MethodPost, fmt.Sprintf("file_uploads/%s/send", id.String()), nil, body)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFileUpload(res)
+}
+
+// multipart encodes the request as the multipart form expected by the API.
+func (r *FileUploadSendRequest) multipart() (*rawBody, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if r.PartNumber > 0 {
+		if err := w.WriteField("part_number", strconv.Itoa(r.PartNumber)); err != nil {
+			return nil, err
+		}
+	}
+
+	filename, contentType := r.Filename, r.ContentType
+	if filename == "" {
+		filename = "file"
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{"name": "file", "filename": filename}))
+	header.Set("Content-Type", contentType)
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, r.Data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return &rawBody{contentType: w.FormDataContentType(), data: buf.Bytes()}, nil
+}
+
+// Completes a multi_part upload once all its parts are sent.
+//
+// See https://developers.notion.com/reference/complete-a-file-upload
+func (fc *FileUploadClient) Complete(ctx context.Context, id FileUploadID) (*FileUpload, error) {
+	res, err := fc.apiClient.request(ctx, http.MethodPost, fmt.Sprintf("file_uploads/%s/complete", id.String()), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFileUpload(res)
+}
+
+// Retrieves a file upload, for example to check the status of an
+// external_url upload.
+//
+// See https://developers.notion.com/reference/retrieve-a-file-upload
+func (fc *FileUploadClient) Get(ctx context.Context, id FileUploadID) (*FileUpload, error) {
+	res, err := fc.apiClient.request(ctx, http.MethodGet, fmt.Sprintf("file_uploads/%s", id.String()), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFileUpload(res)
+}
+
+// FileUploadOptions configures FileUploadClient.Upload.
+type FileUploadOptions struct {
+	// The MIME type of the file, inferred from the extension of its name by
+	// default.
+	ContentType string
+	// The size of the file in bytes. If 0, it is found by seeking readers
+	// implementing io.Seeker, and otherwise only files smaller than PartSize
+	// can be uploaded.
+	Size int64
+	// The size of the parts of files larger than it, MaxFileUploadPartSize by
+	// default. Notion requires parts of at least 5 MB, except for the last
+	// one.
+	PartSize int64
+}
+
+// Upload creates a file upload and streams the content of r to it, in a
+// single part if it is at most PartSize bytes long, and otherwise in parts of
+// PartSize bytes, read in memory one at a time, before completing the upload.
+// It returns the uploaded file, ready to be attached.
+func (fc *FileUploadClient) Upload(ctx context.Context, filename string, r io.Reader, opts *FileUploadOptions) (*FileUpload, error) {
+	var o FileUploadOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.PartSize <= 0 {
+		o.PartSize = MaxFileUploadPartSize
+	}
+	if o.ContentType == "" {
+		o.ContentType = mime.TypeByExtension(filepath.Ext(filename))
+	}
+	size := o.Size
+	if size <= 0 {
+		var err error
+		if size, err = readerSize(r); err != nil {
+			return nil, err
+		}
+	}
+
+	if size < 0 {
+		// The size is unknown: the file is sent in a single part if it fits.
+		data, err := io.ReadAll(io.LimitReader(r, o.PartSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) > o.PartSize {
+			return nil, fmt.Errorf("notionapi: the size of %s is needed to upload it in parts", filename)
+		}
+		size, r = int64(len(data)), bytes.NewReader(data)
+	}
+
+	if size <= o.PartSize {
+		upload, err := fc.Create(ctx, &FileUploadCreateRequest{Filename: filename, ContentType: o.ContentType})
+		if err != nil {
+			return nil, err
+		}
+		return fc.Send(ctx, upload.ID, &FileUploadSendRequest{Data: r, Filename: filename, ContentType: o.ContentType})
+	}
+
+	parts := int((size + o.PartSize - 1) / o.PartSize)
+	upload, err := fc.Create(ctx, &FileUploadCreateRequest{
+		Mode:          FileUploadModeMultiPart,
+		Filename:      filename,
+		ContentType:   o.ContentType,
+		NumberOfParts: parts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, o.PartSize)
+	for part := 1; part <= parts; part++ {
+		n := o.PartSize
+		if remaining := size - int64(part-1)*o.PartSize; remaining < n {
+			n = remaining
+		}
+		if _, err := io.ReadFull(r, buf[:n]); err != nil {
+			return nil, fmt.Errorf("notionapi: cannot read part %d of %s: %w", part, filename, err)
+		}
+		_, err := fc.Send(ctx, upload.ID, &FileUploadSendRequest{
+			Data:        bytes.NewReader(buf[:n]),
+			Filename:    filename,
+			ContentType: o.ContentType,
+			PartNumber:  part,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fc.Complete(ctx, upload.ID)
+}
+
+// readerSize returns the number of bytes left in r if it implements
+// io.Seeker, or -1.
+func readerSize(r io.Reader) (int64, error) {
+	s, ok := r.(io.Seeker)
+	if !ok {
+		return -1, nil
+	}
+	current, err := s.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return -1, nil
+	}
+	end, err := s.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := s.Seek(current, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return end - current, nil
+}
+
+func decodeFileUpload(res *http.Response) (*FileUpload, error) {
+	defer func() {
+		if errClose := res.Body.Close(); errClose != nil {
+			log.Println("failed to close body, should never happen")
+		}
+	}()
+
+	var response FileUpload
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
@@ -0,0 +1,176 @@
+package notionapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jomei/notionapi"
+)
+
+// newUploadServer returns *http.Client accepting file uploads, recording the
+// requests it receives and the content of the parts sent.
+func newUploadServer(t *testing.T, requests *[]string, parts *[]string) *http.Client {
+	return newTestClient(func(req *http.Request) *http.Response {
+		record := req.Method + " " + req.URL.Path
+		status := notionapi.FileUploadStatusPending
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/send"):
+			mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+			if err != nil || mediaType != "multipart/form-data" {
+				t.Fatalf("Content-Type = %s, want multipart/form-data", req.Header.Get("Content-Type"))
+			}
+			form, err := multipart.NewReader(req.Body, params["boundary"]).ReadForm(1 << 20)
+			if err != nil {
+				t.Fatal(err)
+			}
+			file, err := form.File["file"][0].Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, _ := ioutil.ReadAll(file)
+			*parts = append(*parts, string(data))
+			record += fmt.Sprintf(" part=%v type=%s", form.Value["part_number"], form.File["file"][0].Header.Get("Content-Type"))
+			if len(form.Value["part_number"]) == 0 {
+				status = notionapi.FileUploadStatusUploaded
+			}
+		case strings.HasSuffix(req.URL.Path, "/complete"):
+			status = notionapi.FileUploadStatusUploaded
+		case req.Body != nil:
+			b, _ := ioutil.ReadAll(req.Body)
+			record += " " + string(b)
+		}
+		*requests = append(*requests, record)
+
+		b, _ := json.Marshal(map[string]interface{}{"object": "file_upload", "id": "upload", "status": status})
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			Header:     make(http.Header),
+		}
+	})
+}
+
+func TestFileUploadClient_Upload(t *testing.T) {
+	tests := []struct {
+		name      string
+		reader    func(data string) io.Reader
+		data      string
+		opts      *notionapi.FileUploadOptions
+		wantReqs  []string
+		wantParts []string
+		wantErr   bool
+	}{
+		{
+			name:   "single part",
+			reader: func(data string) io.Reader { return strings.NewReader(data) },
+			data:   "hello",
+			wantReqs: []string{
+				`POST /v1/file_uploads {"filename":"hello.json","content_type":"application/json"}`,
+				`POST /v1/file_uploads/upload/send part=[] type=application/json`,
+			},
+			wantParts: []string{"hello"},
+		},
+		{
+			name:   "multiple parts",
+			reader: func(data string) io.Reader { return strings.NewReader(data) },
+			data:   "0123456789",
+			opts:   &notionapi.FileUploadOptions{PartSize: 4, ContentType: "application/octet-stream"},
+			wantReqs: []string{
+				`POST /v1/file_uploads {"mode":"multi_part","filename":"hello.json","content_type":"application/octet-stream","number_of_parts":3}`,
+				`POST /v1/file_uploads/upload/send part=[1] type=application/octet-stream`,
+				`POST /v1/file_uploads/upload/send part=[2] type=application/octet-stream`,
+				`POST /v1/file_uploads/upload/send part=[3] type=application/octet-stream`,
+				`POST /v1/file_uploads/upload/complete`,
+			},
+			wantParts: []string{"0123", "4567", "89"},
+		},
+		{
+			name:   "small stream of unknown size",
+			reader: func(data string) io.Reader { return bytes.NewBufferString(data) },
+			data:   "hello",
+			opts:   &notionapi.FileUploadOptions{PartSize: 5, ContentType: "text/plain"},
+			wantReqs: []string{
+				`POST /v1/file_uploads {"filename":"hello.json","content_type":"text/plain"}`,
+				`POST /v1/file_uploads/upload/send part=[] type=text/plain`,
+			},
+			wantParts: []string{"hello"},
+		},
+		{
+			name:    "large stream of unknown size",
+			reader:  func(data string) io.Reader { return bytes.NewBufferString(data) },
+			data:    "0123456789",
+			opts:    &notionapi.FileUploadOptions{PartSize: 4},
+			wantErr: true,
+		},
+		{
+			name:   "large stream of known size",
+			reader: func(data string) io.Reader { return bytes.NewBufferString(data) },
+			data:   "0123456789",
+			opts:   &notionapi.FileUploadOptions{PartSize: 5, Size: 10, ContentType: "text/plain"},
+			wantReqs: []string{
+				`POST /v1/file_uploads {"mode":"multi_part","filename":"hello.json","content_type":"text/plain","number_of_parts":2}`,
+				`POST /v1/file_uploads/upload/send part=[1] type=text/plain`,
+				`POST /v1/file_uploads/upload/send part=[2] type=text/plain`,
+				`POST /v1/file_uploads/upload/complete`,
+			},
+			wantParts: []string{"01234", "56789"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests, parts []string
+			client := notionapi.NewClient("some_token", notionapi.WithHTTPClient(newUploadServer(t, &requests, &parts)))
+
+			got, err := client.FileUpload.Upload(context.Background(), "hello.json", tt.reader(tt.data), tt.opts)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Upload() error = nil")
+				}
+				if len(requests) != 0 {
+					t.Errorf("Upload() sent %v", requests)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.ID != "upload" || got.Status != notionapi.FileUploadStatusUploaded {
+				t.Errorf("Upload() = %+v", got)
+			}
+			if !reflect.DeepEqual(requests, tt.wantReqs) {
+				t.Errorf("Upload() requests =\n%s\nwant\n%s", strings.Join(requests, "\n"), strings.Join(tt.wantReqs, "\n"))
+			}
+			if !reflect.DeepEqual(parts, tt.wantParts) {
+				t.Errorf("Upload() parts = %q, want %q", parts, tt.wantParts)
+			}
+		})
+	}
+}
+
+func TestFileUploadObject(t *testing.T) {
+	block := notionapi.ImageBlock{
+		BasicBlock: notionapi.BasicBlock{Object: notionapi.ObjectTypeBlock, Type: notionapi.BlockTypeImage},
+		Image: notionapi.Image{
+			Type:       notionapi.FileTypeFileUpload,
+			FileUpload: &notionapi.FileUploadObject{ID: "upload"},
+		},
+	}
+	b, err := json.Marshal(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"image":{"type":"file_upload","file_upload":{"id":"upload"}}`) {
+		t.Errorf("json.Marshal() = %s", b)
+	}
+}
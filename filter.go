@@ -45,20 +45,28 @@ type TimestampFilter struct {
 func (f TimestampFilter) filter() {}
 
 type PropertyFilter struct {
-	Property    string                      `json:"property"`
-	RichText    *TextFilterCondition        `json:"rich_text,omitempty"`
-	Number      *NumberFilterCondition      `json:"number,omitempty"`
-	Checkbox    *CheckboxFilterCondition    `json:"checkbox,omitempty"`
-	Select      *SelectFilterCondition      `json:"select,omitempty"`
-	MultiSelect *MultiSelectFilterCondition `json:"multi_select,omitempty"`
-	Date        *DateFilterCondition        `json:"date,omitempty"`
-	People      *PeopleFilterCondition      `json:"people,omitempty"`
-	Files       *FilesFilterCondition       `json:"files,omitempty"`
-	Relation    *RelationFilterCondition    `json:"relation,omitempty"`
-	Formula     *FormulaFilterCondition     `json:"formula,omitempty"`
-	Rollup      *RollupFilterCondition      `json:"rollup,omitempty"`
-	Status      *StatusFilterCondition      `json:"status,omitempty"`
-	UniqueId    *UniqueIdFilterCondition    `json:"unique_id,omitempty"`
+	Property       string                      `json:"property"`
+	Title          *TextFilterCondition        `json:"title,omitempty"`
+	RichText       *TextFilterCondition        `json:"rich_text,omitempty"`
+	URL            *TextFilterCondition        `json:"url,omitempty"`
+	Email          *TextFilterCondition        `json:"email,omitempty"`
+	PhoneNumber    *TextFilterCondition        `json:"phone_number,omitempty"`
+	Number         *NumberFilterCondition      `json:"number,omitempty"`
+	Checkbox       *CheckboxFilterCondition    `json:"checkbox,omitempty"`
+	Select         *SelectFilterCondition      `json:"select,omitempty"`
+	MultiSelect    *MultiSelectFilterCondition `json:"multi_select,omitempty"`
+	Date           *DateFilterCondition        `json:"date,omitempty"`
+	CreatedTime    *DateFilterCondition        `json:"created_time,omitempty"`
+	LastEditedTime *DateFilterCondition        `json:"last_edited_time,omitempty"`
+	People         *PeopleFilterCondition      `json:"people,omitempty"`
+	CreatedBy      *PeopleFilterCondition      `json:"created_by,omitempty"`
+	LastEditedBy   *PeopleFilterCondition      `json:"last_edited_by,omitempty"`
+	Files          *FilesFilterCondition       `json:"files,omitempty"`
+	Relation       *RelationFilterCondition    `json:"relation,omitempty"`
+	Formula        *FormulaFilterCondition     `json:"formula,omitempty"`
+	Rollup         *RollupFilterCondition      `json:"rollup,omitempty"`
+	Status         *StatusFilterCondition      `json:"status,omitempty"`
+	UniqueId       *UniqueIdFilterCondition    `json:"unique_id,omitempty"`
 }
 
 func (f PropertyFilter) filter() {}
@@ -0,0 +1,642 @@
+package notionapi
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxFilterNesting is the number of levels of compound filters allowed by
+// Notion: a compound filter may contain compound filters, but these may only
+// contain property and timestamp filters.
+//
+// See https://developers.notion.com/reference/post-database-query-filter#compound-filter-conditions
+const MaxFilterNesting = 2
+
+// FilterBuilder builds a Filter for database queries. Builders are values:
+// combining them with And and Or returns new builders and leaves the original
+// ones unchanged.
+//
+//	filter, err := notionapi.Prop("Status").Status().Equals("Done").
+//		And(notionapi.Prop("Due").Date().PastWeek()).
+//		Build()
+//
+// Conditions are built from the builder of the type of the property, so that a
+// condition cannot be applied to a property of another type by mistake.
+type FilterBuilder struct {
+	filter Filter
+	// op is the operator of the compound filter built by And or Or, which
+	// further calls with the same operator extend instead of nesting.
+	op FilterOperator
+}
+
+// And returns a builder of a filter matching pages matched by every filter.
+func And(filters ...FilterBuilder) FilterBuilder {
+	return compoundFilter(FilterOperatorAND, filters)
+}
+
+// Or returns a builder of a filter matching pages matched by any filter.
+func Or(filters ...FilterBuilder) FilterBuilder {
+	return compoundFilter(FilterOperatorOR, filters)
+}
+
+func compoundFilter(op FilterOperator, filters []FilterBuilder) FilterBuilder {
+	var compound []Filter
+	for _, f := range filters {
+		if f.op == op {
+			compound = append(compound, compoundFilters(f.filter)...)
+		} else {
+			compound = append(compound, f.filter)
+		}
+	}
+	if op == FilterOperatorAND {
+		return FilterBuilder{filter: AndCompoundFilter(compound), op: op}
+	}
+	return FilterBuilder{filter: OrCompoundFilter(compound), op: op}
+}
+
+func compoundFilters(f Filter) []Filter {
+	switch f := f.(type) {
+	case AndCompoundFilter:
+		return f
+	case OrCompoundFilter:
+		return f
+	}
+	return nil
+}
+
+// And returns a builder of a filter matching pages matched by b and every
+// other filter.
+func (b FilterBuilder) And(others ...FilterBuilder) FilterBuilder {
+	return And(append([]FilterBuilder{b}, others...)...)
+}
+
+// Or returns a builder of a filter matching pages matched by b or any other
+// filter.
+func (b FilterBuilder) Or(others ...FilterBuilder) FilterBuilder {
+	return Or(append([]FilterBuilder{b}, others...)...)
+}
+
+// Build returns the filter, or an error if it is empty or nests compound
+// filters deeper than MaxFilterNesting.
+func (b FilterBuilder) Build() (Filter, error) {
+	if b.filter == nil {
+		return nil, errors.New("notionapi: empty filter")
+	}
+	if err := validateFilterNesting(b.filter, 0); err != nil {
+		return nil, err
+	}
+	return b.filter, nil
+}
+
+func validateFilterNesting(f Filter, depth int) error {
+	filters := compoundFilters(f)
+	if filters == nil {
+		switch f.(type) {
+		case AndCompoundFilter, OrCompoundFilter:
+			return errors.New("notionapi: empty compound filter")
+		}
+		return nil
+	}
+	if depth >= MaxFilterNesting {
+		return fmt.Errorf("notionapi: compound filters can only be nested %d levels deep", MaxFilterNesting)
+	}
+	for _, sub := range filters {
+		if err := validateFilterNesting(sub, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PropertyFilterBuilder selects the type of the property a filter applies to.
+type PropertyFilterBuilder struct {
+	property string
+}
+
+// Prop starts building a filter on the property with the given name or ID.
+func Prop(property string) PropertyFilterBuilder {
+	return PropertyFilterBuilder{property: property}
+}
+
+func (p PropertyFilterBuilder) build(f PropertyFilter) FilterBuilder {
+	f.Property = p.property
+	return FilterBuilder{filter: f}
+}
+
+// Title returns the conditions of title properties.
+func (p PropertyFilterBuilder) Title() TextConditionBuilder {
+	return TextConditionBuilder{func(c *TextFilterCondition) FilterBuilder { return p.build(PropertyFilter{Title: c}) }}
+}
+
+// RichText returns the conditions of rich text properties.
+func (p PropertyFilterBuilder) RichText() TextConditionBuilder {
+	return TextConditionBuilder{func(c *TextFilterCondition) FilterBuilder { return p.build(PropertyFilter{RichText: c}) }}
+}
+
+// URL returns the conditions of URL properties.
+func (p PropertyFilterBuilder) URL() TextConditionBuilder {
+	return TextConditionBuilder{func(c *TextFilterCondition) FilterBuilder { return p.build(PropertyFilter{URL: c}) }}
+}
+
+// Email returns the conditions of email properties.
+func (p PropertyFilterBuilder) Email() TextConditionBuilder {
+	return TextConditionBuilder{func(c *TextFilterCondition) FilterBuilder { return p.build(PropertyFilter{Email: c}) }}
+}
+
+// PhoneNumber returns the conditions of phone number properties.
+func (p PropertyFilterBuilder) PhoneNumber() TextConditionBuilder {
+	return TextConditionBuilder{func(c *TextFilterCondition) FilterBuilder { return p.build(PropertyFilter{PhoneNumber: c}) }}
+}
+
+// Number returns the conditions of number properties.
+func (p PropertyFilterBuilder) Number() NumberConditionBuilder {
+	return NumberConditionBuilder{func(c *NumberFilterCondition) FilterBuilder { return p.build(PropertyFilter{Number: c}) }}
+}
+
+// Checkbox returns the conditions of checkbox properties.
+func (p PropertyFilterBuilder) Checkbox() CheckboxConditionBuilder {
+	return CheckboxConditionBuilder{func(c *CheckboxFilterCondition) FilterBuilder { return p.build(PropertyFilter{Checkbox: c}) }}
+}
+
+// Select returns the conditions of select properties.
+func (p PropertyFilterBuilder) Select() SelectConditionBuilder {
+	return SelectConditionBuilder{func(c *SelectFilterCondition) FilterBuilder { return p.build(PropertyFilter{Select: c}) }}
+}
+
+// MultiSelect returns the conditions of multi-select properties.
+func (p PropertyFilterBuilder) MultiSelect() MultiSelectConditionBuilder {
+	return MultiSelectConditionBuilder{func(c *MultiSelectFilterCondition) FilterBuilder { return p.build(PropertyFilter{MultiSelect: c}) }}
+}
+
+// Status returns the conditions of status properties.
+func (p PropertyFilterBuilder) Status() StatusConditionBuilder {
+	return StatusConditionBuilder{func(c *StatusFilterCondition) FilterBuilder { return p.build(PropertyFilter{Status: c}) }}
+}
+
+// Date returns the conditions of date properties.
+func (p PropertyFilterBuilder) Date() DateConditionBuilder {
+	return DateConditionBuilder{func(c *DateFilterCondition) FilterBuilder { return p.build(PropertyFilter{Date: c}) }}
+}
+
+// CreatedTime returns the conditions of created time properties.
+func (p PropertyFilterBuilder) CreatedTime() DateConditionBuilder {
+	return DateConditionBuilder{func(c *DateFilterCondition) FilterBuilder { return p.build(PropertyFilter{CreatedTime: c}) }}
+}
+
+// LastEditedTime returns the conditions of last edited time properties.
+func (p PropertyFilterBuilder) LastEditedTime() DateConditionBuilder {
+	return DateConditionBuilder{func(c *DateFilterCondition) FilterBuilder { return p.build(PropertyFilter{LastEditedTime: c}) }}
+}
+
+// People returns the conditions of people properties.
+func (p PropertyFilterBuilder) People() PeopleConditionBuilder {
+	return PeopleConditionBuilder{func(c *PeopleFilterCondition) FilterBuilder { return p.build(PropertyFilter{People: c}) }}
+}
+
+// CreatedBy returns the conditions of created by properties.
+func (p PropertyFilterBuilder) CreatedBy() PeopleConditionBuilder {
+	return PeopleConditionBuilder{func(c *PeopleFilterCondition) FilterBuilder { return p.build(PropertyFilter{CreatedBy: c}) }}
+}
+
+// LastEditedBy returns the conditions of last edited by properties.
+func (p PropertyFilterBuilder) LastEditedBy() PeopleConditionBuilder {
+	return PeopleConditionBuilder{func(c *PeopleFilterCondition) FilterBuilder { return p.build(PropertyFilter{LastEditedBy: c}) }}
+}
+
+// Files returns the conditions of files properties.
+func (p PropertyFilterBuilder) Files() FilesConditionBuilder {
+	return FilesConditionBuilder{func(c *FilesFilterCondition) FilterBuilder { return p.build(PropertyFilter{Files: c}) }}
+}
+
+// Relation returns the conditions of relation properties.
+func (p PropertyFilterBuilder) Relation() RelationConditionBuilder {
+	return RelationConditionBuilder{func(c *RelationFilterCondition) FilterBuilder { return p.build(PropertyFilter{Relation: c}) }}
+}
+
+// UniqueID returns the conditions of unique ID properties.
+func (p PropertyFilterBuilder) UniqueID() UniqueIDConditionBuilder {
+	return UniqueIDConditionBuilder{func(c *UniqueIdFilterCondition) FilterBuilder { return p.build(PropertyFilter{UniqueId: c}) }}
+}
+
+// Formula returns the conditions of formula properties, by type of result.
+func (p PropertyFilterBuilder) Formula() FormulaConditionBuilder {
+	return FormulaConditionBuilder{func(c *FormulaFilterCondition) FilterBuilder { return p.build(PropertyFilter{Formula: c}) }}
+}
+
+// Rollup returns the conditions of rollup properties.
+func (p PropertyFilterBuilder) Rollup() RollupConditionBuilder {
+	return RollupConditionBuilder{func(c *RollupFilterCondition) FilterBuilder { return p.build(PropertyFilter{Rollup: c}) }}
+}
+
+// CreatedTime starts building a filter on the creation time of pages.
+func CreatedTime() DateConditionBuilder {
+	return DateConditionBuilder{func(c *DateFilterCondition) FilterBuilder {
+		return FilterBuilder{filter: TimestampFilter{Timestamp: TimestampCreated, CreatedTime: c}}
+	}}
+}
+
+// LastEditedTime starts building a filter on the last edition time of pages.
+func LastEditedTime() DateConditionBuilder {
+	return DateConditionBuilder{func(c *DateFilterCondition) FilterBuilder {
+		return FilterBuilder{filter: TimestampFilter{Timestamp: TimestampLastEdited, LastEditedTime: c}}
+	}}
+}
+
+// TextConditionBuilder builds conditions on text values.
+type TextConditionBuilder struct {
+	build func(*TextFilterCondition) FilterBuilder
+}
+
+func (b TextConditionBuilder) Equals(v string) FilterBuilder {
+	return b.build(&TextFilterCondition{Equals: v})
+}
+
+func (b TextConditionBuilder) DoesNotEqual(v string) FilterBuilder {
+	return b.build(&TextFilterCondition{DoesNotEqual: v})
+}
+
+func (b TextConditionBuilder) Contains(v string) FilterBuilder {
+	return b.build(&TextFilterCondition{Contains: v})
+}
+
+func (b TextConditionBuilder) DoesNotContain(v string) FilterBuilder {
+	return b.build(&TextFilterCondition{DoesNotContain: v})
+}
+
+func (b TextConditionBuilder) StartsWith(v string) FilterBuilder {
+	return b.build(&TextFilterCondition{StartsWith: v})
+}
+
+func (b TextConditionBuilder) EndsWith(v string) FilterBuilder {
+	return b.build(&TextFilterCondition{EndsWith: v})
+}
+
+func (b TextConditionBuilder) IsEmpty() FilterBuilder {
+	return b.build(&TextFilterCondition{IsEmpty: true})
+}
+
+func (b TextConditionBuilder) IsNotEmpty() FilterBuilder {
+	return b.build(&TextFilterCondition{IsNotEmpty: true})
+}
+
+// NumberConditionBuilder builds conditions on numbers.
+type NumberConditionBuilder struct {
+	build func(*NumberFilterCondition) FilterBuilder
+}
+
+func (b NumberConditionBuilder) Equals(v float64) FilterBuilder {
+	return b.build(&NumberFilterCondition{Equals: &v})
+}
+
+func (b NumberConditionBuilder) DoesNotEqual(v float64) FilterBuilder {
+	return b.build(&NumberFilterCondition{DoesNotEqual: &v})
+}
+
+func (b NumberConditionBuilder) GreaterThan(v float64) FilterBuilder {
+	return b.build(&NumberFilterCondition{GreaterThan: &v})
+}
+
+func (b NumberConditionBuilder) LessThan(v float64) FilterBuilder {
+	return b.build(&NumberFilterCondition{LessThan: &v})
+}
+
+func (b NumberConditionBuilder) GreaterThanOrEqualTo(v float64) FilterBuilder {
+	return b.build(&NumberFilterCondition{GreaterThanOrEqualTo: &v})
+}
+
+func (b NumberConditionBuilder) LessThanOrEqualTo(v float64) FilterBuilder {
+	return b.build(&NumberFilterCondition{LessThanOrEqualTo: &v})
+}
+
+func (b NumberConditionBuilder) IsEmpty() FilterBuilder {
+	return b.build(&NumberFilterCondition{IsEmpty: true})
+}
+
+func (b NumberConditionBuilder) IsNotEmpty() FilterBuilder {
+	return b.build(&NumberFilterCondition{IsNotEmpty: true})
+}
+
+// CheckboxConditionBuilder builds conditions on checkboxes.
+type CheckboxConditionBuilder struct {
+	build func(*CheckboxFilterCondition) FilterBuilder
+}
+
+// Equals matches checkboxes in the given state. As false values are omitted
+// from requests, Equals(false) is sent as does_not_equal true.
+func (b CheckboxConditionBuilder) Equals(v bool) FilterBuilder {
+	if !v {
+		return b.build(&CheckboxFilterCondition{DoesNotEqual: true})
+	}
+	return b.build(&CheckboxFilterCondition{Equals: true})
+}
+
+// DoesNotEqual matches checkboxes not in the given state. As false values are
+// omitted from requests, DoesNotEqual(false) is sent as equals true.
+func (b CheckboxConditionBuilder) DoesNotEqual(v bool) FilterBuilder {
+	return b.Equals(!v)
+}
+
+// SelectConditionBuilder builds conditions on select properties.
+type SelectConditionBuilder struct {
+	build func(*SelectFilterCondition) FilterBuilder
+}
+
+func (b SelectConditionBuilder) Equals(option string) FilterBuilder {
+	return b.build(&SelectFilterCondition{Equals: option})
+}
+
+func (b SelectConditionBuilder) DoesNotEqual(option string) FilterBuilder {
+	return b.build(&SelectFilterCondition{DoesNotEqual: option})
+}
+
+func (b SelectConditionBuilder) IsEmpty() FilterBuilder {
+	return b.build(&SelectFilterCondition{IsEmpty: true})
+}
+
+func (b SelectConditionBuilder) IsNotEmpty() FilterBuilder {
+	return b.build(&SelectFilterCondition{IsNotEmpty: true})
+}
+
+// MultiSelectConditionBuilder builds conditions on multi-select properties.
+type MultiSelectConditionBuilder struct {
+	build func(*MultiSelectFilterCondition) FilterBuilder
+}
+
+func (b MultiSelectConditionBuilder) Contains(option string) FilterBuilder {
+	return b.build(&MultiSelectFilterCondition{Contains: option})
+}
+
+func (b MultiSelectConditionBuilder) DoesNotContain(option string) FilterBuilder {
+	return b.build(&MultiSelectFilterCondition{DoesNotContain: option})
+}
+
+func (b MultiSelectConditionBuilder) IsEmpty() FilterBuilder {
+	return b.build(&MultiSelectFilterCondition{IsEmpty: true})
+}
+
+func (b MultiSelectConditionBuilder) IsNotEmpty() FilterBuilder {
+	return b.build(&MultiSelectFilterCondition{IsNotEmpty: true})
+}
+
+// StatusConditionBuilder builds conditions on status properties.
+type StatusConditionBuilder struct {
+	build func(*StatusFilterCondition) FilterBuilder
+}
+
+func (b StatusConditionBuilder) Equals(option string) FilterBuilder {
+	return b.build(&StatusFilterCondition{Equals: option})
+}
+
+func (b StatusConditionBuilder) DoesNotEqual(option string) FilterBuilder {
+	return b.build(&StatusFilterCondition{DoesNotEqual: option})
+}
+
+func (b StatusConditionBuilder) IsEmpty() FilterBuilder {
+	return b.build(&StatusFilterCondition{IsEmpty: true})
+}
+
+func (b StatusConditionBuilder) IsNotEmpty() FilterBuilder {
+	return b.build(&StatusFilterCondition{IsNotEmpty: true})
+}
+
+// DateConditionBuilder builds conditions on dates.
+type DateConditionBuilder struct {
+	build func(*DateFilterCondition) FilterBuilder
+}
+
+func (b DateConditionBuilder) Equals(t time.Time) FilterBuilder {
+	d := Date(t)
+	return b.build(&DateFilterCondition{Equals: &d})
+}
+
+func (b DateConditionBuilder) Before(t time.Time) FilterBuilder {
+	d := Date(t)
+	return b.build(&DateFilterCondition{Before: &d})
+}
+
+func (b DateConditionBuilder) After(t time.Time) FilterBuilder {
+	d := Date(t)
+	return b.build(&DateFilterCondition{After: &d})
+}
+
+func (b DateConditionBuilder) OnOrBefore(t time.Time) FilterBuilder {
+	d := Date(t)
+	return b.build(&DateFilterCondition{OnOrBefore: &d})
+}
+
+func (b DateConditionBuilder) OnOrAfter(t time.Time) FilterBuilder {
+	d := Date(t)
+	return b.build(&DateFilterCondition{OnOrAfter: &d})
+}
+
+func (b DateConditionBuilder) PastWeek() FilterBuilder {
+	return b.build(&DateFilterCondition{PastWeek: &struct{}{}})
+}
+
+func (b DateConditionBuilder) PastMonth() FilterBuilder {
+	return b.build(&DateFilterCondition{PastMonth: &struct{}{}})
+}
+
+func (b DateConditionBuilder) PastYear() FilterBuilder {
+	return b.build(&DateFilterCondition{PastYear: &struct{}{}})
+}
+
+func (b DateConditionBuilder) NextWeek() FilterBuilder {
+	return b.build(&DateFilterCondition{NextWeek: &struct{}{}})
+}
+
+func (b DateConditionBuilder) NextMonth() FilterBuilder {
+	return b.build(&DateFilterCondition{NextMonth: &struct{}{}})
+}
+
+func (b DateConditionBuilder) NextYear() FilterBuilder {
+	return b.build(&DateFilterCondition{NextYear: &struct{}{}})
+}
+
+func (b DateConditionBuilder) IsEmpty() FilterBuilder {
+	return b.build(&DateFilterCondition{IsEmpty: true})
+}
+
+func (b DateConditionBuilder) IsNotEmpty() FilterBuilder {
+	return b.build(&DateFilterCondition{IsNotEmpty: true})
+}
+
+// PeopleConditionBuilder builds conditions on people properties.
+type PeopleConditionBuilder struct {
+	build func(*PeopleFilterCondition) FilterBuilder
+}
+
+func (b PeopleConditionBuilder) Contains(id UserID) FilterBuilder {
+	return b.build(&PeopleFilterCondition{Contains: id.String()})
+}
+
+func (b PeopleConditionBuilder) DoesNotContain(id UserID) FilterBuilder {
+	return b.build(&PeopleFilterCondition{DoesNotContain: id.String()})
+}
+
+func (b PeopleConditionBuilder) IsEmpty() FilterBuilder {
+	return b.build(&PeopleFilterCondition{IsEmpty: true})
+}
+
+func (b PeopleConditionBuilder) IsNotEmpty() FilterBuilder {
+	return b.build(&PeopleFilterCondition{IsNotEmpty: true})
+}
+
+// FilesConditionBuilder builds conditions on files properties.
+type FilesConditionBuilder struct {
+	build func(*FilesFilterCondition) FilterBuilder
+}
+
+func (b FilesConditionBuilder) IsEmpty() FilterBuilder {
+	return b.build(&FilesFilterCondition{IsEmpty: true})
+}
+
+func (b FilesConditionBuilder) IsNotEmpty() FilterBuilder {
+	return b.build(&FilesFilterCondition{IsNotEmpty: true})
+}
+
+// RelationConditionBuilder builds conditions on relation properties.
+type RelationConditionBuilder struct {
+	build func(*RelationFilterCondition) FilterBuilder
+}
+
+func (b RelationConditionBuilder) Contains(id PageID) FilterBuilder {
+	return b.build(&RelationFilterCondition{Contains: id.String()})
+}
+
+func (b RelationConditionBuilder) DoesNotContain(id PageID) FilterBuilder {
+	return b.build(&RelationFilterCondition{DoesNotContain: id.String()})
+}
+
+func (b RelationConditionBuilder) IsEmpty() FilterBuilder {
+	return b.build(&RelationFilterCondition{IsEmpty: true})
+}
+
+func (b RelationConditionBuilder) IsNotEmpty() FilterBuilder {
+	return b.build(&RelationFilterCondition{IsNotEmpty: true})
+}
+
+// UniqueIDConditionBuilder builds conditions on unique ID properties.
+type UniqueIDConditionBuilder struct {
+	build func(*UniqueIdFilterCondition) FilterBuilder
+}
+
+func (b UniqueIDConditionBuilder) Equals(v int) FilterBuilder {
+	return b.build(&UniqueIdFilterCondition{Equals: &v})
+}
+
+func (b UniqueIDConditionBuilder) DoesNotEqual(v int) FilterBuilder {
+	return b.build(&UniqueIdFilterCondition{DoesNotEqual: &v})
+}
+
+func (b UniqueIDConditionBuilder) GreaterThan(v int) FilterBuilder {
+	return b.build(&UniqueIdFilterCondition{GreaterThan: &v})
+}
+
+func (b UniqueIDConditionBuilder) LessThan(v int) FilterBuilder {
+	return b.build(&UniqueIdFilterCondition{LessThan: &v})
+}
+
+func (b UniqueIDConditionBuilder) GreaterThanOrEqualTo(v int) FilterBuilder {
+	return b.build(&UniqueIdFilterCondition{GreaterThanOrEqualTo: &v})
+}
+
+func (b UniqueIDConditionBuilder) LessThanOrEqualTo(v int) FilterBuilder {
+	return b.build(&UniqueIdFilterCondition{LessThanOrEqualTo: &v})
+}
+
+// FormulaConditionBuilder selects the type of the result of a formula.
+type FormulaConditionBuilder struct {
+	build func(*FormulaFilterCondition) FilterBuilder
+}
+
+func (b FormulaConditionBuilder) String() TextConditionBuilder {
+	return TextConditionBuilder{func(c *TextFilterCondition) FilterBuilder { return b.build(&FormulaFilterCondition{String: c}) }}
+}
+
+func (b FormulaConditionBuilder) Checkbox() CheckboxConditionBuilder {
+	return CheckboxConditionBuilder{func(c *CheckboxFilterCondition) FilterBuilder { return b.build(&FormulaFilterCondition{Checkbox: c}) }}
+}
+
+func (b FormulaConditionBuilder) Number() NumberConditionBuilder {
+	return NumberConditionBuilder{func(c *NumberFilterCondition) FilterBuilder { return b.build(&FormulaFilterCondition{Number: c}) }}
+}
+
+func (b FormulaConditionBuilder) Date() DateConditionBuilder {
+	return DateConditionBuilder{func(c *DateFilterCondition) FilterBuilder { return b.build(&FormulaFilterCondition{Date: c}) }}
+}
+
+// RollupConditionBuilder builds conditions on rollup properties: on every
+// value of rollups computing an array, or on the result of rollups computing a
+// date or a number.
+type RollupConditionBuilder struct {
+	build func(*RollupFilterCondition) FilterBuilder
+}
+
+// Any matches rollups with at least one value matching the condition.
+func (b RollupConditionBuilder) Any() RollupSubfilterBuilder {
+	return RollupSubfilterBuilder{func(c *RollupSubfilterCondition) FilterBuilder { return b.build(&RollupFilterCondition{Any: c}) }}
+}
+
+// Every matches rollups whose values all match the condition.
+func (b RollupConditionBuilder) Every() RollupSubfilterBuilder {
+	return RollupSubfilterBuilder{func(c *RollupSubfilterCondition) FilterBuilder { return b.build(&RollupFilterCondition{Every: c}) }}
+}
+
+// None matches rollups with no value matching the condition.
+func (b RollupConditionBuilder) None() RollupSubfilterBuilder {
+	return RollupSubfilterBuilder{func(c *RollupSubfilterCondition) FilterBuilder { return b.build(&RollupFilterCondition{None: c}) }}
+}
+
+func (b RollupConditionBuilder) Date() DateConditionBuilder {
+	return DateConditionBuilder{func(c *DateFilterCondition) FilterBuilder { return b.build(&RollupFilterCondition{Date: c}) }}
+}
+
+func (b RollupConditionBuilder) Number() NumberConditionBuilder {
+	return NumberConditionBuilder{func(c *NumberFilterCondition) FilterBuilder { return b.build(&RollupFilterCondition{Number: c}) }}
+}
+
+// RollupSubfilterBuilder selects the type of the values of a rollup computing
+// an array.
+type RollupSubfilterBuilder struct {
+	build func(*RollupSubfilterCondition) FilterBuilder
+}
+
+func (b RollupSubfilterBuilder) RichText() TextConditionBuilder {
+	return TextConditionBuilder{func(c *TextFilterCondition) FilterBuilder { return b.build(&RollupSubfilterCondition{RichText: c}) }}
+}
+
+func (b RollupSubfilterBuilder) Number() NumberConditionBuilder {
+	return NumberConditionBuilder{func(c *NumberFilterCondition) FilterBuilder { return b.build(&RollupSubfilterCondition{Number: c}) }}
+}
+
+func (b RollupSubfilterBuilder) Checkbox() CheckboxConditionBuilder {
+	return CheckboxConditionBuilder{func(c *CheckboxFilterCondition) FilterBuilder { return b.build(&RollupSubfilterCondition{Checkbox: c}) }}
+}
+
+func (b RollupSubfilterBuilder) Select() SelectConditionBuilder {
+	return SelectConditionBuilder{func(c *SelectFilterCondition) FilterBuilder { return b.build(&RollupSubfilterCondition{Select: c}) }}
+}
+
+func (b RollupSubfilterBuilder) MultiSelect() MultiSelectConditionBuilder {
+	return MultiSelectConditionBuilder{func(c *MultiSelectFilterCondition) FilterBuilder {
+		return b.build(&RollupSubfilterCondition{MultiSelect: c})
+	}}
+}
+
+func (b RollupSubfilterBuilder) Relation() RelationConditionBuilder {
+	return RelationConditionBuilder{func(c *RelationFilterCondition) FilterBuilder { return b.build(&RollupSubfilterCondition{Relation: c}) }}
+}
+
+func (b RollupSubfilterBuilder) Date() DateConditionBuilder {
+	return DateConditionBuilder{func(c *DateFilterCondition) FilterBuilder { return b.build(&RollupSubfilterCondition{Date: c}) }}
+}
+
+func (b RollupSubfilterBuilder) People() PeopleConditionBuilder {
+	return PeopleConditionBuilder{func(c *PeopleFilterCondition) FilterBuilder { return b.build(&RollupSubfilterCondition{People: c}) }}
+}
+
+func (b RollupSubfilterBuilder) Files() FilesConditionBuilder {
+	return FilesConditionBuilder{func(c *FilesFilterCondition) FilterBuilder { return b.build(&RollupSubfilterCondition{Files: c}) }}
+}
@@ -0,0 +1,115 @@
+package notionapi_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+)
+
+func TestFilterBuilder(t *testing.T) {
+	date := time.Date(2021, 5, 10, 2, 43, 42, 0, time.UTC)
+	tests := []struct {
+		name    string
+		builder notionapi.FilterBuilder
+		want    string
+	}{
+		{
+			name:    "property",
+			builder: notionapi.Prop("Status").Status().Equals("Done"),
+			want:    `{"property":"Status","status":{"equals":"Done"}}`,
+		},
+		{
+			name:    "and",
+			builder: notionapi.Prop("Status").Status().Equals("Done").And(notionapi.Prop("Due").Date().PastWeek()),
+			want:    `{"and":[{"property":"Status","status":{"equals":"Done"}},{"property":"Due","date":{"past_week":{}}}]}`,
+		},
+		{
+			name: "chained operators extend the compound filter",
+			builder: notionapi.Prop("Name").Title().Contains("a").
+				Or(notionapi.Prop("Name").Title().Contains("b")).
+				Or(notionapi.Prop("Name").Title().Contains("c")),
+			want: `{"or":[{"property":"Name","title":{"contains":"a"}},{"property":"Name","title":{"contains":"b"}},{"property":"Name","title":{"contains":"c"}}]}`,
+		},
+		{
+			name: "nested",
+			builder: notionapi.And(
+				notionapi.Prop("Done").Checkbox().Equals(false),
+				notionapi.Or(
+					notionapi.Prop("Points").Number().GreaterThan(0),
+					notionapi.Prop("Tags").MultiSelect().Contains("urgent"),
+				),
+			),
+			want: `{"and":[{"property":"Done","checkbox":{"does_not_equal":true}},{"or":[{"property":"Points","number":{"greater_than":0}},{"property":"Tags","multi_select":{"contains":"urgent"}}]}]}`,
+		},
+		{
+			name:    "timestamp",
+			builder: notionapi.LastEditedTime().OnOrAfter(date),
+			want:    `{"timestamp":"last_edited_time","last_edited_time":{"on_or_after":"2021-05-10T02:43:42Z"}}`,
+		},
+		{
+			name:    "formula",
+			builder: notionapi.Prop("Total").Formula().Number().LessThanOrEqualTo(10),
+			want:    `{"property":"Total","formula":{"number":{"less_than_or_equal_to":10}}}`,
+		},
+		{
+			name:    "rollup",
+			builder: notionapi.Prop("Owners").Rollup().Any().People().Contains("user-1"),
+			want:    `{"property":"Owners","rollup":{"any":{"people":{"contains":"user-1"}}}}`,
+		},
+		{
+			name:    "unique ID",
+			builder: notionapi.Prop("ID").UniqueID().Equals(0),
+			want:    `{"property":"ID","unique_id":{"equals":0}}`,
+		},
+		{
+			name:    "relation",
+			builder: notionapi.Prop("Project").Relation().IsNotEmpty(),
+			want:    `{"property":"Project","relation":{"is_not_empty":true}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := tt.builder.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.Marshal(filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Build() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("validates nesting", func(t *testing.T) {
+		a := notionapi.Prop("A").Checkbox().Equals(true)
+		b := notionapi.Prop("B").Checkbox().Equals(true)
+		tooDeep := notionapi.And(a, notionapi.Or(b, notionapi.And(a, b)))
+		if _, err := tooDeep.Build(); err == nil {
+			t.Error("Build() error = nil for three levels of compound filters")
+		}
+		if _, err := notionapi.And().Build(); err == nil {
+			t.Error("Build() error = nil for an empty compound filter")
+		}
+		if _, err := (notionapi.FilterBuilder{}).Build(); err == nil {
+			t.Error("Build() error = nil for an empty filter")
+		}
+	})
+
+	t.Run("does not modify combined builders", func(t *testing.T) {
+		base := notionapi.And(notionapi.Prop("A").Checkbox().Equals(true), notionapi.Prop("B").Checkbox().Equals(true))
+		base.And(notionapi.Prop("C").Checkbox().Equals(true))
+		filter, err := base.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := len(filter.(notionapi.AndCompoundFilter)); n != 2 {
+			t.Errorf("Build() got %d filters, want 2", n)
+		}
+	})
+}
@@ -0,0 +1,631 @@
+package notionapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseFilter parses a filter expression into a Filter, for example:
+//
+//	Status = "Done" AND (Priority > 2 OR Tags contains "urgent") AND created_time past_week
+//
+// A condition is a property, an operator and a value. Properties are written
+// as identifiers, or between backquotes if they contain other characters, as
+// in `Due date`. Values are double-quoted strings, numbers, true or false;
+// dates are strings in the RFC 3339 or 2006-01-02 formats. Operators are =,
+// !=, >, <, >=, <= and the names of the conditions of the API, such as
+// contains, starts_with, on_or_after, is_empty or past_week, the last ones
+// taking no value. Conditions are combined with AND and OR, AND binding
+// tighter, and grouped with parentheses. created_time and last_edited_time
+// filter on the timestamps of pages.
+//
+// The kind of condition depends on the type of the property, which is taken
+// from properties, the schema of the database. It can also be given after
+// the property, with the path of the condition in the API, which is required
+// for rollups of arrays and when properties is nil:
+//
+//	Status:status = "Done" AND Total:formula.number > 10 AND Tags:rollup.any.select = "a"
+//
+// The result of formulas defaults to the type of the value, or to a date for
+// date operators.
+func ParseFilter(expr string, properties PropertyConfigs) (Filter, error) {
+	p := &filterParser{lexer: filterLexer{input: expr}, properties: properties}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	b, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokenEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	return b.Build()
+}
+
+// FilterSyntaxError is returned by ParseFilter for invalid expressions.
+type FilterSyntaxError struct {
+	// Offset is the offset in bytes of the error in the expression.
+	Offset  int
+	Message string
+}
+
+func (e *FilterSyntaxError) Error() string {
+	return fmt.Sprintf("notionapi: invalid filter expression at offset %d: %s", e.Offset, e.Message)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenColon
+	tokenDot
+)
+
+type token struct {
+	kind tokenKind
+	// text is the identifier, operator, number or unquoted string.
+	text string
+	// quoted is set for properties written between backquotes.
+	quoted bool
+	offset int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+type filterLexer struct {
+	input  string
+	offset int
+}
+
+func (l *filterLexer) next() (token, error) {
+	for l.offset < len(l.input) && unicode.IsSpace(rune(l.input[l.offset])) {
+		l.offset++
+	}
+	start := l.offset
+	if start == len(l.input) {
+		return token{kind: tokenEOF, offset: start}, nil
+	}
+
+	rest := l.input[start:]
+	switch c := rest[0]; {
+	case c == '(':
+		l.offset++
+		return token{kind: tokenLParen, text: "(", offset: start}, nil
+	case c == ')':
+		l.offset++
+		return token{kind: tokenRParen, text: ")", offset: start}, nil
+	case c == ':':
+		l.offset++
+		return token{kind: tokenColon, text: ":", offset: start}, nil
+	case c == '.':
+		l.offset++
+		return token{kind: tokenDot, text: ".", offset: start}, nil
+	case c == '"':
+		for i := 1; i < len(rest); i++ {
+			switch rest[i] {
+			case '\\':
+				i++
+			case '"':
+				text, err := strconv.Unquote(rest[:i+1])
+				if err != nil {
+					return token{}, &FilterSyntaxError{Offset: start, Message: fmt.Sprintf("invalid string %s", rest[:i+1])}
+				}
+				l.offset += i + 1
+				return token{kind: tokenString, text: text, offset: start}, nil
+			}
+		}
+		return token{}, &FilterSyntaxError{Offset: start, Message: "unterminated string"}
+	case c == '`':
+		var name strings.Builder
+		for i := 1; i < len(rest); i++ {
+			if rest[i] != '`' {
+				name.WriteByte(rest[i])
+				continue
+			}
+			// A doubled backquote stands for a backquote in the name.
+			if i+1 < len(rest) && rest[i+1] == '`' {
+				name.WriteByte('`')
+				i++
+				continue
+			}
+			l.offset += i + 1
+			return token{kind: tokenIdent, text: name.String(), quoted: true, offset: start}, nil
+		}
+		return token{}, &FilterSyntaxError{Offset: start, Message: "unterminated property name"}
+	case strings.HasPrefix(rest, ">=") || strings.HasPrefix(rest, "<=") || strings.HasPrefix(rest, "!="):
+		l.offset += 2
+		return token{kind: tokenOperator, text: rest[:2], offset: start}, nil
+	case c == '=' || c == '>' || c == '<':
+		l.offset++
+		return token{kind: tokenOperator, text: rest[:1], offset: start}, nil
+	case c == '-' || c >= '0' && c <= '9':
+		end := 1
+		for end < len(rest) && (rest[end] >= '0' && rest[end] <= '9' || rest[end] == '.' || rest[end] == 'e' || rest[end] == 'E') {
+			end++
+		}
+		if _, err := strconv.ParseFloat(rest[:end], 64); err != nil {
+			return token{}, &FilterSyntaxError{Offset: start, Message: fmt.Sprintf("invalid number %q", rest[:end])}
+		}
+		l.offset += end
+		return token{kind: tokenNumber, text: rest[:end], offset: start}, nil
+	}
+
+	end := 0
+	for end < len(rest) {
+		r := rune(rest[end])
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) && r < 0x80 {
+			break
+		}
+		end++
+	}
+	if end == 0 {
+		return token{}, &FilterSyntaxError{Offset: start, Message: fmt.Sprintf("unexpected character %q", rest[0])}
+	}
+	l.offset += end
+	return token{kind: tokenIdent, text: rest[:end], offset: start}, nil
+}
+
+// filterOperators maps the symbolic operators of expressions to the
+// conditions of the API.
+var filterOperators = map[string]string{
+	"=":  "equals",
+	"!=": "does_not_equal",
+	">":  "greater_than",
+	"<":  "less_than",
+	">=": "greater_than_or_equal_to",
+	"<=": "less_than_or_equal_to",
+}
+
+// unaryFilterOperators are the conditions of the API taking no value, with
+// the value sent for them.
+var unaryFilterOperators = map[string]interface{}{
+	"is_empty":     true,
+	"is_not_empty": true,
+	"past_week":    struct{}{},
+	"past_month":   struct{}{},
+	"past_year":    struct{}{},
+	"next_week":    struct{}{},
+	"next_month":   struct{}{},
+	"next_year":    struct{}{},
+}
+
+// dateFilterOperators are the conditions only applying to dates.
+var dateFilterOperators = map[string]bool{
+	"before": true, "after": true, "on_or_before": true, "on_or_after": true,
+	"past_week": true, "past_month": true, "past_year": true,
+	"next_week": true, "next_month": true, "next_year": true,
+}
+
+type filterParser struct {
+	lexer      filterLexer
+	tok        token
+	properties PropertyConfigs
+}
+
+func (p *filterParser) next() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *filterParser) errorf(format string, args ...interface{}) error {
+	return &FilterSyntaxError{Offset: p.tok.offset, Message: fmt.Sprintf(format, args...)}
+}
+
+// keyword reports whether the current token is the given keyword, ignoring
+// case.
+func (p *filterParser) keyword(k string) bool {
+	return p.tok.kind == tokenIdent && !p.tok.quoted && strings.EqualFold(p.tok.text, k)
+}
+
+func (p *filterParser) or() (FilterBuilder, error) {
+	b, err := p.and()
+	if err != nil {
+		return FilterBuilder{}, err
+	}
+	filters := []FilterBuilder{b}
+	for p.keyword("or") {
+		if err := p.next(); err != nil {
+			return FilterBuilder{}, err
+		}
+		b, err := p.and()
+		if err != nil {
+			return FilterBuilder{}, err
+		}
+		filters = append(filters, b)
+	}
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+	return Or(filters...), nil
+}
+
+func (p *filterParser) and() (FilterBuilder, error) {
+	b, err := p.operand()
+	if err != nil {
+		return FilterBuilder{}, err
+	}
+	filters := []FilterBuilder{b}
+	for p.keyword("and") {
+		if err := p.next(); err != nil {
+			return FilterBuilder{}, err
+		}
+		b, err := p.operand()
+		if err != nil {
+			return FilterBuilder{}, err
+		}
+		filters = append(filters, b)
+	}
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+	return And(filters...), nil
+}
+
+func (p *filterParser) operand() (FilterBuilder, error) {
+	if p.tok.kind != tokenLParen {
+		return p.condition()
+	}
+	if err := p.next(); err != nil {
+		return FilterBuilder{}, err
+	}
+	b, err := p.or()
+	if err != nil {
+		return FilterBuilder{}, err
+	}
+	if p.tok.kind != tokenRParen {
+		return FilterBuilder{}, p.errorf("expected \")\", got %s", p.tok)
+	}
+	if err := p.next(); err != nil {
+		return FilterBuilder{}, err
+	}
+	// Parentheses group filters: they are not merged into the enclosing
+	// compound filter.
+	b.op = ""
+	return b, nil
+}
+
+func (p *filterParser) condition() (FilterBuilder, error) {
+	if p.tok.kind != tokenIdent || p.keyword("and") || p.keyword("or") {
+		return FilterBuilder{}, p.errorf("expected a property, got %s", p.tok)
+	}
+	start := p.tok
+	if err := p.next(); err != nil {
+		return FilterBuilder{}, err
+	}
+
+	// The path of the condition in the API, if given.
+	var path []string
+	if p.tok.kind == tokenColon {
+		for {
+			if err := p.next(); err != nil {
+				return FilterBuilder{}, err
+			}
+			if p.tok.kind != tokenIdent || p.tok.quoted {
+				return FilterBuilder{}, p.errorf("expected a property type, got %s", p.tok)
+			}
+			path = append(path, p.tok.text)
+			if err := p.next(); err != nil {
+				return FilterBuilder{}, err
+			}
+			if p.tok.kind != tokenDot {
+				break
+			}
+		}
+	}
+
+	opTok := p.tok
+	op, ok := filterOperators[p.tok.text]
+	if p.tok.kind == tokenIdent && !p.tok.quoted {
+		op, ok = strings.ToLower(p.tok.text), true
+	}
+	if !ok {
+		return FilterBuilder{}, p.errorf("expected an operator, got %s", p.tok)
+	}
+	if err := p.next(); err != nil {
+		return FilterBuilder{}, err
+	}
+
+	value, unary := unaryFilterOperators[op]
+	if !unary {
+		switch {
+		case p.tok.kind == tokenString:
+			// Empty strings are omitted from conditions, which would be left
+			// without operator.
+			if p.tok.text == "" {
+				return FilterBuilder{}, p.errorf("expected a value, got an empty string; use is_empty instead")
+			}
+			value = p.tok.text
+		case p.tok.kind == tokenNumber:
+			value = json.Number(p.tok.text)
+		case p.keyword("true"):
+			value = true
+		case p.keyword("false"):
+			value = false
+		default:
+			return FilterBuilder{}, p.errorf("expected a value, got %s", p.tok)
+		}
+		if err := p.next(); err != nil {
+			return FilterBuilder{}, err
+		}
+	}
+
+	if !start.quoted && path == nil && (start.text == string(TimestampCreated) || start.text == string(TimestampLastEdited)) {
+		return p.timestampCondition(start, opTok, op, value)
+	}
+	return p.propertyCondition(start, path, opTok, op, value)
+}
+
+func (p *filterParser) timestampCondition(start, opTok token, op string, value interface{}) (FilterBuilder, error) {
+	var f TimestampFilter
+	data, _ := json.Marshal(map[string]interface{}{
+		"timestamp": start.text,
+		start.text:  map[string]interface{}{op: value},
+	})
+	if err := decodeStrict(data, &f); err != nil {
+		return FilterBuilder{}, &FilterSyntaxError{Offset: opTok.offset, Message: fmt.Sprintf("invalid condition %s on %s: %v", op, start.text, err)}
+	}
+	return FilterBuilder{filter: f}, nil
+}
+
+func (p *filterParser) propertyCondition(start token, path []string, opTok token, op string, value interface{}) (FilterBuilder, error) {
+	if path == nil {
+		var err error
+		if path, err = p.conditionPath(start.text, op, value); err != nil {
+			return FilterBuilder{}, &FilterSyntaxError{Offset: start.offset, Message: err.Error()}
+		}
+	}
+
+	// Checkboxes equal to false are sent as not equal to true, as false
+	// values are omitted from requests.
+	if b, ok := value.(bool); ok && !b && path[len(path)-1] == "checkbox" && (op == "equals" || op == "does_not_equal") {
+		value = true
+		if op == "equals" {
+			op = "does_not_equal"
+		} else {
+			op = "equals"
+		}
+	}
+
+	var condition interface{} = map[string]interface{}{op: value}
+	for i := len(path) - 1; i >= 0; i-- {
+		key := path[i]
+		// The multi_select condition of rollups is spelled multiSelect.
+		if i > 0 && path[0] == "rollup" && key == "multi_select" {
+			key = "multiSelect"
+		}
+		condition = map[string]interface{}{key: condition}
+	}
+	m := condition.(map[string]interface{})
+	m["property"] = start.text
+	data, _ := json.Marshal(m)
+
+	var f PropertyFilter
+	if err := decodeStrict(data, &f); err != nil {
+		return FilterBuilder{}, &FilterSyntaxError{Offset: opTok.offset, Message: fmt.Sprintf("invalid condition %s on %s property %q: %v", op, strings.Join(path, "."), start.text, err)}
+	}
+	return FilterBuilder{filter: f}, nil
+}
+
+// conditionPath returns the path of a condition on a property from its type
+// in the schema.
+func (p *filterParser) conditionPath(property, op string, value interface{}) ([]string, error) {
+	if p.properties == nil {
+		return nil, fmt.Errorf("the type of property %q must be given without a schema, as in %s:type", property, formatPropertyName(property))
+	}
+	config, ok := p.properties[property]
+	if !ok {
+		for _, c := range p.properties {
+			if string(c.GetID()) == property {
+				config, ok = c, true
+				break
+			}
+		}
+	}
+	if !ok || config.GetType() == "" {
+		return nil, fmt.Errorf("property %q does not exist", property)
+	}
+
+	kind := string(config.GetType())
+	switch config.GetType() {
+	case PropertyConfigTypeFormula:
+		// Unary operators carry no value telling the type of the result.
+		if _, unary := unaryFilterOperators[op]; !unary {
+			switch value.(type) {
+			case json.Number:
+				return []string{kind, "number"}, nil
+			case bool:
+				return []string{kind, "checkbox"}, nil
+			}
+		}
+		if dateFilterOperators[op] {
+			return []string{kind, "date"}, nil
+		}
+		return []string{kind, "string"}, nil
+	case PropertyConfigTypeRollup:
+		var function FunctionType
+		switch c := config.(type) {
+		case *RollupPropertyConfig:
+			function = c.Rollup.Function
+		case RollupPropertyConfig:
+			function = c.Rollup.Function
+		}
+		result := rollupResult(function)
+		if len(result) != 1 {
+			return nil, fmt.Errorf("the condition on rollup %q must be given, as in %s:rollup.any.select", property, formatPropertyName(property))
+		}
+		return []string{kind, result[0]}, nil
+	}
+	return []string{kind}, nil
+}
+
+// decodeStrict decodes JSON, failing on unknown fields.
+func decodeStrict(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// FormatFilter returns the expression of a filter, as parsed by ParseFilter.
+// The type of every property is written so that the expression can be parsed
+// without the schema of the database.
+func FormatFilter(f Filter) (string, error) {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return "", err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v map[string]interface{}
+	if err := dec.Decode(&v); err != nil {
+		return "", err
+	}
+	var buf strings.Builder
+	if err := formatFilter(&buf, v, true); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func formatFilter(buf *strings.Builder, f map[string]interface{}, top bool) error {
+	for _, op := range []string{"and", "or"} {
+		filters, ok := f[op].([]interface{})
+		if !ok {
+			continue
+		}
+		if len(filters) == 0 {
+			return fmt.Errorf("notionapi: cannot format an empty %s filter", op)
+		}
+		if !top {
+			buf.WriteByte('(')
+		}
+		for i, sub := range filters {
+			if i > 0 {
+				buf.WriteString(" " + strings.ToUpper(op) + " ")
+			}
+			m, _ := sub.(map[string]interface{})
+			if err := formatFilter(buf, m, false); err != nil {
+				return err
+			}
+		}
+		if !top {
+			buf.WriteByte(')')
+		}
+		return nil
+	}
+
+	if ts, ok := f["timestamp"].(string); ok {
+		buf.WriteString(ts)
+		cond, _ := f[ts].(map[string]interface{})
+		return formatCondition(buf, ts, cond)
+	}
+
+	property, ok := f["property"].(string)
+	if !ok {
+		return fmt.Errorf("notionapi: cannot format filter %v", f)
+	}
+	var path []string
+	var cond map[string]interface{}
+	for k, v := range f {
+		if k != "property" {
+			path = []string{k}
+			cond, _ = v.(map[string]interface{})
+		}
+	}
+	if path == nil {
+		return fmt.Errorf("notionapi: filter on property %q has no condition", property)
+	}
+	// Nested conditions have a single key whose value is a non-empty object,
+	// unlike operators whose values are scalars or empty objects.
+	for len(cond) == 1 {
+		var key string
+		var value interface{}
+		for key, value = range cond {
+		}
+		nested, ok := value.(map[string]interface{})
+		if !ok || len(nested) == 0 {
+			break
+		}
+		if key == "multiSelect" {
+			key = "multi_select"
+		}
+		path = append(path, key)
+		cond = nested
+	}
+	buf.WriteString(formatPropertyName(property) + ":" + strings.Join(path, "."))
+	return formatCondition(buf, property, cond)
+}
+
+func formatCondition(buf *strings.Builder, name string, cond map[string]interface{}) error {
+	if len(cond) != 1 {
+		return fmt.Errorf("notionapi: condition on %q should have exactly one operator, got %d", name, len(cond))
+	}
+	for op, value := range cond {
+		symbol := op
+		for s, o := range filterOperators {
+			if o == op {
+				symbol = s
+			}
+		}
+		buf.WriteString(" " + symbol)
+		if _, unary := unaryFilterOperators[op]; unary {
+			return nil
+		}
+		switch v := value.(type) {
+		case string:
+			buf.WriteString(" " + strconv.Quote(v))
+		case json.Number:
+			buf.WriteString(" " + v.String())
+		case bool:
+			buf.WriteString(" " + strconv.FormatBool(v))
+		default:
+			return fmt.Errorf("notionapi: cannot format value %v of condition on %q", value, name)
+		}
+	}
+	return nil
+}
+
+// filterKeywords are the identifiers which cannot be used for property names
+// without backquotes.
+var filterKeywords = []string{"and", "or", "true", "false", string(TimestampCreated), string(TimestampLastEdited)}
+
+// formatPropertyName returns the name of a property in an expression.
+func formatPropertyName(name string) string {
+	plain := name != "" && !(name[0] >= '0' && name[0] <= '9' || name[0] == '-')
+	for _, r := range name {
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) && r < 0x80 {
+			plain = false
+		}
+	}
+	for _, k := range filterKeywords {
+		if strings.EqualFold(name, k) {
+			plain = false
+		}
+	}
+	if plain {
+		return name
+	}
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
@@ -0,0 +1,155 @@
+package notionapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jomei/notionapi"
+)
+
+func TestParseFilter(t *testing.T) {
+	properties := notionapi.PropertyConfigs{
+		"Name":     &notionapi.TitlePropertyConfig{Type: notionapi.PropertyConfigTypeTitle},
+		"Status":   &notionapi.StatusPropertyConfig{Type: notionapi.PropertyConfigStatus},
+		"Priority": &notionapi.NumberPropertyConfig{Type: notionapi.PropertyConfigTypeNumber},
+		"Tags":     &notionapi.MultiSelectPropertyConfig{Type: notionapi.PropertyConfigTypeMultiSelect},
+		"Done":     &notionapi.CheckboxPropertyConfig{Type: notionapi.PropertyConfigTypeCheckbox},
+		"Due date": &notionapi.DatePropertyConfig{Type: notionapi.PropertyConfigTypeDate},
+		"Total":    &notionapi.FormulaPropertyConfig{Type: notionapi.PropertyConfigTypeFormula},
+		"Count": &notionapi.RollupPropertyConfig{
+			Type:   notionapi.PropertyConfigTypeRollup,
+			Rollup: notionapi.RollupConfig{Function: notionapi.FunctionSum},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expr       string
+		properties notionapi.PropertyConfigs
+		want       string
+		formatted  string
+	}{
+		{
+			name:       "example",
+			expr:       `Status = "Done" AND (Priority > 2 OR Tags contains "urgent") AND created_time past_week`,
+			properties: properties,
+			want:       `{"and":[{"property":"Status","status":{"equals":"Done"}},{"or":[{"property":"Priority","number":{"greater_than":2}},{"property":"Tags","multi_select":{"contains":"urgent"}}]},{"timestamp":"created_time","created_time":{"past_week":{}}}]}`,
+			formatted:  `Status:status = "Done" AND (Priority:number > 2 OR Tags:multi_select contains "urgent") AND created_time past_week`,
+		},
+		{
+			name:       "AND binds tighter than OR",
+			expr:       `Done = true or Name starts_with "A" and Priority <= -1.5`,
+			properties: properties,
+			want:       `{"or":[{"property":"Done","checkbox":{"equals":true}},{"and":[{"property":"Name","title":{"starts_with":"A"}},{"property":"Priority","number":{"less_than_or_equal_to":-1.5}}]}]}`,
+			formatted:  `Done:checkbox = true OR (Name:title starts_with "A" AND Priority:number <= -1.5)`,
+		},
+		{
+			name:       "quoted properties and dates",
+			expr:       "`Due date` on_or_after \"2024-01-01\"",
+			properties: properties,
+			want:       `{"property":"Due date","date":{"on_or_after":"2024-01-01T00:00:00Z"}}`,
+			formatted:  "`Due date`:date on_or_after \"2024-01-01T00:00:00Z\"",
+		},
+		{
+			name:       "checkboxes equal to false",
+			expr:       `Done = false`,
+			properties: properties,
+			want:       `{"property":"Done","checkbox":{"does_not_equal":true}}`,
+			formatted:  `Done:checkbox != true`,
+		},
+		{
+			name:       "formulas and rollups from the schema",
+			expr:       `Total >= 10 AND Count is_empty`,
+			properties: properties,
+			want:       `{"and":[{"property":"Total","formula":{"number":{"greater_than_or_equal_to":10}}},{"property":"Count","rollup":{"number":{"is_empty":true}}}]}`,
+			formatted:  `Total:formula.number >= 10 AND Count:rollup.number is_empty`,
+		},
+		{
+			name:       "unary conditions on formulas",
+			expr:       `Total is_empty OR Total past_week`,
+			properties: properties,
+			want:       `{"or":[{"property":"Total","formula":{"string":{"is_empty":true}}},{"property":"Total","formula":{"date":{"past_week":{}}}}]}`,
+			formatted:  `Total:formula.string is_empty OR Total:formula.date past_week`,
+		},
+		{
+			name:      "explicit types without schema",
+			expr:      `Owners:rollup.any.multi_select contains "x" AND Verified:formula.checkbox = true`,
+			want:      `{"and":[{"property":"Owners","rollup":{"any":{"multiSelect":{"contains":"x"}}}},{"property":"Verified","formula":{"checkbox":{"equals":true}}}]}`,
+			formatted: `Owners:rollup.any.multi_select contains "x" AND Verified:formula.checkbox = true`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := notionapi.ParseFilter(tt.expr, tt.properties)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.Marshal(filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ParseFilter() = %s, want %s", got, tt.want)
+			}
+
+			formatted, err := notionapi.FormatFilter(filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if formatted != tt.formatted {
+				t.Errorf("FormatFilter() = %s, want %s", formatted, tt.formatted)
+			}
+			reparsed, err := notionapi.ParseFilter(formatted, nil)
+			if err != nil {
+				t.Fatalf("ParseFilter(FormatFilter()) error = %v", err)
+			}
+			if again, _ := json.Marshal(reparsed); string(again) != tt.want {
+				t.Errorf("ParseFilter(FormatFilter()) = %s, want %s", again, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilter_Errors(t *testing.T) {
+	properties := notionapi.PropertyConfigs{
+		"Status": &notionapi.StatusPropertyConfig{Type: notionapi.PropertyConfigStatus},
+		"Tags": &notionapi.RollupPropertyConfig{
+			Type:   notionapi.PropertyConfigTypeRollup,
+			Rollup: notionapi.RollupConfig{Function: notionapi.FunctionShowOriginal},
+		},
+	}
+	tests := []struct {
+		name   string
+		expr   string
+		offset int
+	}{
+		{"unknown property", `Missing = "x"`, 0},
+		{"operator of another type", `Status > 2`, 7},
+		{"missing value", `Status =`, 8},
+		{"empty string", `Status = ""`, 9},
+		{"unterminated string", `Status = "Done`, 9},
+		{"unbalanced parentheses", `(Status = "Done"`, 16},
+		{"trailing tokens", `Status = "Done" "x"`, 16},
+		{"rollup of arrays without type", `Tags contains "x"`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := notionapi.ParseFilter(tt.expr, properties)
+			var syntaxErr *notionapi.FilterSyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("ParseFilter() error = %v, want a *FilterSyntaxError", err)
+			}
+			if syntaxErr.Offset != tt.offset {
+				t.Errorf("ParseFilter() error at offset %d, want %d: %v", syntaxErr.Offset, tt.offset, err)
+			}
+		})
+	}
+
+	t.Run("nesting", func(t *testing.T) {
+		if _, err := notionapi.ParseFilter(`A:checkbox = true AND (B:checkbox = true OR (A:checkbox = true AND B:checkbox = true))`, nil); err == nil {
+			t.Error("ParseFilter() error = nil for three levels of compound filters")
+		}
+	})
+}
@@ -1,3 +1,3 @@
 module github.com/jomei/notionapi
 
-go 1.14
+go 1.18
@@ -0,0 +1,399 @@
+package notionapi
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+)
+
+// HTMLRenderer renders blocks and rich text as HTML.
+//
+// Colors are rendered as CSS classes named after the Color constants, for
+// example "notion-red" and "notion-red-background", so that pages can be
+// styled with a stylesheet. The zero value is ready to use.
+type HTMLRenderer struct {
+	// RewriteImageURL, if set, is called with the URL of every image and
+	// returns the URL to render instead, for example to serve a copy of
+	// Notion-hosted files that doesn't expire.
+	RewriteImageURL func(b Block, url string) string
+	// RewriteFileURL, if set, is called with the URL of every video, audio,
+	// file and pdf and returns the URL to render instead.
+	RewriteFileURL func(b Block, url string) string
+}
+
+// BlocksToHTML renders blocks as HTML using the default HTMLRenderer.
+func BlocksToHTML(blocks Blocks) string {
+	return (&HTMLRenderer{}).RenderBlocks(blocks)
+}
+
+// RichTextToHTML renders rich text as HTML using the default HTMLRenderer.
+func RichTextToHTML(richText []RichText) string {
+	return (&HTMLRenderer{}).RenderRichText(richText)
+}
+
+// ColorClass returns the CSS class used to render the color, or an empty
+// string for the default color.
+func ColorClass(c Color) string {
+	if c == "" || c == ColorDefault || c == ColorDefaultBackground {
+		return ""
+	}
+	return "notion-" + strings.ReplaceAll(c.String(), "_", "-")
+}
+
+// RenderBlocks renders blocks as HTML. Nested blocks are rendered from the
+// Children field of each block, so the blocks should be fetched with
+// BlockClient.GetTree to render a full page.
+func (r *HTMLRenderer) RenderBlocks(blocks Blocks) string {
+	var sb strings.Builder
+	r.writeBlocks(&sb, blocks)
+	return sb.String()
+}
+
+// RenderRichText renders rich text as inline HTML.
+func (r *HTMLRenderer) RenderRichText(richText []RichText) string {
+	var sb strings.Builder
+	for _, rt := range richText {
+		r.writeRichText(&sb, rt)
+	}
+	return sb.String()
+}
+
+func (r *HTMLRenderer) writeRichText(sb *strings.Builder, rt RichText) {
+	if rt.Equation != nil {
+		sb.WriteString(`<span class="notion-equation">`)
+		sb.WriteString(html.EscapeString(rt.Equation.Expression))
+		sb.WriteString(`</span>`)
+		return
+	}
+
+	content := rt.PlainText
+	if rt.Text != nil {
+		content = rt.Text.Content
+	}
+	if content == "" {
+		return
+	}
+	text := strings.ReplaceAll(html.EscapeString(content), "\n", "<br>")
+
+	if a := rt.Annotations; a != nil {
+		if a.Code {
+			text = "<code>" + text + "</code>"
+		}
+		if a.Underline {
+			text = "<u>" + text + "</u>"
+		}
+		if a.Strikethrough {
+			text = "<s>" + text + "</s>"
+		}
+		if a.Italic {
+			text = "<em>" + text + "</em>"
+		}
+		if a.Bold {
+			text = "<strong>" + text + "</strong>"
+		}
+		if class := ColorClass(a.Color); class != "" {
+			text = fmt.Sprintf(`<span class="%s">%s</span>`, class, text)
+		}
+	}
+	if rt.Mention != nil {
+		text = fmt.Sprintf(`<span class="notion-mention">%s</span>`, text)
+	}
+
+	href := rt.Href
+	if rt.Text != nil && rt.Text.Link != nil && rt.Text.Link.Url != "" {
+		href = rt.Text.Link.Url
+	}
+	if href != "" && safeLinkURL(href) {
+		text = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(href), text)
+	}
+	sb.WriteString(text)
+}
+
+func (r *HTMLRenderer) writeBlocks(sb *strings.Builder, blocks Blocks) {
+	var list BlockType
+	for _, b := range blocks {
+		// Consecutive list items of the same type share a list element.
+		next := markdownListType(b)
+		if next != list && list != "" {
+			sb.WriteString("</" + htmlListTag(list) + ">")
+		}
+		if next != list && next != "" {
+			sb.WriteString("<" + htmlListTag(next))
+			if next == BlockTypeToDo {
+				sb.WriteString(` class="notion-to-do"`)
+			}
+			sb.WriteString(">")
+		}
+		list = next
+
+		if list != "" {
+			r.writeListItem(sb, b)
+		} else {
+			r.writeBlock(sb, b)
+		}
+	}
+	if list != "" {
+		sb.WriteString("</" + htmlListTag(list) + ">")
+	}
+}
+
+func htmlListTag(list BlockType) string {
+	if list == BlockTypeNumberedListItem {
+		return "ol"
+	}
+	return "ul"
+}
+
+func (r *HTMLRenderer) writeListItem(sb *strings.Builder, b Block) {
+	switch b := b.(type) {
+	case *BulletedListItemBlock:
+		r.writeElement(sb, "li", b.BulletedListItem.Color, b.BulletedListItem.RichText, b.BulletedListItem.Children)
+	case *NumberedListItemBlock:
+		r.writeElement(sb, "li", b.NumberedListItem.Color, b.NumberedListItem.RichText, b.NumberedListItem.Children)
+	case *ToDoBlock:
+		sb.WriteString("<li" + htmlClass(b.ToDo.Color) + ">")
+		if b.ToDo.Checked {
+			sb.WriteString(`<input type="checkbox" disabled checked> `)
+		} else {
+			sb.WriteString(`<input type="checkbox" disabled> `)
+		}
+		sb.WriteString(r.RenderRichText(b.ToDo.RichText))
+		r.writeBlocks(sb, b.ToDo.Children)
+		sb.WriteString("</li>")
+	}
+}
+
+// writeElement writes the rich text and the children of a block in an
+// element with the given tag.
+func (r *HTMLRenderer) writeElement(sb *strings.Builder, tag, color string, richText []RichText, children Blocks) {
+	sb.WriteString("<" + tag + htmlClass(color) + ">")
+	sb.WriteString(r.RenderRichText(richText))
+	r.writeBlocks(sb, children)
+	sb.WriteString("</" + tag + ">")
+}
+
+func htmlClass(color string, classes ...string) string {
+	if c := ColorClass(Color(color)); c != "" {
+		classes = append(classes, c)
+	}
+	if len(classes) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(` class="%s"`, strings.Join(classes, " "))
+}
+
+func (r *HTMLRenderer) writeBlock(sb *strings.Builder, b Block) {
+	switch b := b.(type) {
+	case *ParagraphBlock:
+		r.writeElement(sb, "p", b.Paragraph.Color, b.Paragraph.RichText, nil)
+		if len(b.Paragraph.Children) > 0 {
+			sb.WriteString(`<div class="notion-indent">`)
+			r.writeBlocks(sb, b.Paragraph.Children)
+			sb.WriteString(`</div>`)
+		}
+	case *Heading1Block:
+		r.writeHeading(sb, "h1", b.Heading1)
+	case *Heading2Block:
+		r.writeHeading(sb, "h2", b.Heading2)
+	case *Heading3Block:
+		r.writeHeading(sb, "h3", b.Heading3)
+	case *ToggleBlock:
+		sb.WriteString("<details" + htmlClass(b.Toggle.Color) + "><summary>")
+		sb.WriteString(r.RenderRichText(b.Toggle.RichText))
+		sb.WriteString("</summary>")
+		r.writeBlocks(sb, b.Toggle.Children)
+		sb.WriteString("</details>")
+	case *QuoteBlock:
+		r.writeElement(sb, "blockquote", b.Quote.Color, b.Quote.RichText, b.Quote.Children)
+	case *CalloutBlock:
+		sb.WriteString("<div" + htmlClass(b.Callout.Color, "notion-callout") + ">")
+		if icon := b.Callout.Icon; icon != nil {
+			sb.WriteString(`<span class="notion-callout-icon">`)
+			if icon.Emoji != nil {
+				sb.WriteString(html.EscapeString(string(*icon.Emoji)))
+			} else if u := icon.GetURL(); u != "" {
+				fmt.Fprintf(sb, `<img src="%s" alt="">`, html.EscapeString(r.imageURL(b, u)))
+			}
+			sb.WriteString(`</span>`)
+		}
+		r.writeElement(sb, "div", "", b.Callout.RichText, b.Callout.Children)
+		sb.WriteString("</div>")
+	case *CodeBlock:
+		sb.WriteString("<pre><code")
+		if b.Code.Language != "" && b.Code.Language != "plain text" {
+			fmt.Fprintf(sb, ` class="language-%s"`, html.EscapeString(strings.ReplaceAll(b.Code.Language, " ", "-")))
+		}
+		sb.WriteString(">")
+		sb.WriteString(html.EscapeString(concatenateRichText(b.Code.RichText)))
+		sb.WriteString("</code></pre>")
+		r.writeCaption(sb, "div", b.Code.Caption)
+	case *EquationBlock:
+		sb.WriteString(`<div class="notion-equation">`)
+		sb.WriteString(html.EscapeString(b.Equation.Expression))
+		sb.WriteString(`</div>`)
+	case *DividerBlock:
+		sb.WriteString("<hr>")
+	case *TableBlock:
+		r.writeTable(sb, b.Table)
+	case *ColumnListBlock:
+		sb.WriteString(`<div class="notion-column-list" style="display:flex">`)
+		r.writeBlocks(sb, b.ColumnList.Children)
+		sb.WriteString(`</div>`)
+	case *ColumnBlock:
+		sb.WriteString(`<div class="notion-column" style="flex:1">`)
+		r.writeBlocks(sb, b.Column.Children)
+		sb.WriteString(`</div>`)
+	case *ImageBlock:
+		sb.WriteString("<figure>")
+		fmt.Fprintf(sb, `<img src="%s" alt="%s">`, html.EscapeString(r.imageURL(b, b.Image.GetURL())), html.EscapeString(concatenateRichText(b.Image.Caption)))
+		r.writeCaption(sb, "figcaption", b.Image.Caption)
+		sb.WriteString("</figure>")
+	case *VideoBlock:
+		sb.WriteString("<figure>")
+		fmt.Fprintf(sb, `<video controls src="%s"></video>`, html.EscapeString(r.fileURL(b, fileURL(b.Video.File, b.Video.External))))
+		r.writeCaption(sb, "figcaption", b.Video.Caption)
+		sb.WriteString("</figure>")
+	case *AudioBlock:
+		sb.WriteString("<figure>")
+		fmt.Fprintf(sb, `<audio controls src="%s"></audio>`, html.EscapeString(r.fileURL(b, b.Audio.GetURL())))
+		r.writeCaption(sb, "figcaption", b.Audio.Caption)
+		sb.WriteString("</figure>")
+	case *FileBlock:
+		r.writeLink(sb, "notion-file", r.fileURL(b, b.GetURL()), b.File.Caption)
+	case *PdfBlock:
+		r.writeLink(sb, "notion-pdf", r.fileURL(b, b.GetURL()), b.Pdf.Caption)
+	case *BookmarkBlock:
+		r.writeLink(sb, "notion-bookmark", b.Bookmark.URL, b.Bookmark.Caption)
+	case *EmbedBlock:
+		r.writeLink(sb, "notion-embed", b.Embed.URL, b.Embed.Caption)
+	case *LinkPreviewBlock:
+		r.writeLink(sb, "notion-link-preview", b.LinkPreview.URL, nil)
+	case *ChildPageBlock:
+		fmt.Fprintf(sb, `<p class="notion-child-page"><a href="%s">%s</a></p>`, notionURL(string(b.ID)), html.EscapeString(b.ChildPage.Title))
+	case *ChildDatabaseBlock:
+		fmt.Fprintf(sb, `<p class="notion-child-database"><a href="%s">%s</a></p>`, notionURL(string(b.ID)), html.EscapeString(b.ChildDatabase.Title))
+	case *LinkToPageBlock:
+		id := string(b.LinkToPage.PageID)
+		if b.LinkToPage.DatabaseID != "" {
+			id = string(b.LinkToPage.DatabaseID)
+		}
+		fmt.Fprintf(sb, `<p class="notion-link-to-page"><a href="%[1]s">%[1]s</a></p>`, notionURL(id))
+	case *SyncedBlock:
+		r.writeBlocks(sb, b.SyncedBlock.Children)
+	case *TemplateBlock:
+		r.writeElement(sb, "div", "", b.Template.RichText, b.Template.Children)
+	}
+}
+
+func (r *HTMLRenderer) writeHeading(sb *strings.Builder, tag string, h Heading) {
+	if !h.IsToggleable {
+		r.writeElement(sb, tag, h.Color, h.RichText, nil)
+		r.writeBlocks(sb, h.Children)
+		return
+	}
+	sb.WriteString("<details><summary>")
+	r.writeElement(sb, tag, h.Color, h.RichText, nil)
+	sb.WriteString("</summary>")
+	r.writeBlocks(sb, h.Children)
+	sb.WriteString("</details>")
+}
+
+func (r *HTMLRenderer) writeCaption(sb *strings.Builder, tag string, caption []RichText) {
+	if len(caption) == 0 {
+		return
+	}
+	sb.WriteString("<" + tag + ` class="notion-caption">`)
+	sb.WriteString(r.RenderRichText(caption))
+	sb.WriteString("</" + tag + ">")
+}
+
+func (r *HTMLRenderer) writeLink(sb *strings.Builder, class, u string, caption []RichText) {
+	if u == "" {
+		return
+	}
+	text := r.RenderRichText(caption)
+	if text == "" {
+		text = html.EscapeString(u)
+	}
+	if !safeLinkURL(u) {
+		fmt.Fprintf(sb, `<p class="%s">%s</p>`, class, text)
+		return
+	}
+	fmt.Fprintf(sb, `<p class="%s"><a href="%s">%s</a></p>`, class, html.EscapeString(u), text)
+}
+
+// safeLinkURL reports whether a URL can be rendered as a link: http, https
+// and mailto URLs, and relative ones such as the links to Notion pages.
+// Links to other URLs, such as javascript: ones, are rendered as plain text.
+func safeLinkURL(u string) bool {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+	switch parsed.Scheme {
+	case "", "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+func (r *HTMLRenderer) writeTable(sb *strings.Builder, t Table) {
+	sb.WriteString(`<table class="notion-table">`)
+	rows := make([]*TableRowBlock, 0, len(t.Children))
+	for _, child := range t.Children {
+		if row, ok := child.(*TableRowBlock); ok {
+			rows = append(rows, row)
+		}
+	}
+	for i, row := range rows {
+		header := t.HasColumnHeader && i == 0
+		switch {
+		case header:
+			sb.WriteString("<thead>")
+		case i == 0 || (t.HasColumnHeader && i == 1):
+			sb.WriteString("<tbody>")
+		}
+
+		sb.WriteString("<tr>")
+		for j, cell := range row.TableRow.Cells {
+			switch {
+			case header:
+				sb.WriteString(`<th scope="col">`)
+				sb.WriteString(r.RenderRichText(cell))
+				sb.WriteString("</th>")
+			case t.HasRowHeader && j == 0:
+				sb.WriteString(`<th scope="row">`)
+				sb.WriteString(r.RenderRichText(cell))
+				sb.WriteString("</th>")
+			default:
+				sb.WriteString("<td>")
+				sb.WriteString(r.RenderRichText(cell))
+				sb.WriteString("</td>")
+			}
+		}
+		sb.WriteString("</tr>")
+
+		switch {
+		case header:
+			sb.WriteString("</thead>")
+		case i == len(rows)-1:
+			sb.WriteString("</tbody>")
+		}
+	}
+	sb.WriteString("</table>")
+}
+
+func (r *HTMLRenderer) imageURL(b Block, u string) string {
+	if r.RewriteImageURL != nil {
+		return r.RewriteImageURL(b, u)
+	}
+	return u
+}
+
+func (r *HTMLRenderer) fileURL(b Block, u string) string {
+	if r.RewriteFileURL != nil {
+		return r.RewriteFileURL(b, u)
+	}
+	return u
+}
@@ -0,0 +1,215 @@
+package notionapi_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jomei/notionapi"
+)
+
+func TestRichTextToHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		richText []notionapi.RichText
+		want     string
+	}{
+		{
+			name: "annotations and colors",
+			richText: []notionapi.RichText{
+				annotated("bold", notionapi.Annotations{Bold: true, Italic: true}),
+				text(" <and> "),
+				annotated("red", notionapi.Annotations{Color: notionapi.ColorRed}),
+				annotated("code", notionapi.Annotations{Code: true, Color: notionapi.ColorYellowBackground}),
+				annotated("plain", notionapi.Annotations{Color: notionapi.ColorDefault}),
+			},
+			want: `<strong><em>bold</em></strong> &lt;and&gt; <span class="notion-red">red</span>` +
+				`<span class="notion-yellow-background"><code>code</code></span>plain`,
+		},
+		{
+			name: "links, mentions and equations",
+			richText: []notionapi.RichText{
+				{
+					Type: notionapi.ObjectTypeText,
+					Text: &notionapi.Text{Content: "Notion", Link: &notionapi.Link{Url: "https://notion.so/?a=1&b=2"}},
+				},
+				text("\n"),
+				{
+					Type:      "mention",
+					Mention:   &notionapi.Mention{Type: notionapi.MentionTypePage, Page: &notionapi.PageMention{ID: "some_id"}},
+					PlainText: "Some page",
+					Href:      "https://www.notion.so/some_id",
+				},
+				{
+					Type:     "equation",
+					Equation: &notionapi.Equation{Expression: "a<b"},
+				},
+			},
+			want: `<a href="https://notion.so/?a=1&amp;b=2">Notion</a><br>` +
+				`<a href="https://www.notion.so/some_id"><span class="notion-mention">Some page</span></a>` +
+				`<span class="notion-equation">a&lt;b</span>`,
+		},
+		{
+			name: "unsafe links",
+			richText: []notionapi.RichText{
+				{
+					Type: notionapi.ObjectTypeText,
+					Text: &notionapi.Text{Content: "click", Link: &notionapi.Link{Url: "javascript:alert(1)"}},
+				},
+				{
+					Type:      notionapi.ObjectTypeText,
+					Text:      &notionapi.Text{Content: " page"},
+					PlainText: " page",
+					Href:      "/some_id",
+				},
+				{
+					Type: notionapi.ObjectTypeText,
+					Text: &notionapi.Text{Content: " mail", Link: &notionapi.Link{Url: "mailto:a@example.com"}},
+				},
+			},
+			want: `click<a href="/some_id"> page</a><a href="mailto:a@example.com"> mail</a>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := notionapi.RichTextToHTML(tt.richText); got != tt.want {
+				t.Errorf("RichTextToHTML() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlocksToHTML(t *testing.T) {
+	tests := []struct {
+		name   string
+		blocks notionapi.Blocks
+		want   string
+	}{
+		{
+			name: "headings and paragraphs",
+			blocks: notionapi.Blocks{
+				&notionapi.Heading1Block{Heading1: notionapi.Heading{RichText: []notionapi.RichText{text("Title")}}},
+				&notionapi.ParagraphBlock{Paragraph: notionapi.Paragraph{
+					RichText: []notionapi.RichText{text("Hello")},
+					Color:    notionapi.ColorBlueBackground.String(),
+				}},
+				&notionapi.DividerBlock{},
+			},
+			want: `<h1>Title</h1><p class="notion-blue-background">Hello</p><hr>`,
+		},
+		{
+			name: "lists",
+			blocks: notionapi.Blocks{
+				&notionapi.NumberedListItemBlock{NumberedListItem: notionapi.ListItem{
+					RichText: []notionapi.RichText{text("first")},
+					Children: notionapi.Blocks{
+						&notionapi.BulletedListItemBlock{BulletedListItem: notionapi.ListItem{RichText: []notionapi.RichText{text("a")}}},
+					},
+				}},
+				&notionapi.NumberedListItemBlock{NumberedListItem: notionapi.ListItem{RichText: []notionapi.RichText{text("second")}}},
+				&notionapi.ToDoBlock{ToDo: notionapi.ToDo{RichText: []notionapi.RichText{text("done")}, Checked: true}},
+			},
+			want: `<ol><li>first<ul><li>a</li></ul></li><li>second</li></ol>` +
+				`<ul class="notion-to-do"><li><input type="checkbox" disabled checked> done</li></ul>`,
+		},
+		{
+			name: "columns",
+			blocks: notionapi.Blocks{
+				&notionapi.ColumnListBlock{ColumnList: notionapi.ColumnList{Children: notionapi.Blocks{
+					&notionapi.ColumnBlock{Column: notionapi.Column{Children: notionapi.Blocks{
+						&notionapi.ParagraphBlock{Paragraph: notionapi.Paragraph{RichText: []notionapi.RichText{text("left")}}},
+					}}},
+					&notionapi.ColumnBlock{Column: notionapi.Column{Children: notionapi.Blocks{
+						&notionapi.ParagraphBlock{Paragraph: notionapi.Paragraph{RichText: []notionapi.RichText{text("right")}}},
+					}}},
+				}}},
+			},
+			want: `<div class="notion-column-list" style="display:flex">` +
+				`<div class="notion-column" style="flex:1"><p>left</p></div>` +
+				`<div class="notion-column" style="flex:1"><p>right</p></div>` +
+				`</div>`,
+		},
+		{
+			name: "table with headers",
+			blocks: notionapi.Blocks{
+				&notionapi.TableBlock{Table: notionapi.Table{
+					TableWidth:      2,
+					HasColumnHeader: true,
+					HasRowHeader:    true,
+					Children: notionapi.Blocks{
+						&notionapi.TableRowBlock{TableRow: notionapi.TableRow{Cells: [][]notionapi.RichText{{text("Name")}, {text("Value")}}}},
+						&notionapi.TableRowBlock{TableRow: notionapi.TableRow{Cells: [][]notionapi.RichText{{text("a")}, {text("1")}}}},
+					},
+				}},
+			},
+			want: `<table class="notion-table">` +
+				`<thead><tr><th scope="col">Name</th><th scope="col">Value</th></tr></thead>` +
+				`<tbody><tr><th scope="row">a</th><td>1</td></tr></tbody>` +
+				`</table>`,
+		},
+		{
+			name: "code and toggle",
+			blocks: notionapi.Blocks{
+				&notionapi.CodeBlock{Code: notionapi.Code{RichText: []notionapi.RichText{text("if a < b {}")}, Language: "go"}},
+				&notionapi.ToggleBlock{Toggle: notionapi.Toggle{
+					RichText: []notionapi.RichText{text("More")},
+					Children: notionapi.Blocks{
+						&notionapi.ParagraphBlock{Paragraph: notionapi.Paragraph{RichText: []notionapi.RichText{text("hidden")}}},
+					},
+				}},
+			},
+			want: `<pre><code class="language-go">if a &lt; b {}</code></pre>` +
+				`<details><summary>More</summary><p>hidden</p></details>`,
+		},
+		{
+			name: "unsafe bookmark and embed",
+			blocks: notionapi.Blocks{
+				&notionapi.BookmarkBlock{Bookmark: notionapi.Bookmark{URL: "javascript:alert(1)"}},
+				&notionapi.EmbedBlock{Embed: notionapi.Embed{URL: "JavaScript:alert(1)", Caption: []notionapi.RichText{text("embed")}}},
+				&notionapi.BookmarkBlock{Bookmark: notionapi.Bookmark{URL: "https://example.com"}},
+			},
+			want: `<p class="notion-bookmark">javascript:alert(1)</p>` +
+				`<p class="notion-embed">embed</p>` +
+				`<p class="notion-bookmark"><a href="https://example.com">https://example.com</a></p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := notionapi.BlocksToHTML(tt.blocks); got != tt.want {
+				t.Errorf("BlocksToHTML() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLRenderer_RewriteURL(t *testing.T) {
+	r := &notionapi.HTMLRenderer{
+		RewriteImageURL: func(b notionapi.Block, u string) string {
+			return "/images/" + string(b.GetID()) + ".png"
+		},
+		RewriteFileURL: func(b notionapi.Block, u string) string {
+			return strings.Replace(u, "https://files.example.com", "/files", 1)
+		},
+	}
+	blocks := notionapi.Blocks{
+		&notionapi.ImageBlock{
+			BasicBlock: notionapi.BasicBlock{ID: "img"},
+			Image: notionapi.Image{
+				Type:    "file",
+				File:    &notionapi.FileObject{URL: "https://files.example.com/cat.png?expires=1"},
+				Caption: []notionapi.RichText{text("cat")},
+			},
+		},
+		&notionapi.FileBlock{File: notionapi.BlockFile{
+			Type: "file",
+			File: &notionapi.FileObject{URL: "https://files.example.com/report.pdf"},
+		}},
+	}
+
+	want := `<figure><img src="/images/img.png" alt="cat"><figcaption class="notion-caption">cat</figcaption></figure>` +
+		`<p class="notion-file"><a href="/files/report.pdf">/files/report.pdf</a></p>`
+	if got := r.RenderBlocks(blocks); got != want {
+		t.Errorf("RenderBlocks() got = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,279 @@
+package notionapi
+
+import (
+	"context"
+)
+
+// IteratorOption to configure auto-paginating iterators.
+type IteratorOption func(*iteratorConfig)
+
+type iteratorConfig struct {
+	pageSize int
+	maxItems int
+}
+
+// WithPageSize sets the number of items requested per page. Maximum: 100.
+func WithPageSize(size int) IteratorOption {
+	return func(c *iteratorConfig) {
+		c.pageSize = size
+	}
+}
+
+// WithMaxItems stops the iteration once the given number of items has been
+// returned, even if more results are available.
+func WithMaxItems(max int) IteratorOption {
+	return func(c *iteratorConfig) {
+		c.maxItems = max
+	}
+}
+
+func newIteratorConfig(opts []IteratorOption) iteratorConfig {
+	var cfg iteratorConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// fetchPageFunc requests the page of results starting at cursor, stores the
+// results in the typed iterator and returns the number of results along with
+// the pagination state of the response.
+type fetchPageFunc func(ctx context.Context, cursor Cursor, pageSize int) (n int, next Cursor, hasMore bool, err error)
+
+// cursorIterator implements the cursor handling shared by all typed
+// iterators. It follows next_cursor until has_more is false, the context is
+// done or the configured maximum number of items is reached.
+type cursorIterator struct {
+	ctx   context.Context
+	cfg   iteratorConfig
+	fetch fetchPageFunc
+
+	cursor  Cursor
+	hasMore bool
+	started bool
+
+	// index of the current item in the current page and the size of the page.
+	idx int
+	n   int
+
+	seen int
+	err  error
+}
+
+func newCursorIterator(ctx context.Context, cursor Cursor, opts []IteratorOption, fetch fetchPageFunc) cursorIterator {
+	return cursorIterator{
+		ctx:    ctx,
+		cfg:    newIteratorConfig(opts),
+		fetch:  fetch,
+		cursor: cursor,
+		idx:    -1,
+	}
+}
+
+func (it *cursorIterator) next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.cfg.maxItems > 0 && it.seen >= it.cfg.maxItems {
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	it.idx++
+	for it.idx >= it.n {
+		if it.started && !it.hasMore {
+			return false
+		}
+
+		n, next, hasMore, err := it.fetch(it.ctx, it.cursor, it.cfg.pageSize)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.started = true
+		it.cursor = next
+		it.hasMore = hasMore && next != ""
+		it.idx = 0
+		it.n = n
+	}
+
+	it.seen++
+	return true
+}
+
+// PageIterator iterates over the pages returned by DatabaseClient.Query,
+// transparently requesting the following pages of results.
+//
+//	it := client.Database.QueryAll(ctx, id, request)
+//	for it.Next() {
+//		page := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		// Handle the error
+//	}
+type PageIterator struct {
+	cursorIterator
+	results []Page
+}
+
+// Next advances the iterator to the next page. It returns false when the
+// iteration stops, either because there are no more results or because of an
+// error, which is then reported by Err.
+func (it *PageIterator) Next() bool {
+	return it.next()
+}
+
+// Value returns the current page.
+func (it *PageIterator) Value() *Page {
+	return &it.results[it.idx]
+}
+
+// Err returns the first error encountered during the iteration, if any.
+func (it *PageIterator) Err() error {
+	return it.err
+}
+
+// All drains the iterator and returns every remaining page.
+func (it *PageIterator) All() ([]Page, error) {
+	result := make([]Page, 0)
+	for it.Next() {
+		result = append(result, *it.Value())
+	}
+	return result, it.Err()
+}
+
+// BlockIterator iterates over the child blocks returned by
+// BlockClient.GetChildren, transparently requesting the following pages of
+// results.
+type BlockIterator struct {
+	cursorIterator
+	results Blocks
+}
+
+// Next advances the iterator to the next block. It returns false when the
+// iteration stops, either because there are no more results or because of an
+// error, which is then reported by Err.
+func (it *BlockIterator) Next() bool {
+	return it.next()
+}
+
+// Value returns the current block.
+func (it *BlockIterator) Value() Block {
+	return it.results[it.idx]
+}
+
+// Err returns the first error encountered during the iteration, if any.
+func (it *BlockIterator) Err() error {
+	return it.err
+}
+
+// All drains the iterator and returns every remaining block.
+func (it *BlockIterator) All() (Blocks, error) {
+	result := make(Blocks, 0)
+	for it.Next() {
+		result = append(result, it.Value())
+	}
+	return result, it.Err()
+}
+
+// UserIterator iterates over the users returned by UserClient.List,
+// transparently requesting the following pages of results.
+type UserIterator struct {
+	cursorIterator
+	results []User
+}
+
+// Next advances the iterator to the next user. It returns false when the
+// iteration stops, either because there are no more results or because of an
+// error, which is then reported by Err.
+func (it *UserIterator) Next() bool {
+	return it.next()
+}
+
+// Value returns the current user.
+func (it *UserIterator) Value() *User {
+	return &it.results[it.idx]
+}
+
+// Err returns the first error encountered during the iteration, if any.
+func (it *UserIterator) Err() error {
+	return it.err
+}
+
+// All drains the iterator and returns every remaining user.
+func (it *UserIterator) All() ([]User, error) {
+	result := make([]User, 0)
+	for it.Next() {
+		result = append(result, *it.Value())
+	}
+	return result, it.Err()
+}
+
+// CommentIterator iterates over the comments returned by CommentClient.Get,
+// transparently requesting the following pages of results.
+type CommentIterator struct {
+	cursorIterator
+	results []Comment
+}
+
+// Next advances the iterator to the next comment. It returns false when the
+// iteration stops, either because there are no more results or because of an
+// error, which is then reported by Err.
+func (it *CommentIterator) Next() bool {
+	return it.next()
+}
+
+// Value returns the current comment.
+func (it *CommentIterator) Value() *Comment {
+	return &it.results[it.idx]
+}
+
+// Err returns the first error encountered during the iteration, if any.
+func (it *CommentIterator) Err() error {
+	return it.err
+}
+
+// All drains the iterator and returns every remaining comment.
+func (it *CommentIterator) All() ([]Comment, error) {
+	result := make([]Comment, 0)
+	for it.Next() {
+		result = append(result, *it.Value())
+	}
+	return result, it.Err()
+}
+
+// ObjectIterator iterates over the pages and databases returned by
+// SearchClient.Do, transparently requesting the following pages of results.
+type ObjectIterator struct {
+	cursorIterator
+	results []Object
+}
+
+// Next advances the iterator to the next object. It returns false when the
+// iteration stops, either because there are no more results or because of an
+// error, which is then reported by Err.
+func (it *ObjectIterator) Next() bool {
+	return it.next()
+}
+
+// Value returns the current object, either a *Page or a *Database.
+func (it *ObjectIterator) Value() Object {
+	return it.results[it.idx]
+}
+
+// Err returns the first error encountered during the iteration, if any.
+func (it *ObjectIterator) Err() error {
+	return it.err
+}
+
+// All drains the iterator and returns every remaining object.
+func (it *ObjectIterator) All() ([]Object, error) {
+	result := make([]Object, 0)
+	for it.Next() {
+		result = append(result, it.Value())
+	}
+	return result, it.Err()
+}
@@ -0,0 +1,169 @@
+package notionapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/jomei/notionapi"
+)
+
+// newPaginatedClient returns *http.Client which serves the given pages of
+// results in order, using the index of the page as the cursor.
+func newPaginatedClient(t *testing.T, pages [][]map[string]interface{}, requests *[]*http.Request) *http.Client {
+	return newTestClient(func(req *http.Request) *http.Response {
+		*requests = append(*requests, req)
+
+		cursor := req.URL.Query().Get("start_cursor")
+		if req.Body != nil {
+			var body struct {
+				StartCursor string `json:"start_cursor"`
+			}
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			cursor = body.StartCursor
+		}
+
+		idx := 0
+		if cursor != "" {
+			if _, err := fmt.Sscanf(cursor, "%d", &idx); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		response := map[string]interface{}{
+			"object":      "list",
+			"results":     pages[idx],
+			"has_more":    idx+1 < len(pages),
+			"next_cursor": nil,
+		}
+		if idx+1 < len(pages) {
+			response["next_cursor"] = fmt.Sprintf("%d", idx+1)
+		}
+		b, err := json.Marshal(response)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			Header:     make(http.Header),
+		}
+	})
+}
+
+func userJSON(id string) map[string]interface{} {
+	return map[string]interface{}{"object": "user", "id": id, "type": "person"}
+}
+
+func pageJSON(id string) map[string]interface{} {
+	return map[string]interface{}{"object": "page", "id": id, "properties": map[string]interface{}{}}
+}
+
+func TestIterator(t *testing.T) {
+	t.Run("UserClient.ListAll follows the cursor", func(t *testing.T) {
+		var requests []*http.Request
+		c := newPaginatedClient(t, [][]map[string]interface{}{
+			{userJSON("1"), userJSON("2")},
+			{userJSON("3")},
+			{userJSON("4")},
+		}, &requests)
+		client := notionapi.NewClient("some_token", notionapi.WithHTTPClient(c))
+
+		got, err := client.User.ListAll(context.Background(), notionapi.WithPageSize(2)).All()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var ids []notionapi.UserID
+		for _, u := range got {
+			ids = append(ids, u.ID)
+		}
+		want := []notionapi.UserID{"1", "2", "3", "4"}
+		if !reflect.DeepEqual(ids, want) {
+			t.Errorf("ListAll() got = %v, want %v", ids, want)
+		}
+		if len(requests) != 3 {
+			t.Errorf("ListAll() requests = %d, want 3", len(requests))
+		}
+		if size := requests[0].URL.Query().Get("page_size"); size != "2" {
+			t.Errorf("ListAll() page_size = %s, want 2", size)
+		}
+	})
+
+	t.Run("DatabaseClient.QueryAll stops at max items", func(t *testing.T) {
+		var requests []*http.Request
+		c := newPaginatedClient(t, [][]map[string]interface{}{
+			{pageJSON("1"), pageJSON("2")},
+			{pageJSON("3"), pageJSON("4")},
+			{pageJSON("5")},
+		}, &requests)
+		client := notionapi.NewClient("some_token", notionapi.WithHTTPClient(c))
+
+		request := &notionapi.DatabaseQueryRequest{PageSize: 2}
+		got, err := client.Database.QueryAll(context.Background(), "some_id", request, notionapi.WithMaxItems(3)).All()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var ids []notionapi.ObjectID
+		for _, p := range got {
+			ids = append(ids, p.ID)
+		}
+		want := []notionapi.ObjectID{"1", "2", "3"}
+		if !reflect.DeepEqual(ids, want) {
+			t.Errorf("QueryAll() got = %v, want %v", ids, want)
+		}
+		if len(requests) != 2 {
+			t.Errorf("QueryAll() requests = %d, want 2", len(requests))
+		}
+		if request.StartCursor != "" {
+			t.Errorf("QueryAll() modified the request cursor: %s", request.StartCursor)
+		}
+	})
+
+	t.Run("stops when the context is canceled", func(t *testing.T) {
+		var requests []*http.Request
+		c := newPaginatedClient(t, [][]map[string]interface{}{
+			{pageJSON("1")},
+			{pageJSON("2")},
+		}, &requests)
+		client := notionapi.NewClient("some_token", notionapi.WithHTTPClient(c))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		it := client.Search.DoAll(ctx, &notionapi.SearchRequest{})
+		if !it.Next() {
+			t.Fatalf("Next() = false, err %v", it.Err())
+		}
+		cancel()
+		if it.Next() {
+			t.Errorf("Next() = true after cancel")
+		}
+		if it.Err() != context.Canceled {
+			t.Errorf("Err() = %v, want %v", it.Err(), context.Canceled)
+		}
+		if len(requests) != 1 {
+			t.Errorf("DoAll() requests = %d, want 1", len(requests))
+		}
+	})
+
+	t.Run("propagates request errors", func(t *testing.T) {
+		c := newMockedClient(t, "testdata/validation_error.json", http.StatusBadRequest)
+		client := notionapi.NewClient("some_token", notionapi.WithHTTPClient(c))
+
+		it := client.Block.GetAllChildren(context.Background(), "some_id")
+		if it.Next() {
+			t.Errorf("Next() = true, want false")
+		}
+		if _, ok := it.Err().(*notionapi.Error); !ok {
+			t.Errorf("Err() = %v, want *notionapi.Error", it.Err())
+		}
+	})
+}
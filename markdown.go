@@ -0,0 +1,383 @@
+package notionapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BlocksToMarkdown renders blocks as GitHub Flavored Markdown.
+//
+// Nested blocks are rendered from the Children field of each block, so the
+// blocks should be fetched with BlockClient.GetTree to render a full page.
+// Toggles are rendered as HTML details elements and callouts as block quotes
+// starting with their emoji. Blocks that have no Markdown equivalent, such as
+// breadcrumbs or tables of contents, are skipped.
+func BlocksToMarkdown(blocks Blocks) string {
+	md := markdownBlocks(blocks)
+	if md == "" {
+		return ""
+	}
+	return md + "\n"
+}
+
+// RichTextToMarkdown renders rich text as inline Markdown, including
+// annotations, links, mentions and inline equations.
+func RichTextToMarkdown(richText []RichText) string {
+	var sb strings.Builder
+	for _, rt := range richText {
+		sb.WriteString(markdownRichText(rt))
+	}
+	return sb.String()
+}
+
+func markdownRichText(rt RichText) string {
+	var text string
+	switch {
+	case rt.Equation != nil:
+		return "$" + rt.Equation.Expression + "$"
+	case rt.Text != nil:
+		text = rt.Text.Content
+	default:
+		text = rt.PlainText
+	}
+	if text == "" {
+		return ""
+	}
+
+	href := rt.Href
+	if rt.Text != nil && rt.Text.Link != nil && rt.Text.Link.Url != "" {
+		href = rt.Text.Link.Url
+	}
+
+	a := rt.Annotations
+	if a != nil && a.Code {
+		text = markdownCodeSpan(text)
+	} else {
+		text = escapeMarkdown(text)
+	}
+	if a != nil {
+		if a.Strikethrough {
+			text = wrapMarkdown(text, "~~")
+		}
+		if a.Italic {
+			text = wrapMarkdown(text, "_")
+		}
+		if a.Bold {
+			text = wrapMarkdown(text, "**")
+		}
+	}
+	text = strings.ReplaceAll(text, "\n", "\\\n")
+
+	if href != "" {
+		return fmt.Sprintf("[%s](%s)", text, markdownURL(href))
+	}
+	return text
+}
+
+// wrapMarkdown surrounds text with the given delimiter, keeping leading and
+// trailing spaces outside of it as emphasis cannot start or end with a space.
+func wrapMarkdown(text, delimiter string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	start := strings.Index(text, trimmed)
+	return text[:start] + delimiter + trimmed + delimiter + text[start+len(trimmed):]
+}
+
+func markdownCodeSpan(text string) string {
+	fence := "`"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		return fence + " " + text + " " + fence
+	}
+	return fence + text + fence
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `\<`,
+	`>`, `\>`,
+	`~`, `\~`,
+	`#`, `\#`,
+)
+
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+func markdownURL(u string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(u)
+}
+
+// markdownListType returns the type of list the block belongs to, or an
+// empty string if it is not a list item.
+func markdownListType(b Block) BlockType {
+	switch b.(type) {
+	case *BulletedListItemBlock:
+		return BlockTypeBulletedListItem
+	case *NumberedListItemBlock:
+		return BlockTypeNumberedListItem
+	case *ToDoBlock:
+		return BlockTypeToDo
+	}
+	return ""
+}
+
+func markdownBlocks(blocks Blocks) string {
+	var sb strings.Builder
+	var prevList BlockType
+	first := true
+	number := 0
+	for _, b := range blocks {
+		list := markdownListType(b)
+		if list == BlockTypeNumberedListItem {
+			number++
+		} else {
+			number = 0
+		}
+
+		md := markdownBlock(b, number)
+		if md == "" {
+			continue
+		}
+		if !first {
+			if list != "" && list == prevList {
+				sb.WriteString("\n")
+			} else {
+				sb.WriteString("\n\n")
+			}
+		}
+		sb.WriteString(md)
+		prevList = list
+		first = false
+	}
+	return sb.String()
+}
+
+func markdownBlock(b Block, number int) string {
+	switch b := b.(type) {
+	case *ParagraphBlock:
+		return markdownWithChildren(RichTextToMarkdown(b.Paragraph.RichText), b.Paragraph.Children)
+	case *Heading1Block:
+		return markdownHeading("#", b.Heading1)
+	case *Heading2Block:
+		return markdownHeading("##", b.Heading2)
+	case *Heading3Block:
+		return markdownHeading("###", b.Heading3)
+	case *BulletedListItemBlock:
+		return markdownListItem("- ", RichTextToMarkdown(b.BulletedListItem.RichText), b.BulletedListItem.Children)
+	case *NumberedListItemBlock:
+		return markdownListItem(fmt.Sprintf("%d. ", number), RichTextToMarkdown(b.NumberedListItem.RichText), b.NumberedListItem.Children)
+	case *ToDoBlock:
+		text := "[ ] " + RichTextToMarkdown(b.ToDo.RichText)
+		if b.ToDo.Checked {
+			text = "[x] " + RichTextToMarkdown(b.ToDo.RichText)
+		}
+		return markdownListItem("- ", text, b.ToDo.Children)
+	case *ToggleBlock:
+		return markdownToggle(RichTextToMarkdown(b.Toggle.RichText), b.Toggle.Children)
+	case *QuoteBlock:
+		return markdownQuote(markdownWithChildren(RichTextToMarkdown(b.Quote.RichText), b.Quote.Children))
+	case *CalloutBlock:
+		text := RichTextToMarkdown(b.Callout.RichText)
+		if b.Callout.Icon != nil && b.Callout.Icon.Emoji != nil {
+			text = string(*b.Callout.Icon.Emoji) + " " + text
+		}
+		return markdownQuote(markdownWithChildren(text, b.Callout.Children))
+	case *CodeBlock:
+		return markdownCode(b.Code)
+	case *EquationBlock:
+		return "$$\n" + b.Equation.Expression + "\n$$"
+	case *DividerBlock:
+		return "---"
+	case *TableBlock:
+		return markdownTable(b.Table)
+	case *ImageBlock:
+		return fmt.Sprintf("![%s](%s)", RichTextToMarkdown(b.Image.Caption), markdownURL(b.Image.GetURL()))
+	case *BookmarkBlock:
+		return markdownLink(b.Bookmark.Caption, b.Bookmark.URL)
+	case *EmbedBlock:
+		return markdownLink(b.Embed.Caption, b.Embed.URL)
+	case *LinkPreviewBlock:
+		return markdownLink(nil, b.LinkPreview.URL)
+	case *VideoBlock:
+		return markdownLink(b.Video.Caption, fileURL(b.Video.File, b.Video.External))
+	case *AudioBlock:
+		return markdownLink(b.Audio.Caption, b.Audio.GetURL())
+	case *FileBlock:
+		return markdownLink(b.File.Caption, b.GetURL())
+	case *PdfBlock:
+		return markdownLink(b.Pdf.Caption, b.GetURL())
+	case *ChildPageBlock:
+		return fmt.Sprintf("[%s](%s)", escapeMarkdown(b.ChildPage.Title), notionURL(string(b.ID)))
+	case *ChildDatabaseBlock:
+		return fmt.Sprintf("[%s](%s)", escapeMarkdown(b.ChildDatabase.Title), notionURL(string(b.ID)))
+	case *LinkToPageBlock:
+		id := string(b.LinkToPage.PageID)
+		if b.LinkToPage.DatabaseID != "" {
+			id = string(b.LinkToPage.DatabaseID)
+		}
+		return fmt.Sprintf("[%s](%s)", notionURL(id), notionURL(id))
+	case *ColumnListBlock:
+		return markdownBlocks(b.ColumnList.Children)
+	case *ColumnBlock:
+		return markdownBlocks(b.Column.Children)
+	case *SyncedBlock:
+		return markdownBlocks(b.SyncedBlock.Children)
+	case *TemplateBlock:
+		return markdownWithChildren(RichTextToMarkdown(b.Template.RichText), b.Template.Children)
+	}
+	return ""
+}
+
+func markdownWithChildren(text string, children Blocks) string {
+	md := markdownBlocks(children)
+	switch {
+	case md == "":
+		return text
+	case text == "":
+		return md
+	}
+	return text + "\n\n" + md
+}
+
+func markdownHeading(level string, h Heading) string {
+	text := strings.ReplaceAll(RichTextToMarkdown(h.RichText), "\\\n", " ")
+	heading := level + " " + text
+	if md := markdownBlocks(h.Children); md != "" {
+		return heading + "\n\n" + md
+	}
+	return heading
+}
+
+func markdownListItem(marker, text string, children Blocks) string {
+	indent := strings.Repeat(" ", len(marker))
+	item := marker + indentMarkdown(text, indent, false)
+	if md := markdownBlocks(children); md != "" {
+		item += "\n" + indentMarkdown(md, indent, true)
+	}
+	return item
+}
+
+func markdownToggle(summary string, children Blocks) string {
+	md := markdownBlocks(children)
+	if md == "" {
+		return "<details>\n<summary>" + summary + "</summary>\n</details>"
+	}
+	return "<details>\n<summary>" + summary + "</summary>\n\n" + md + "\n\n</details>"
+}
+
+func markdownQuote(md string) string {
+	lines := strings.Split(md, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = ">"
+		} else {
+			lines[i] = "> " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indentMarkdown prefixes every non-empty line of md with indent, starting
+// from the first line or the second one.
+func indentMarkdown(md, indent string, first bool) string {
+	lines := strings.Split(md, "\n")
+	for i, line := range lines {
+		if line == "" || (i == 0 && !first) {
+			continue
+		}
+		lines[i] = indent + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+func markdownCode(c Code) string {
+	content := concatenateRichText(c.RichText)
+	fence := "```"
+	for strings.Contains(content, fence) {
+		fence += "`"
+	}
+	language := c.Language
+	if language == "plain text" {
+		language = ""
+	}
+	return fence + language + "\n" + content + "\n" + fence
+}
+
+func markdownTable(t Table) string {
+	var rows [][]string
+	for _, child := range t.Children {
+		row, ok := child.(*TableRowBlock)
+		if !ok {
+			continue
+		}
+		cells := make([]string, t.TableWidth)
+		for i, cell := range row.TableRow.Cells {
+			if i >= len(cells) {
+				break
+			}
+			md := strings.ReplaceAll(RichTextToMarkdown(cell), "\\\n", "<br>")
+			cells[i] = strings.ReplaceAll(md, "|", `\|`)
+		}
+		rows = append(rows, cells)
+	}
+	if t.TableWidth == 0 {
+		return ""
+	}
+
+	// GFM tables always have a header row, so an empty one is added when the
+	// first row of the table is not a header.
+	header := make([]string, t.TableWidth)
+	if t.HasColumnHeader && len(rows) > 0 {
+		header, rows = rows[0], rows[1:]
+	}
+	separator := make([]string, t.TableWidth)
+	for i := range separator {
+		separator[i] = "---"
+	}
+
+	lines := []string{markdownTableRow(header), markdownTableRow(separator)}
+	for _, row := range rows {
+		lines = append(lines, markdownTableRow(row))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func markdownTableRow(cells []string) string {
+	return "| " + strings.Join(cells, " | ") + " |"
+}
+
+func markdownLink(caption []RichText, u string) string {
+	if u == "" {
+		return ""
+	}
+	text := RichTextToMarkdown(caption)
+	if text == "" {
+		text = escapeMarkdown(u)
+	}
+	return fmt.Sprintf("[%s](%s)", text, markdownURL(u))
+}
+
+func fileURL(file, external *FileObject) string {
+	if file != nil {
+		return file.URL
+	}
+	if external != nil {
+		return external.URL
+	}
+	return ""
+}
+
+// notionURL returns the URL of a page or database from its ID.
+func notionURL(id string) string {
+	return "https://www.notion.so/" + strings.ReplaceAll(id, "-", "")
+}
@@ -0,0 +1,666 @@
+package notionapi
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxRichTextLength is the maximum length of the content of a single rich
+	// text object accepted by the API.
+	maxRichTextLength = 2000
+	// maxAppendNesting is the maximum number of levels of children accepted in
+	// a single append request.
+	maxAppendNesting = 2
+)
+
+var (
+	mdFenceRe     = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})\\s*([^`\\s]*)")
+	mdHeadingRe   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:\s+(.*?))?(?:\s+#+)?\s*$`)
+	mdBreakRe     = regexp.MustCompile(`^ {0,3}(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	mdListItemRe  = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])(\s+|$)(.*)$`)
+	mdTaskRe      = regexp.MustCompile(`^\[([ xX])\]\s+`)
+	mdImageRe     = regexp.MustCompile(`^\s*!\[([^\]]*)\]\(\s*<?([^\s>)]+)>?(?:\s+"[^"]*")?\s*\)\s*$`)
+	mdTableSepRe  = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	mdQuoteRe     = regexp.MustCompile(`^ {0,3}>\s?`)
+	mdEquationRe  = regexp.MustCompile(`^\s*\$\$\s*$`)
+	mdLinkCloseRe = regexp.MustCompile(`^\(\s*<?([^\s>)]*)>?(?:\s+"[^"]*")?\s*\)`)
+)
+
+// codeLanguages maps common Markdown info strings to the languages supported
+// by code blocks.
+var codeLanguages = map[string]string{
+	"":       "plain text",
+	"text":   "plain text",
+	"txt":    "plain text",
+	"js":     "javascript",
+	"ts":     "typescript",
+	"py":     "python",
+	"rb":     "ruby",
+	"sh":     "shell",
+	"bash":   "bash",
+	"zsh":    "shell",
+	"yml":    "yaml",
+	"golang": "go",
+	"c++":    "c++",
+	"cpp":    "c++",
+	"cs":     "c#",
+	"csharp": "c#",
+	"md":     "markdown",
+	"kt":     "kotlin",
+	"rs":     "rust",
+}
+
+// MarkdownToBlocks parses a GitHub Flavored Markdown document into blocks that
+// can be sent in an AppendBlockChildrenRequest or a PageCreateRequest.
+//
+// Lists, quotes and tables are nested using the Children field of the blocks.
+// As the API accepts at most two levels of nesting in a single request, deeper
+// blocks are moved up to the last allowed level, and tables one level above,
+// with their rows. Rich text longer than the
+// 2000 characters accepted by the API is split into several rich text objects.
+func MarkdownToBlocks(md string) Blocks {
+	md = strings.ReplaceAll(md, "\r\n", "\n")
+	md = strings.ReplaceAll(md, "\t", "    ")
+	blocks := parseMarkdownBlocks(strings.Split(md, "\n"))
+	blocks, _ = limitBlockNesting(blocks, 0)
+	return blocks
+}
+
+// MarkdownToRichText parses inline Markdown into rich text. Bold, italic,
+// strikethrough, code spans, links and inline equations are supported.
+func MarkdownToRichText(text string) []RichText {
+	p := &inlineParser{}
+	p.parse(text, Annotations{}, "")
+	return splitRichText(p.result)
+}
+
+func newBasicBlock(t BlockType) BasicBlock {
+	return BasicBlock{Object: ObjectTypeBlock, Type: t}
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func parseMarkdownBlocks(lines []string) Blocks {
+	blocks := Blocks{}
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case isBlank(line):
+			i++
+		case mdFenceRe.MatchString(line):
+			var b Block
+			b, i = parseMarkdownCode(lines, i)
+			blocks = append(blocks, b)
+		case mdEquationRe.MatchString(line):
+			var b Block
+			b, i = parseMarkdownEquation(lines, i)
+			blocks = append(blocks, b)
+		case mdHeadingRe.MatchString(line):
+			blocks = append(blocks, parseMarkdownHeading(line))
+			i++
+		case mdBreakRe.MatchString(line):
+			blocks = append(blocks, &DividerBlock{BasicBlock: newBasicBlock(BlockTypeDivider)})
+			i++
+		case mdQuoteRe.MatchString(line):
+			var b Block
+			b, i = parseMarkdownQuote(lines, i)
+			blocks = append(blocks, b)
+		case mdListItemRe.MatchString(line):
+			var b Block
+			b, i = parseMarkdownListItem(lines, i)
+			blocks = append(blocks, b)
+		case isMarkdownTable(lines, i):
+			var b Block
+			b, i = parseMarkdownTable(lines, i)
+			blocks = append(blocks, b)
+		case mdImageRe.MatchString(line):
+			blocks = append(blocks, parseMarkdownImage(line))
+			i++
+		default:
+			var b Block
+			b, i = parseMarkdownParagraph(lines, i)
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks
+}
+
+// startsMarkdownBlock reports whether the line interrupts a paragraph.
+func startsMarkdownBlock(lines []string, i int) bool {
+	line := lines[i]
+	return isBlank(line) ||
+		mdFenceRe.MatchString(line) ||
+		mdEquationRe.MatchString(line) ||
+		mdHeadingRe.MatchString(line) ||
+		mdBreakRe.MatchString(line) ||
+		mdQuoteRe.MatchString(line) ||
+		mdListItemRe.MatchString(line) ||
+		isMarkdownTable(lines, i)
+}
+
+func parseMarkdownParagraph(lines []string, i int) (Block, int) {
+	var sb strings.Builder
+	for start := i; i < len(lines) && (i == start || !startsMarkdownBlock(lines, i)); i++ {
+		if i > start {
+			sb.WriteString(markdownLineBreak(lines[i-1]))
+		}
+		sb.WriteString(strings.TrimRight(strings.TrimLeft(lines[i], " "), " \\"))
+	}
+	return &ParagraphBlock{
+		BasicBlock: newBasicBlock(BlockTypeParagraph),
+		Paragraph:  Paragraph{RichText: MarkdownToRichText(sb.String())},
+	}, i
+}
+
+// markdownLineBreak returns the separator between a line of a paragraph and
+// the next one: a new line for hard line breaks, a space otherwise.
+func markdownLineBreak(line string) string {
+	if strings.HasSuffix(line, "  ") || strings.HasSuffix(line, "\\") {
+		return "\n"
+	}
+	return " "
+}
+
+func parseMarkdownHeading(line string) Block {
+	m := mdHeadingRe.FindStringSubmatch(line)
+	heading := Heading{RichText: MarkdownToRichText(m[2])}
+	switch len(m[1]) {
+	case 1:
+		return &Heading1Block{BasicBlock: newBasicBlock(BlockTypeHeading1), Heading1: heading}
+	case 2:
+		return &Heading2Block{BasicBlock: newBasicBlock(BlockTypeHeading2), Heading2: heading}
+	}
+	return &Heading3Block{BasicBlock: newBasicBlock(BlockTypeHeading3), Heading3: heading}
+}
+
+func parseMarkdownCode(lines []string, i int) (Block, int) {
+	m := mdFenceRe.FindStringSubmatch(lines[i])
+	fence := m[1]
+	language, ok := codeLanguages[strings.ToLower(m[2])]
+	if !ok {
+		language = strings.ToLower(m[2])
+	}
+
+	var content []string
+	for i++; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			i++
+			break
+		}
+		content = append(content, lines[i])
+	}
+
+	return &CodeBlock{
+		BasicBlock: newBasicBlock(BlockTypeCode),
+		Code: Code{
+			RichText: splitRichText([]RichText{plainRichText(strings.Join(content, "\n"))}),
+			Language: language,
+		},
+	}, i
+}
+
+func parseMarkdownEquation(lines []string, i int) (Block, int) {
+	var content []string
+	for i++; i < len(lines); i++ {
+		if mdEquationRe.MatchString(lines[i]) {
+			i++
+			break
+		}
+		content = append(content, lines[i])
+	}
+	return &EquationBlock{
+		BasicBlock: newBasicBlock(BlockTypeEquation),
+		Equation:   Equation{Expression: strings.Join(content, "\n")},
+	}, i
+}
+
+func parseMarkdownQuote(lines []string, i int) (Block, int) {
+	var content []string
+	for ; i < len(lines) && mdQuoteRe.MatchString(lines[i]); i++ {
+		content = append(content, mdQuoteRe.ReplaceAllString(lines[i], ""))
+	}
+
+	text, children := splitLeadingText(parseMarkdownBlocks(content))
+	return &QuoteBlock{
+		BasicBlock: newBasicBlock(BlockTypeQuote),
+		Quote:      Quote{RichText: text, Children: children},
+	}, i
+}
+
+func parseMarkdownListItem(lines []string, i int) (Block, int) {
+	m := mdListItemRe.FindStringSubmatch(lines[i])
+	indent := len(m[1]) + len(m[2]) + len(m[3])
+	if m[3] == "" {
+		indent++
+	}
+
+	content := []string{m[4]}
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		if isBlank(line) {
+			// A blank line only belongs to the item if it is followed by an
+			// indented line.
+			j := i
+			for j < len(lines) && isBlank(lines[j]) {
+				j++
+			}
+			if j == len(lines) || leadingSpaces(lines[j]) < indent {
+				break
+			}
+			content = append(content, "")
+			continue
+		}
+		if leadingSpaces(line) >= indent {
+			content = append(content, line[indent:])
+			continue
+		}
+		// Lazy continuation of the item paragraph.
+		if !startsMarkdownBlock(lines, i) && !isBlank(content[len(content)-1]) {
+			content = append(content, line)
+			continue
+		}
+		break
+	}
+
+	checked, isTask := false, false
+	if t := mdTaskRe.FindStringSubmatch(content[0]); t != nil {
+		isTask = true
+		checked = t[1] != " "
+		content[0] = content[0][len(t[0]):]
+	}
+
+	text, children := splitLeadingText(parseMarkdownBlocks(content))
+	item := ListItem{RichText: text, Children: children}
+	switch {
+	case isTask:
+		return &ToDoBlock{
+			BasicBlock: newBasicBlock(BlockTypeToDo),
+			ToDo:       ToDo{RichText: text, Children: children, Checked: checked},
+		}, i
+	case strings.ContainsAny(m[2], ".)"):
+		return &NumberedListItemBlock{BasicBlock: newBasicBlock(BlockTypeNumberedListItem), NumberedListItem: item}, i
+	}
+	return &BulletedListItemBlock{BasicBlock: newBasicBlock(BlockTypeBulletedListItem), BulletedListItem: item}, i
+}
+
+func leadingSpaces(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// splitLeadingText returns the rich text of the first block if it is a
+// paragraph, along with the remaining blocks.
+func splitLeadingText(blocks Blocks) ([]RichText, Blocks) {
+	if len(blocks) > 0 {
+		if p, ok := blocks[0].(*ParagraphBlock); ok {
+			blocks = blocks[1:]
+			if len(blocks) == 0 {
+				blocks = nil
+			}
+			return p.Paragraph.RichText, blocks
+		}
+	}
+	if len(blocks) == 0 {
+		blocks = nil
+	}
+	return []RichText{}, blocks
+}
+
+func isMarkdownTable(lines []string, i int) bool {
+	return strings.Contains(lines[i], "|") && i+1 < len(lines) &&
+		strings.Contains(lines[i+1], "-") && mdTableSepRe.MatchString(lines[i+1])
+}
+
+func parseMarkdownTable(lines []string, i int) (Block, int) {
+	header := splitTableRow(lines[i])
+	rows := [][]string{header}
+	for i += 2; i < len(lines) && !isBlank(lines[i]) && strings.Contains(lines[i], "|"); i++ {
+		rows = append(rows, splitTableRow(lines[i]))
+	}
+
+	width := len(header)
+	children := make(Blocks, len(rows))
+	for r, row := range rows {
+		cells := make([][]RichText, width)
+		for c := range cells {
+			cells[c] = []RichText{}
+			if c < len(row) {
+				cells[c] = MarkdownToRichText(row[c])
+			}
+		}
+		children[r] = &TableRowBlock{
+			BasicBlock: newBasicBlock(BlockTypeTableRowBlock),
+			TableRow:   TableRow{Cells: cells},
+		}
+	}
+
+	return &TableBlock{
+		BasicBlock: newBasicBlock(BlockTypeTableBlock),
+		Table: Table{
+			TableWidth:      width,
+			HasColumnHeader: true,
+			Children:        children,
+		},
+	}, i
+}
+
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for j := 0; j < len(line); j++ {
+		switch {
+		case line[j] == '\\' && j+1 < len(line) && line[j+1] == '|':
+			cell.WriteByte('|')
+			j++
+		case line[j] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[j])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+func parseMarkdownImage(line string) Block {
+	m := mdImageRe.FindStringSubmatch(line)
+	image := Image{
+		Type:     FileTypeExternal,
+		External: &FileObject{URL: m[2]},
+	}
+	if m[1] != "" {
+		image.Caption = MarkdownToRichText(m[1])
+	}
+	return &ImageBlock{BasicBlock: newBasicBlock(BlockTypeImage), Image: image}
+}
+
+// limitBlockNesting moves the children of blocks nested deeper than the API
+// accepts in a single request up to the deepest accepted level, right after
+// their parent. As table rows cannot be moved out of their table, a table at
+// the deepest level is moved up one level instead, along with the blocks
+// after it: those are returned separately, to be added after the parent of
+// the blocks.
+func limitBlockNesting(blocks Blocks, depth int) (Blocks, Blocks) {
+	result := make(Blocks, 0, len(blocks))
+	for i, b := range blocks {
+		if _, ok := b.(*TableBlock); ok && depth >= maxAppendNesting {
+			moved, _ := limitBlockNesting(blocks[i:], depth-1)
+			return result, moved
+		}
+		result = append(result, b)
+		children := blockChildren(b)
+		if len(children) == 0 {
+			continue
+		}
+		if _, ok := b.(*TableBlock); ok {
+			continue
+		}
+		if depth >= maxAppendNesting {
+			setBlockChildren(b, nil)
+			flattened, moved := limitBlockNesting(children, depth)
+			result = append(result, flattened...)
+			if len(moved) > 0 {
+				rest, _ := limitBlockNesting(blocks[i+1:], depth-1)
+				return result, append(moved, rest...)
+			}
+			continue
+		}
+		kept, moved := limitBlockNesting(children, depth+1)
+		if len(kept) == 0 {
+			kept = nil
+		}
+		setBlockChildren(b, kept)
+		result = append(result, moved...)
+	}
+	return result, nil
+}
+
+func plainRichText(content string) RichText {
+	return RichText{Type: ObjectTypeText, Text: &Text{Content: content}}
+}
+
+// splitRichText splits the text of rich text objects longer than the maximum
+// length accepted by the API into several objects with the same annotations.
+func splitRichText(richText []RichText) []RichText {
+	result := make([]RichText, 0, len(richText))
+	for _, rt := range richText {
+		if rt.Text == nil || utf8.RuneCountInString(rt.Text.Content) <= maxRichTextLength {
+			result = append(result, rt)
+			continue
+		}
+		content := []rune(rt.Text.Content)
+		for start := 0; start < len(content); start += maxRichTextLength {
+			end := start + maxRichTextLength
+			if end > len(content) {
+				end = len(content)
+			}
+			part := rt
+			part.Text = &Text{Content: string(content[start:end]), Link: rt.Text.Link}
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
+// inlineParser converts inline Markdown into rich text, merging consecutive
+// runs of text that share the same annotations and link.
+type inlineParser struct {
+	result []RichText
+}
+
+func (p *inlineParser) text(content string, a Annotations, link string) {
+	if content == "" {
+		return
+	}
+	if n := len(p.result); n > 0 {
+		last := &p.result[n-1]
+		if last.Text != nil && sameInline(*last, a, link) {
+			last.Text.Content += content
+			return
+		}
+	}
+
+	rt := plainRichText(content)
+	if a != (Annotations{}) {
+		annotations := a
+		rt.Annotations = &annotations
+	}
+	if link != "" {
+		rt.Text.Link = &Link{Url: link}
+	}
+	p.result = append(p.result, rt)
+}
+
+func sameInline(rt RichText, a Annotations, link string) bool {
+	var annotations Annotations
+	if rt.Annotations != nil {
+		annotations = *rt.Annotations
+	}
+	var l string
+	if rt.Text.Link != nil {
+		l = rt.Text.Link.Url
+	}
+	return annotations == a && l == link
+}
+
+func (p *inlineParser) parse(s string, a Annotations, link string) {
+	var plain strings.Builder
+	flush := func() {
+		p.text(plain.String(), a, link)
+		plain.Reset()
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]):
+			plain.WriteByte(s[i+1])
+			i += 2
+			continue
+		case c == '`':
+			ticks := countRun(s, i, '`')
+			fence := s[i : i+ticks]
+			if end := strings.Index(s[i+ticks:], fence); end >= 0 {
+				flush()
+				code := s[i+ticks : i+ticks+end]
+				if strings.HasPrefix(code, " ") && strings.HasSuffix(code, " ") && strings.TrimSpace(code) != "" {
+					code = code[1 : len(code)-1]
+				}
+				ca := a
+				ca.Code = true
+				p.text(code, ca, link)
+				i += 2*ticks + end
+				continue
+			}
+			plain.WriteString(fence)
+			i += ticks
+			continue
+		case c == '$' && i+1 < len(s) && s[i+1] != ' ' && s[i+1] != '$':
+			if end := strings.IndexByte(s[i+1:], '$'); end > 0 && s[i+end] != ' ' {
+				flush()
+				p.result = append(p.result, RichText{
+					Type:     ObjectTypeEquation,
+					Equation: &Equation{Expression: s[i+1 : i+1+end]},
+				})
+				i += end + 2
+				continue
+			}
+		case c == '[' && link == "":
+			if label, target, n := parseInlineLink(s[i:]); n > 0 {
+				flush()
+				p.parse(label, a, target)
+				i += n
+				continue
+			}
+		case c == '*' || c == '_' || c == '~':
+			if n := p.emphasis(s, i, a, link, flush); n > 0 {
+				i += n
+				continue
+			}
+		}
+		plain.WriteByte(c)
+		i++
+	}
+	flush()
+}
+
+// emphasis parses the emphasis starting at s[i], if any, and returns the
+// number of bytes consumed.
+func (p *inlineParser) emphasis(s string, i int, a Annotations, link string, flush func()) int {
+	c := s[i]
+	run := countRun(s, i, c)
+	if c == '~' && run != 2 {
+		return 0
+	}
+	if run > 3 {
+		return 0
+	}
+	delimiter := s[i : i+run]
+
+	// The opening delimiter must be followed by a non-space character and,
+	// for underscores, must not be inside a word.
+	if i+run >= len(s) || s[i+run] == ' ' {
+		return 0
+	}
+	if c == '_' && i > 0 && isWordByte(s[i-1]) {
+		return 0
+	}
+
+	end := findClosingDelimiter(s, i+run, delimiter)
+	if end < 0 {
+		return 0
+	}
+
+	inner := a
+	switch {
+	case c == '~':
+		inner.Strikethrough = true
+	case run == 1:
+		inner.Italic = true
+	case run == 2:
+		inner.Bold = true
+	default:
+		inner.Bold = true
+		inner.Italic = true
+	}
+
+	flush()
+	p.parse(s[i+run:end], inner, link)
+	return end + run - i
+}
+
+func findClosingDelimiter(s string, from int, delimiter string) int {
+	c := delimiter[0]
+	for j := from; j < len(s); j++ {
+		switch {
+		case s[j] == '\\':
+			j++
+		case s[j] == '`':
+			ticks := countRun(s, j, '`')
+			if end := strings.Index(s[j+ticks:], s[j:j+ticks]); end >= 0 {
+				j += 2*ticks + end - 1
+			} else {
+				j += ticks - 1
+			}
+		case s[j] == c:
+			run := countRun(s, j, c)
+			if run == len(delimiter) && s[j-1] != ' ' && j > from &&
+				(c != '_' || j+run >= len(s) || !isWordByte(s[j+run])) {
+				return j
+			}
+			j += run - 1
+		}
+	}
+	return -1
+}
+
+// parseInlineLink parses a [label](url) link at the start of s and returns
+// the number of bytes consumed, or 0 if s does not start with a link.
+func parseInlineLink(s string) (string, string, int) {
+	depth := 0
+	for j := 0; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				m := mdLinkCloseRe.FindStringSubmatch(s[j+1:])
+				if m == nil || m[1] == "" {
+					return "", "", 0
+				}
+				return s[1:j], m[1], j + 1 + len(m[0])
+			}
+		}
+	}
+	return "", "", 0
+}
+
+func countRun(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= utf8.RuneSelf || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+func isASCIIPunct(c byte) bool {
+	return c < utf8.RuneSelf && unicode.IsPunct(rune(c)) || strings.IndexByte("$+<=>^`|~", c) >= 0
+}
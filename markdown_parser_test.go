@@ -0,0 +1,233 @@
+package notionapi_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jomei/notionapi"
+)
+
+func TestMarkdownToRichText(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		want []notionapi.RichText
+	}{
+		{
+			name: "plain text",
+			md:   "Hello world",
+			want: []notionapi.RichText{requestText("Hello world")},
+		},
+		{
+			name: "annotations",
+			md:   "**bold** _italic_ `a*b` ~~gone~~ ***both***",
+			want: []notionapi.RichText{
+				annotatedText("bold", notionapi.Annotations{Bold: true}),
+				requestText(" "),
+				annotatedText("italic", notionapi.Annotations{Italic: true}),
+				requestText(" "),
+				annotatedText("a*b", notionapi.Annotations{Code: true}),
+				requestText(" "),
+				annotatedText("gone", notionapi.Annotations{Strikethrough: true}),
+				requestText(" "),
+				annotatedText("both", notionapi.Annotations{Bold: true, Italic: true}),
+			},
+		},
+		{
+			name: "nested annotations and links",
+			md:   "see **[the _docs_](https://example.com)** \\*now\\*",
+			want: []notionapi.RichText{
+				requestText("see "),
+				linkText("the ", "https://example.com", notionapi.Annotations{Bold: true}),
+				linkText("docs", "https://example.com", notionapi.Annotations{Bold: true, Italic: true}),
+				requestText(" *now*"),
+			},
+		},
+		{
+			name: "snake_case is not emphasis",
+			md:   "some_variable_name",
+			want: []notionapi.RichText{requestText("some_variable_name")},
+		},
+		{
+			name: "inline equation",
+			md:   "area $\\pi r^2$",
+			want: []notionapi.RichText{
+				requestText("area "),
+				{Type: notionapi.ObjectTypeEquation, Equation: &notionapi.Equation{Expression: "\\pi r^2"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := notionapi.MarkdownToRichText(tt.md)
+			if !reflect.DeepEqual(got, tt.want) {
+				gotJSON, _ := json.Marshal(got)
+				wantJSON, _ := json.Marshal(tt.want)
+				t.Errorf("MarkdownToRichText() got = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+
+	t.Run("splits long text", func(t *testing.T) {
+		got := notionapi.MarkdownToRichText(strings.Repeat("a", 4500))
+		if len(got) != 3 {
+			t.Fatalf("MarkdownToRichText() got %d rich texts, want 3", len(got))
+		}
+		if len(got[0].Text.Content) != 2000 || len(got[2].Text.Content) != 500 {
+			t.Errorf("MarkdownToRichText() split into %d, %d and %d characters", len(got[0].Text.Content), len(got[1].Text.Content), len(got[2].Text.Content))
+		}
+	})
+}
+
+// requestText returns rich text as sent in requests, without plain text.
+func requestText(content string) notionapi.RichText {
+	return notionapi.RichText{Type: notionapi.ObjectTypeText, Text: &notionapi.Text{Content: content}}
+}
+
+func annotatedText(content string, annotations notionapi.Annotations) notionapi.RichText {
+	rt := requestText(content)
+	rt.Annotations = &annotations
+	return rt
+}
+
+func linkText(content, url string, annotations notionapi.Annotations) notionapi.RichText {
+	rt := annotatedText(content, annotations)
+	rt.Text.Link = &notionapi.Link{Url: url}
+	return rt
+}
+
+func TestMarkdownToBlocks(t *testing.T) {
+	md := "# Title\n" +
+		"\n" +
+		"Some *text*\n" +
+		"on two lines.\n" +
+		"\n" +
+		"- item\n" +
+		"  - nested\n" +
+		"    - deeper\n" +
+		"      - deepest\n" +
+		"- [x] done\n" +
+		"\n" +
+		"1. first\n" +
+		"2. second\n" +
+		"\n" +
+		"> quoted\n" +
+		"\n" +
+		"```js\n" +
+		"console.log(1)\n" +
+		"```\n" +
+		"\n" +
+		"| a | b |\n" +
+		"|---|---|\n" +
+		"| 1 | 2 |\n" +
+		"\n" +
+		"---\n" +
+		"![cat](https://example.com/cat.png)\n"
+
+	got := notionapi.MarkdownToBlocks(md)
+
+	var types []notionapi.BlockType
+	for _, b := range got {
+		types = append(types, b.GetType())
+	}
+	wantTypes := []notionapi.BlockType{
+		notionapi.BlockTypeHeading1,
+		notionapi.BlockTypeParagraph,
+		notionapi.BlockTypeBulletedListItem,
+		notionapi.BlockTypeToDo,
+		notionapi.BlockTypeNumberedListItem,
+		notionapi.BlockTypeNumberedListItem,
+		notionapi.BlockTypeQuote,
+		notionapi.BlockTypeCode,
+		notionapi.BlockTypeTableBlock,
+		notionapi.BlockTypeDivider,
+		notionapi.BlockTypeImage,
+	}
+	if !reflect.DeepEqual(types, wantTypes) {
+		t.Fatalf("MarkdownToBlocks() types = %v, want %v", types, wantTypes)
+	}
+
+	paragraph := got[1].(*notionapi.ParagraphBlock)
+	if n := len(paragraph.Paragraph.RichText); n != 3 || paragraph.Paragraph.RichText[2].Text.Content != " on two lines." {
+		t.Errorf("paragraph rich text = %+v", paragraph.Paragraph.RichText)
+	}
+
+	// Only two levels of nesting are allowed, so "deepest" is moved next to
+	// "deeper".
+	item := got[2].(*notionapi.BulletedListItemBlock)
+	nested := item.BulletedListItem.Children[0].(*notionapi.BulletedListItemBlock)
+	if len(nested.BulletedListItem.Children) != 2 {
+		t.Fatalf("nested item has %d children, want 2", len(nested.BulletedListItem.Children))
+	}
+	deeper := nested.BulletedListItem.Children[0].(*notionapi.BulletedListItemBlock)
+	if deeper.BulletedListItem.Children != nil {
+		t.Errorf("deeper item has children beyond the nesting limit")
+	}
+	if c := nested.BulletedListItem.Children[1].(*notionapi.BulletedListItemBlock).BulletedListItem.RichText[0].Text.Content; c != "deepest" {
+		t.Errorf("moved item = %q, want deepest", c)
+	}
+
+	if todo := got[3].(*notionapi.ToDoBlock); !todo.ToDo.Checked || todo.ToDo.RichText[0].Text.Content != "done" {
+		t.Errorf("to do = %+v", todo.ToDo)
+	}
+	if code := got[7].(*notionapi.CodeBlock); code.Code.Language != "javascript" || code.Code.RichText[0].Text.Content != "console.log(1)" {
+		t.Errorf("code = %+v", code.Code)
+	}
+	table := got[8].(*notionapi.TableBlock)
+	if table.Table.TableWidth != 2 || !table.Table.HasColumnHeader || len(table.Table.Children) != 2 {
+		t.Errorf("table = %+v", table.Table)
+	}
+	if image := got[10].(*notionapi.ImageBlock); image.Image.GetURL() != "https://example.com/cat.png" {
+		t.Errorf("image = %+v", image.Image)
+	}
+
+	b, err := json.Marshal(&notionapi.AppendBlockChildrenRequest{Children: got[:1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"children":[{"object":"block","type":"heading_1","heading_1":{"rich_text":[{"type":"text","text":{"content":"Title"}}]}}]}`
+	if string(b) != want {
+		t.Errorf("MarshalJSON() got = %s, want %s", b, want)
+	}
+}
+
+func TestMarkdownToBlocks_NestedTable(t *testing.T) {
+	md := "- item\n" +
+		"  - nested\n" +
+		"    | a | b |\n" +
+		"    |---|---|\n" +
+		"    | 1 | 2 |\n" +
+		"\n" +
+		"    after\n" +
+		"- next\n"
+
+	got := notionapi.MarkdownToBlocks(md)
+	if len(got) != 2 {
+		t.Fatalf("MarkdownToBlocks() = %d blocks, want 2", len(got))
+	}
+
+	// The rows of the table would be nested three levels deep, so the table
+	// is moved next to "nested", along with the paragraph after it.
+	var types []notionapi.BlockType
+	children := got[0].(*notionapi.BulletedListItemBlock).BulletedListItem.Children
+	for _, b := range children {
+		types = append(types, b.GetType())
+	}
+	wantTypes := []notionapi.BlockType{
+		notionapi.BlockTypeBulletedListItem,
+		notionapi.BlockTypeTableBlock,
+		notionapi.BlockTypeParagraph,
+	}
+	if !reflect.DeepEqual(types, wantTypes) {
+		t.Fatalf("item children types = %v, want %v", types, wantTypes)
+	}
+	if nested := children[0].(*notionapi.BulletedListItemBlock); nested.BulletedListItem.Children != nil {
+		t.Errorf("nested item children = %+v, want none", nested.BulletedListItem.Children)
+	}
+	if table := children[1].(*notionapi.TableBlock); len(table.Table.Children) != 2 {
+		t.Errorf("table rows = %+v, want 2 rows", table.Table.Children)
+	}
+}
@@ -0,0 +1,168 @@
+package notionapi_test
+
+import (
+	"testing"
+
+	"github.com/jomei/notionapi"
+)
+
+func text(content string) notionapi.RichText {
+	return notionapi.RichText{
+		Type:      notionapi.ObjectTypeText,
+		Text:      &notionapi.Text{Content: content},
+		PlainText: content,
+	}
+}
+
+func annotated(content string, annotations notionapi.Annotations) notionapi.RichText {
+	rt := text(content)
+	rt.Annotations = &annotations
+	return rt
+}
+
+func TestRichTextToMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		richText []notionapi.RichText
+		want     string
+	}{
+		{
+			name: "annotations",
+			richText: []notionapi.RichText{
+				annotated("bold ", notionapi.Annotations{Bold: true}),
+				annotated("italic", notionapi.Annotations{Italic: true}),
+				text(" and "),
+				annotated("a_b", notionapi.Annotations{Code: true}),
+				text(" "),
+				annotated("gone", notionapi.Annotations{Strikethrough: true, Bold: true}),
+			},
+			want: "**bold** _italic_ and `a_b` **~~gone~~**",
+		},
+		{
+			name: "escapes special characters",
+			richText: []notionapi.RichText{
+				text("2 * 3 = [six]"),
+			},
+			want: `2 \* 3 = \[six\]`,
+		},
+		{
+			name: "links and mentions",
+			richText: []notionapi.RichText{
+				{
+					Type: notionapi.ObjectTypeText,
+					Text: &notionapi.Text{Content: "Notion", Link: &notionapi.Link{Url: "https://notion.so"}},
+				},
+				text(" "),
+				{
+					Type:      "mention",
+					Mention:   &notionapi.Mention{Type: notionapi.MentionTypePage, Page: &notionapi.PageMention{ID: "some_id"}},
+					PlainText: "Some page",
+					Href:      "https://www.notion.so/some_id",
+				},
+				text(" "),
+				{
+					Type:     "equation",
+					Equation: &notionapi.Equation{Expression: "e=mc^2"},
+				},
+			},
+			want: "[Notion](https://notion.so) [Some page](https://www.notion.so/some_id) $e=mc^2$",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := notionapi.RichTextToMarkdown(tt.richText); got != tt.want {
+				t.Errorf("RichTextToMarkdown() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlocksToMarkdown(t *testing.T) {
+	emoji := notionapi.Emoji("💡")
+
+	tests := []struct {
+		name   string
+		blocks notionapi.Blocks
+		want   string
+	}{
+		{
+			name: "headings and paragraphs",
+			blocks: notionapi.Blocks{
+				&notionapi.Heading1Block{Heading1: notionapi.Heading{RichText: []notionapi.RichText{text("Title")}}},
+				&notionapi.ParagraphBlock{Paragraph: notionapi.Paragraph{RichText: []notionapi.RichText{text("Hello")}}},
+				&notionapi.Heading3Block{Heading3: notionapi.Heading{RichText: []notionapi.RichText{text("Sub")}}},
+				&notionapi.DividerBlock{},
+			},
+			want: "# Title\n\nHello\n\n### Sub\n\n---\n",
+		},
+		{
+			name: "nested lists",
+			blocks: notionapi.Blocks{
+				&notionapi.NumberedListItemBlock{NumberedListItem: notionapi.ListItem{
+					RichText: []notionapi.RichText{text("first")},
+					Children: notionapi.Blocks{
+						&notionapi.BulletedListItemBlock{BulletedListItem: notionapi.ListItem{RichText: []notionapi.RichText{text("a")}}},
+						&notionapi.BulletedListItemBlock{BulletedListItem: notionapi.ListItem{RichText: []notionapi.RichText{text("b")}}},
+					},
+				}},
+				&notionapi.NumberedListItemBlock{NumberedListItem: notionapi.ListItem{RichText: []notionapi.RichText{text("second")}}},
+				&notionapi.ToDoBlock{ToDo: notionapi.ToDo{RichText: []notionapi.RichText{text("done")}, Checked: true}},
+				&notionapi.ToDoBlock{ToDo: notionapi.ToDo{RichText: []notionapi.RichText{text("todo")}}},
+			},
+			want: "1. first\n   - a\n   - b\n2. second\n\n- [x] done\n- [ ] todo\n",
+		},
+		{
+			name: "code, quote and callout",
+			blocks: notionapi.Blocks{
+				&notionapi.CodeBlock{Code: notionapi.Code{RichText: []notionapi.RichText{text("fmt.Println(\"hi\")")}, Language: "go"}},
+				&notionapi.QuoteBlock{Quote: notionapi.Quote{RichText: []notionapi.RichText{text("quoted")}}},
+				&notionapi.CalloutBlock{Callout: notionapi.Callout{
+					RichText: []notionapi.RichText{text("note")},
+					Icon:     &notionapi.Icon{Type: "emoji", Emoji: &emoji},
+				}},
+				&notionapi.EquationBlock{Equation: notionapi.Equation{Expression: "x^2"}},
+			},
+			want: "```go\nfmt.Println(\"hi\")\n```\n\n> quoted\n\n> 💡 note\n\n$$\nx^2\n$$\n",
+		},
+		{
+			name: "table",
+			blocks: notionapi.Blocks{
+				&notionapi.TableBlock{Table: notionapi.Table{
+					TableWidth:      2,
+					HasColumnHeader: true,
+					Children: notionapi.Blocks{
+						&notionapi.TableRowBlock{TableRow: notionapi.TableRow{Cells: [][]notionapi.RichText{{text("Name")}, {text("Value")}}}},
+						&notionapi.TableRowBlock{TableRow: notionapi.TableRow{Cells: [][]notionapi.RichText{{text("a|b")}, {text("1")}}}},
+					},
+				}},
+			},
+			want: "| Name | Value |\n| --- | --- |\n| a\\|b | 1 |\n",
+		},
+		{
+			name: "media and toggles",
+			blocks: notionapi.Blocks{
+				&notionapi.ImageBlock{Image: notionapi.Image{
+					Caption:  []notionapi.RichText{text("cat")},
+					External: &notionapi.FileObject{URL: "https://example.com/cat.png"},
+				}},
+				&notionapi.BookmarkBlock{Bookmark: notionapi.Bookmark{URL: "https://example.com"}},
+				&notionapi.ToggleBlock{Toggle: notionapi.Toggle{
+					RichText: []notionapi.RichText{text("More")},
+					Children: notionapi.Blocks{
+						&notionapi.ParagraphBlock{Paragraph: notionapi.Paragraph{RichText: []notionapi.RichText{text("hidden")}}},
+					},
+				}},
+			},
+			want: "![cat](https://example.com/cat.png)\n\n[https://example.com](https://example.com)\n\n<details>\n<summary>More</summary>\n\nhidden\n\n</details>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := notionapi.BlocksToMarkdown(tt.blocks); got != tt.want {
+				t.Errorf("BlocksToMarkdown() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,431 @@
+package notionapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MigrationAction is the kind of change made by a step of a migration.
+type MigrationAction string
+
+const (
+	MigrationAddProperty    MigrationAction = "add_property"
+	MigrationRenameProperty MigrationAction = "rename_property"
+	MigrationChangeType     MigrationAction = "change_type"
+	MigrationAddOptions     MigrationAction = "add_options"
+	MigrationRemoveOptions  MigrationAction = "remove_options"
+	MigrationDeleteProperty MigrationAction = "delete_property"
+)
+
+// MigrationStep is a change of the schema of a database, made by a single
+// update request.
+type MigrationStep struct {
+	Action MigrationAction
+	// Property is the name of the property in the database, or the name of
+	// the property to add.
+	Property string
+	// Description tells what the step does, such as
+	// `rename property "Name" to "Title"`.
+	Description string
+	Request     *DatabaseUpdateRequest
+}
+
+// MigrationPlan is the list of steps migrating the schema of a database to
+// the desired properties, in the order they are applied: deletions, renames,
+// changes of type, changes of options and additions.
+type MigrationPlan struct {
+	DatabaseID DatabaseID
+	Steps      []MigrationStep
+	// Warnings are the differences that cannot be migrated through the API,
+	// such as the options of status properties.
+	Warnings []string
+}
+
+// MigrationOptions configures PlanMigration and Migrate.
+type MigrationOptions struct {
+	// Renames maps the current names of properties to their desired names.
+	// Properties are otherwise matched by ID, if the desired configuration has
+	// one, then by name. The title property of the database is matched with
+	// the desired title property.
+	Renames map[string]string
+	// DeleteUnlisted deletes the properties of the database matching no
+	// desired property. They are kept by default.
+	DeleteUnlisted bool
+	// DryRun makes Migrate report the plan without applying it.
+	DryRun bool
+}
+
+// PlanMigration compares the desired properties, keyed by their desired
+// names, with the properties of the database and returns the steps migrating
+// the database to them:
+//   - properties missing from the database are added,
+//   - properties matched under another name are renamed,
+//   - properties whose type differs are updated to the desired configuration,
+//   - options of select and multi-select properties are added and removed,
+//   - with DeleteUnlisted, properties matching no desired property are
+//     deleted.
+//
+// Other differences of configuration, such as number formats or formula
+// expressions, are not migrated.
+func PlanMigration(db *Database, desired PropertyConfigs, opts MigrationOptions) (*MigrationPlan, error) {
+	if db == nil {
+		return nil, errors.New("notionapi: no database to migrate")
+	}
+	plan := &MigrationPlan{DatabaseID: DatabaseID(db.ID)}
+
+	names := sortedPropertyNames(desired)
+	types := make(map[string]PropertyConfigType, len(desired))
+	for _, name := range names {
+		t, err := propertyConfigType(desired[name])
+		if err != nil {
+			return nil, fmt.Errorf("notionapi: desired property %q: %w", name, err)
+		}
+		types[name] = t
+	}
+	matches := matchProperties(db.Properties, desired, types, opts.Renames)
+
+	matched := make(map[string]bool, len(matches))
+	for _, live := range matches {
+		matched[live] = true
+	}
+	for _, live := range sortedPropertyNames(db.Properties) {
+		if matched[live] || !opts.DeleteUnlisted {
+			continue
+		}
+		if db.Properties[live].GetType() == PropertyConfigTypeTitle {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("title property %q cannot be deleted", live))
+			continue
+		}
+		plan.add(MigrationDeleteProperty, live, fmt.Sprintf("delete property %q", live),
+			propertyKey(db.Properties, live), DeletePropertyConfig{})
+	}
+
+	for _, name := range names {
+		if live, ok := matches[name]; ok && live != name {
+			plan.add(MigrationRenameProperty, live, fmt.Sprintf("rename property %q to %q", live, name),
+				propertyKey(db.Properties, live), RenamePropertyConfig{Name: name})
+		}
+	}
+
+	for _, name := range names {
+		live, ok := matches[name]
+		if !ok || db.Properties[live].GetType() == types[name] {
+			continue
+		}
+		plan.add(MigrationChangeType, live, fmt.Sprintf("change type of property %q from %s to %s", name, db.Properties[live].GetType(), types[name]),
+			propertyKey(db.Properties, live), normalizePropertyConfig(desired[name], types[name]))
+	}
+
+	for _, name := range names {
+		live, ok := matches[name]
+		if !ok || db.Properties[live].GetType() != types[name] {
+			continue
+		}
+		plan.options(name, live, db.Properties, desired[name], types[name])
+	}
+
+	for _, name := range names {
+		if _, ok := matches[name]; !ok {
+			plan.add(MigrationAddProperty, name, fmt.Sprintf("add %s property %q", types[name], name),
+				name, normalizePropertyConfig(desired[name], types[name]))
+		}
+	}
+	return plan, nil
+}
+
+func (p *MigrationPlan) add(action MigrationAction, property, description, key string, config PropertyConfig) {
+	p.Steps = append(p.Steps, MigrationStep{
+		Action:      action,
+		Property:    property,
+		Description: description,
+		Request:     &DatabaseUpdateRequest{Properties: PropertyConfigs{key: config}},
+	})
+}
+
+// options adds the steps adding and removing the options of a select or
+// multi-select property. As the API replaces the options of a property with
+// the given ones, each step sends every option to keep.
+func (p *MigrationPlan) options(name, live string, properties PropertyConfigs, config PropertyConfig, t PropertyConfigType) {
+	current := propertyOptions(properties[live])
+	wanted := propertyOptions(config)
+
+	var added, removed, kept []Option
+	for _, o := range wanted {
+		if !containsOption(current, o.Name) {
+			added = append(added, Option{Name: o.Name, Color: o.Color})
+		}
+	}
+	for _, o := range current {
+		if containsOption(wanted, o.Name) {
+			kept = append(kept, o)
+		} else {
+			removed = append(removed, o)
+		}
+	}
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+	if t == PropertyConfigStatus {
+		p.Warnings = append(p.Warnings, fmt.Sprintf("options of status property %q cannot be changed", name))
+		return
+	}
+
+	key := propertyKey(properties, live)
+	if len(added) > 0 {
+		options := append(append([]Option{}, current...), added...)
+		p.add(MigrationAddOptions, live, fmt.Sprintf("add options %s to property %q", optionList(added), name),
+			key, optionsPropertyConfig(t, options))
+	}
+	if len(removed) > 0 {
+		options := append(kept, added...)
+		p.add(MigrationRemoveOptions, live, fmt.Sprintf("remove options %s from property %q", optionList(removed), name),
+			key, optionsPropertyConfig(t, options))
+	}
+}
+
+// matchProperties returns the names of the properties of the database
+// matching the desired properties, keyed by desired name.
+func matchProperties(live, desired PropertyConfigs, types map[string]PropertyConfigType, renames map[string]string) map[string]string {
+	renamedFrom := make(map[string]string, len(renames))
+	for old, name := range renames {
+		renamedFrom[name] = old
+	}
+	matches := map[string]string{}
+	used := map[string]bool{}
+	match := func(name, liveName string) {
+		if _, ok := matches[name]; !ok && liveName != "" && !used[liveName] {
+			matches[name] = liveName
+			used[liveName] = true
+		}
+	}
+
+	names := sortedPropertyNames(desired)
+	for _, name := range names {
+		if id := desired[name].GetID(); id != "" {
+			for liveName, config := range live {
+				if config.GetID() == id {
+					match(name, liveName)
+				}
+			}
+		}
+	}
+	for _, name := range names {
+		if old, ok := renamedFrom[name]; ok && live[old] != nil {
+			match(name, old)
+		}
+	}
+	for _, name := range names {
+		if _, renamed := renames[name]; !renamed && live[name] != nil {
+			match(name, name)
+		}
+	}
+	for _, name := range names {
+		if types[name] != PropertyConfigTypeTitle {
+			continue
+		}
+		for liveName, config := range live {
+			if config.GetType() == PropertyConfigTypeTitle {
+				match(name, liveName)
+			}
+		}
+	}
+	return matches
+}
+
+// propertyKey returns the ID of a property of the database, or its name if
+// it has none, to update the property regardless of renames.
+func propertyKey(properties PropertyConfigs, name string) string {
+	if id := properties[name].GetID(); id != "" {
+		return string(id)
+	}
+	return name
+}
+
+func sortedPropertyNames(properties PropertyConfigs) []string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// propertyConfigType returns the type of a configuration, which is not set
+// in configurations built without it, from the key of its settings.
+func propertyConfigType(config PropertyConfig) (PropertyConfigType, error) {
+	if config == nil || reflect.ValueOf(config).Kind() == reflect.Ptr && reflect.ValueOf(config).IsNil() {
+		return "", errors.New("no configuration")
+	}
+	if t := config.GetType(); t != "" {
+		return t, nil
+	}
+	b, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return "", err
+	}
+	var keys []string
+	for key := range fields {
+		if key != "id" && key != "type" && key != "name" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) != 1 {
+		return "", fmt.Errorf("cannot tell the type of %T", config)
+	}
+	return PropertyConfigType(keys[0]), nil
+}
+
+// normalizePropertyConfig returns a copy of a configuration with its type
+// set and without ID, to be sent in update requests.
+func normalizePropertyConfig(config PropertyConfig, t PropertyConfigType) PropertyConfig {
+	v := reflect.ValueOf(config)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return config
+	}
+	c := reflect.New(v.Type())
+	c.Elem().Set(v)
+	if f := c.Elem().FieldByName("Type"); f.IsValid() && f.Kind() == reflect.String {
+		f.SetString(string(t))
+	}
+	if f := c.Elem().FieldByName("ID"); f.IsValid() && f.Kind() == reflect.String {
+		f.SetString("")
+	}
+	return c.Interface().(PropertyConfig)
+}
+
+func optionsPropertyConfig(t PropertyConfigType, options []Option) PropertyConfig {
+	if t == PropertyConfigTypeMultiSelect {
+		return &MultiSelectPropertyConfig{Type: t, MultiSelect: Select{Options: options}}
+	}
+	return &SelectPropertyConfig{Type: t, Select: Select{Options: options}}
+}
+
+func containsOption(options []Option, name string) bool {
+	for _, o := range options {
+		if o.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func optionList(options []Option) string {
+	names := make([]string, len(options))
+	for i, o := range options {
+		names[i] = fmt.Sprintf("%q", o.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// MigrationStatus is the outcome of a step of a migration.
+type MigrationStatus string
+
+const (
+	// MigrationPlanned is the status of steps of dry runs.
+	MigrationPlanned MigrationStatus = "planned"
+	MigrationApplied MigrationStatus = "applied"
+	MigrationFailed  MigrationStatus = "failed"
+	// MigrationSkipped is the status of the steps following a failed one.
+	MigrationSkipped MigrationStatus = "skipped"
+)
+
+// MigrationStepResult is the outcome of a step of a migration.
+type MigrationStepResult struct {
+	MigrationStep
+	Status MigrationStatus
+	Err    error
+}
+
+// MigrationReport tells the outcome of each step of a migration.
+type MigrationReport struct {
+	DatabaseID DatabaseID
+	DryRun     bool
+	Steps      []MigrationStepResult
+	Warnings   []string
+}
+
+func (r *MigrationReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "migration of database %s", r.DatabaseID)
+	if r.DryRun {
+		b.WriteString(" (dry run)")
+	}
+	b.WriteString(":\n")
+	if len(r.Steps) == 0 {
+		b.WriteString("  no changes\n")
+	}
+	for _, s := range r.Steps {
+		fmt.Fprintf(&b, "  %-8s %s", s.Status, s.Description)
+		if s.Err != nil {
+			fmt.Fprintf(&b, ": %v", s.Err)
+		}
+		b.WriteString("\n")
+	}
+	for _, w := range r.Warnings {
+		fmt.Fprintf(&b, "  warning: %s\n", w)
+	}
+	return b.String()
+}
+
+// Report returns the report of a dry run of the plan.
+func (p *MigrationPlan) Report() *MigrationReport {
+	report := &MigrationReport{DatabaseID: p.DatabaseID, DryRun: true, Warnings: p.Warnings}
+	for _, s := range p.Steps {
+		report.Steps = append(report.Steps, MigrationStepResult{MigrationStep: s, Status: MigrationPlanned})
+	}
+	return report
+}
+
+// Apply sends the update requests of the plan in order. It stops at the first
+// failed step, whose error it returns along with the report.
+func (p *MigrationPlan) Apply(ctx context.Context, dbs DatabaseService) (*MigrationReport, error) {
+	report := &MigrationReport{DatabaseID: p.DatabaseID, Warnings: p.Warnings}
+	var err error
+	for _, s := range p.Steps {
+		result := MigrationStepResult{MigrationStep: s, Status: MigrationSkipped}
+		if err == nil {
+			if _, err = dbs.Update(ctx, p.DatabaseID, s.Request); err != nil {
+				result.Status, result.Err = MigrationFailed, err
+			} else {
+				result.Status = MigrationApplied
+			}
+		}
+		report.Steps = append(report.Steps, result)
+	}
+	if err != nil {
+		return report, fmt.Errorf("notionapi: migration of database %s failed: %w", p.DatabaseID, err)
+	}
+	return report, nil
+}
+
+// Migrate migrates the schema of a database to the desired properties, keyed
+// by their desired names, as planned by PlanMigration. With DryRun, it only
+// reports the plan.
+func Migrate(ctx context.Context, dbs DatabaseService, id DatabaseID, desired PropertyConfigs, opts MigrationOptions) (*MigrationReport, error) {
+	db, err := dbs.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := PlanMigration(db, desired, opts)
+	if err != nil {
+		return nil, err
+	}
+	// Update requests are sent to the ID the database was fetched with.
+	plan.DatabaseID = id
+	if opts.DryRun {
+		return plan.Report(), nil
+	}
+	return plan.Apply(ctx, dbs)
+}
@@ -0,0 +1,166 @@
+package notionapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jomei/notionapi"
+)
+
+const liveDatabase = `{
+	"object": "database",
+	"id": "db",
+	"title": [],
+	"properties": {
+		"Name": {"id": "title", "type": "title", "title": {}},
+		"Estimate": {"id": "a", "type": "rich_text", "rich_text": {}},
+		"Tags": {"id": "b", "type": "multi_select", "multi_select": {"options": [
+			{"id": "o1", "name": "bug", "color": "red"},
+			{"id": "o2", "name": "chore", "color": "gray"}
+		]}},
+		"Owner": {"id": "c", "type": "people", "people": {}},
+		"Legacy": {"id": "d", "type": "checkbox", "checkbox": {}}
+	}
+}`
+
+func migrationTarget() notionapi.PropertyConfigs {
+	return notionapi.PropertyConfigs{
+		"Title":    notionapi.TitlePropertyConfig{},
+		"Estimate": &notionapi.NumberPropertyConfig{Type: notionapi.PropertyConfigTypeNumber, Number: notionapi.NumberFormat{Format: "number"}},
+		"Labels": &notionapi.MultiSelectPropertyConfig{MultiSelect: notionapi.Select{Options: []notionapi.Option{
+			{Name: "bug"}, {Name: "feature", Color: "green"},
+		}}},
+		"Assignee": notionapi.PeoplePropertyConfig{ID: "c"},
+		"Due":      notionapi.DatePropertyConfig{Type: notionapi.PropertyConfigTypeDate},
+	}
+}
+
+func TestPlanMigration(t *testing.T) {
+	var db notionapi.Database
+	if err := json.Unmarshal([]byte(liveDatabase), &db); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := notionapi.PlanMigration(&db, migrationTarget(), notionapi.MigrationOptions{
+		Renames:        map[string]string{"Tags": "Labels"},
+		DeleteUnlisted: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type step struct {
+		action  notionapi.MigrationAction
+		request string
+	}
+	var got []step
+	for _, s := range plan.Steps {
+		b, err := json.Marshal(s.Request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, step{s.Action, string(b)})
+	}
+	want := []step{
+		{notionapi.MigrationDeleteProperty, `{"properties":{"d":null}}`},
+		{notionapi.MigrationRenameProperty, `{"properties":{"c":{"name":"Assignee"}}}`},
+		{notionapi.MigrationRenameProperty, `{"properties":{"b":{"name":"Labels"}}}`},
+		{notionapi.MigrationRenameProperty, `{"properties":{"title":{"name":"Title"}}}`},
+		{notionapi.MigrationChangeType, `{"properties":{"a":{"type":"number","number":{"format":"number"}}}}`},
+		{notionapi.MigrationAddOptions, `{"properties":{"b":{"type":"multi_select","multi_select":{"options":[{"id":"o1","name":"bug","color":"red"},{"id":"o2","name":"chore","color":"gray"},{"name":"feature","color":"green"}]}}}}`},
+		{notionapi.MigrationRemoveOptions, `{"properties":{"b":{"type":"multi_select","multi_select":{"options":[{"id":"o1","name":"bug","color":"red"},{"name":"feature","color":"green"}]}}}}`},
+		{notionapi.MigrationAddProperty, `{"properties":{"Due":{"type":"date","date":{}}}}`},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PlanMigration() steps =\n%v\nwant\n%v", got, want)
+	}
+
+	t.Run("no changes", func(t *testing.T) {
+		plan, err := notionapi.PlanMigration(&db, db.Properties, notionapi.MigrationOptions{DeleteUnlisted: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(plan.Steps) != 0 {
+			t.Errorf("PlanMigration() of the live schema = %+v, want no steps", plan.Steps)
+		}
+	})
+
+	t.Run("keeps unlisted properties by default", func(t *testing.T) {
+		plan, err := notionapi.PlanMigration(&db, notionapi.PropertyConfigs{}, notionapi.MigrationOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(plan.Steps) != 0 {
+			t.Errorf("PlanMigration() = %+v, want no steps", plan.Steps)
+		}
+	})
+}
+
+func TestMigrate(t *testing.T) {
+	var updates []string
+	c := newTestClient(func(req *http.Request) *http.Response {
+		status, body := http.StatusOK, liveDatabase
+		if req.Method == http.MethodPatch {
+			b, _ := ioutil.ReadAll(req.Body)
+			updates = append(updates, string(b))
+			if strings.Contains(string(b), `"type":"number"`) {
+				status = http.StatusBadRequest
+				body = `{"object":"error","status":400,"code":"validation_error","message":"cannot convert"}`
+			}
+		}
+		return &http.Response{
+			StatusCode: status,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+			Header:     make(http.Header),
+		}
+	})
+	client := notionapi.NewClient("some_token", notionapi.WithHTTPClient(c))
+	opts := notionapi.MigrationOptions{Renames: map[string]string{"Tags": "Labels"}, DryRun: true}
+
+	report, err := notionapi.Migrate(context.Background(), client.Database, "db", migrationTarget(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 0 {
+		t.Errorf("Migrate() dry run sent %d updates", len(updates))
+	}
+	wantReport := `migration of database db (dry run):
+  planned  rename property "Owner" to "Assignee"
+  planned  rename property "Tags" to "Labels"
+  planned  rename property "Name" to "Title"
+  planned  change type of property "Estimate" from rich_text to number
+  planned  add options "feature" to property "Labels"
+  planned  remove options "chore" from property "Labels"
+  planned  add date property "Due"
+`
+	if report.String() != wantReport {
+		t.Errorf("Migrate() dry run report =\n%s\nwant\n%s", report, wantReport)
+	}
+
+	opts.DryRun = false
+	report, err = notionapi.Migrate(context.Background(), client.Database, "db", migrationTarget(), opts)
+	if err == nil {
+		t.Fatal("Migrate() error = nil for a failed step")
+	}
+	var statuses []notionapi.MigrationStatus
+	for _, s := range report.Steps {
+		statuses = append(statuses, s.Status)
+	}
+	wantStatuses := []notionapi.MigrationStatus{
+		notionapi.MigrationApplied, notionapi.MigrationApplied, notionapi.MigrationApplied,
+		notionapi.MigrationFailed,
+		notionapi.MigrationSkipped, notionapi.MigrationSkipped, notionapi.MigrationSkipped,
+	}
+	if !reflect.DeepEqual(statuses, wantStatuses) {
+		t.Errorf("Migrate() statuses = %v, want %v\n%s", statuses, wantStatuses, report)
+	}
+	if len(updates) != 4 {
+		t.Errorf("Migrate() sent %d updates, want 4", len(updates))
+	}
+}
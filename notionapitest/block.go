@@ -0,0 +1,346 @@
+package notionapitest
+
+import (
+	"net/http"
+
+	"github.com/jomei/notionapi"
+)
+
+const (
+	// maxAppendChildren is the maximum number of blocks appended in a single
+	// request.
+	maxAppendChildren = 100
+	// maxAppendNesting is the maximum number of levels of children accepted
+	// in a single request.
+	maxAppendNesting = 2
+)
+
+// blockFields are the fields of block objects that are not the type-specific
+// content.
+var blockFields = map[string]bool{
+	"object":           true,
+	"id":               true,
+	"type":             true,
+	"created_time":     true,
+	"last_edited_time": true,
+	"created_by":       true,
+	"last_edited_by":   true,
+	"has_children":     true,
+	"archived":         true,
+	"in_trash":         true,
+	"parent":           true,
+}
+
+func (s *Server) block(id string) (object, error) {
+	b, ok := s.blocks[key(id)]
+	if !ok {
+		return nil, notFoundError("block", id)
+	}
+	return b, nil
+}
+
+// blockType returns the type of a block object of a request.
+func blockType(b object) string {
+	if kind, ok := b["type"].(string); ok {
+		return kind
+	}
+	for k := range b {
+		if !blockFields[k] {
+			return k
+		}
+	}
+	return ""
+}
+
+// validateBlocks checks blocks to append before any of them is stored, so
+// that invalid requests have no effect.
+func validateBlocks(children []interface{}, depth int) error {
+	if len(children) > maxAppendChildren {
+		return validationError("body failed validation: body.children.length should be ≤ `%d`, instead was `%d`.", maxAppendChildren, len(children))
+	}
+	for _, c := range children {
+		b, ok := c.(object)
+		if !ok {
+			return validationError("body failed validation: body.children should be an array of block objects.")
+		}
+		kind := blockType(b)
+		switch kind {
+		case "":
+			return validationError("body failed validation: body.children should be an array of block objects.")
+		case notionapi.BlockTypeChildPage.String(), notionapi.BlockTypeChildDatabase.String():
+			return validationError("Blocks of type %s cannot be appended, create a page or a database instead.", kind)
+		}
+		content, _ := b[kind].(object)
+		nested, _ := content["children"].([]interface{})
+		if len(nested) == 0 {
+			continue
+		}
+		if depth >= maxAppendNesting {
+			return validationError("body failed validation: children can only be nested %d levels deep in a single request.", maxAppendNesting)
+		}
+		if err := validateBlocks(nested, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertBlocks stores blocks as children of the parent page or block, after
+// the child with the given ID or at the end, and returns them.
+func (s *Server) insertBlocks(parent object, children []interface{}, after string) []object {
+	parentID := parent["id"].(string)
+	ref := object{"type": string(notionapi.ParentTypeBlockID), "block_id": parentID}
+	if parent["object"] == notionapi.ObjectTypePage.String() {
+		ref = object{"type": string(notionapi.ParentTypePageID), "page_id": parentID}
+	}
+
+	var blocks []object
+	for _, c := range children {
+		in := c.(object)
+		kind := blockType(in)
+		content := object{}
+		if v, ok := in[kind].(object); ok {
+			for k, v := range v {
+				content[k] = v
+			}
+		}
+		nested, _ := content["children"].([]interface{})
+		delete(content, "children")
+		normalizeBlockContent(kind, content)
+
+		now := s.timestamp()
+		b := object{
+			"object":           notionapi.ObjectTypeBlock.String(),
+			"id":               s.newID(),
+			"parent":           ref,
+			"created_time":     now,
+			"last_edited_time": now,
+			"created_by":       partialUser(s.bot),
+			"last_edited_by":   partialUser(s.bot),
+			"has_children":     false,
+			"archived":         false,
+			"type":             kind,
+			kind:               content,
+		}
+		s.blocks[key(b["id"].(string))] = b
+		blocks = append(blocks, b)
+		s.insertBlocks(b, nested, "")
+	}
+
+	ids := make([]string, len(blocks))
+	for i, b := range blocks {
+		ids[i] = b["id"].(string)
+	}
+	existing := s.children[key(parentID)]
+	pos := len(existing)
+	for i, id := range existing {
+		if after != "" && key(id) == key(after) {
+			pos = i + 1
+		}
+	}
+	result := append([]string{}, existing[:pos]...)
+	result = append(result, ids...)
+	s.children[key(parentID)] = append(result, existing[pos:]...)
+	return blocks
+}
+
+// insertChildBlock adds the child_page or child_database block of a new page
+// or database to its parent page. The block has the ID of the page or
+// database.
+func (s *Server) insertChildBlock(parentID string, o object, kind string, content object) {
+	id := o["id"].(string)
+	s.blocks[key(id)] = object{
+		"object":           notionapi.ObjectTypeBlock.String(),
+		"id":               id,
+		"parent":           object{"type": string(notionapi.ParentTypePageID), "page_id": parentID},
+		"created_time":     o["created_time"],
+		"last_edited_time": o["last_edited_time"],
+		"created_by":       o["created_by"],
+		"last_edited_by":   o["last_edited_by"],
+		"has_children":     false,
+		"archived":         false,
+		"type":             kind,
+		kind:               content,
+	}
+	s.children[key(parentID)] = append(s.children[key(parentID)], id)
+}
+
+// normalizeBlockContent fills in the type-specific content of a block as in
+// API responses.
+func normalizeBlockContent(kind string, content object) {
+	for _, k := range []string{"rich_text", "caption"} {
+		if v, ok := content[k]; ok {
+			content[k] = normalizeRichText(v)
+		}
+	}
+	if cells, ok := content["cells"].([]interface{}); ok {
+		for i, cell := range cells {
+			cells[i] = normalizeRichText(cell)
+		}
+	}
+	if content["type"] == nil {
+		for _, t := range []string{"external", "file", "file_upload"} {
+			if _, ok := content[t]; ok {
+				content["type"] = t
+			}
+		}
+	}
+	switch kind {
+	case notionapi.BlockTypeParagraph.String(), notionapi.BlockTypeQuote.String(), notionapi.BlockTypeToggle.String(),
+		notionapi.BlockTypeBulletedListItem.String(), notionapi.BlockTypeNumberedListItem.String(),
+		notionapi.BlockTypeHeading1.String(), notionapi.BlockTypeHeading2.String(), notionapi.BlockTypeHeading3.String():
+		if content["color"] == nil {
+			content["color"] = "default"
+		}
+	case notionapi.BlockTypeToDo.String():
+		if content["checked"] == nil {
+			content["checked"] = false
+		}
+	case notionapi.BlockTypeCode.String():
+		if content["language"] == nil {
+			content["language"] = "plain text"
+		}
+	}
+}
+
+// blockResponse returns the block as in API responses, with has_children
+// set from its current children.
+func (s *Server) blockResponse(b object) object {
+	res := object{}
+	for k, v := range b {
+		res[k] = v
+	}
+	res["has_children"] = len(s.childBlocks(b["id"].(string))) > 0
+	return res
+}
+
+// childBlocks returns the children of a page or block that are not archived.
+func (s *Server) childBlocks(parentID string) []object {
+	var blocks []object
+	for _, id := range s.children[key(parentID)] {
+		if b := s.blocks[key(id)]; b["archived"] != true {
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks
+}
+
+// blockParent returns the page or block with the given ID.
+func (s *Server) blockParent(id string) (object, error) {
+	if b, ok := s.blocks[key(id)]; ok && b["type"] != notionapi.BlockTypeChildPage.String() {
+		return b, nil
+	}
+	if page, ok := s.pages[key(id)]; ok {
+		return page, nil
+	}
+	return nil, notFoundError("block", id)
+}
+
+func (s *Server) getBlock(r *http.Request, _ object, ids []string) (interface{}, error) {
+	b, err := s.block(ids[0])
+	if err != nil {
+		return nil, err
+	}
+	return s.blockResponse(b), nil
+}
+
+func (s *Server) updateBlock(r *http.Request, body object, ids []string) (interface{}, error) {
+	b, err := s.block(ids[0])
+	if err != nil {
+		return nil, err
+	}
+	kind := b["type"].(string)
+	for k, v := range body {
+		switch {
+		case k == "archived" || k == "in_trash":
+			archived, _ := v.(bool)
+			s.archiveBlock(b, archived)
+		case k == kind:
+			in, _ := v.(object)
+			content := b[kind].(object)
+			for field, value := range in {
+				if field != "children" {
+					content[field] = value
+				}
+			}
+			normalizeBlockContent(kind, content)
+		case !blockFields[k]:
+			return nil, validationError("body failed validation: the block type %s does not match the existing block type %s.", k, kind)
+		}
+	}
+	b["last_edited_time"] = s.timestamp()
+	b["last_edited_by"] = partialUser(s.bot)
+	return s.blockResponse(b), nil
+}
+
+func (s *Server) deleteBlock(r *http.Request, _ object, ids []string) (interface{}, error) {
+	b, err := s.block(ids[0])
+	if err != nil {
+		return nil, err
+	}
+	s.archiveBlock(b, true)
+	b["last_edited_time"] = s.timestamp()
+	return s.blockResponse(b), nil
+}
+
+// archiveBlock archives or restores a block, and the page or database of
+// child_page and child_database blocks.
+func (s *Server) archiveBlock(b object, archived bool) {
+	b["archived"] = archived
+	id := key(b["id"].(string))
+	if page, ok := s.pages[id]; ok {
+		page["archived"] = archived
+	}
+	if db, ok := s.databases[id]; ok {
+		db["archived"] = archived
+	}
+}
+
+func (s *Server) getBlockChildren(r *http.Request, _ object, ids []string) (interface{}, error) {
+	parent, err := s.blockParent(ids[0])
+	if err != nil {
+		return nil, err
+	}
+	cursor, size, err := pagination(r, nil)
+	if err != nil {
+		return nil, err
+	}
+	results, next, hasMore, err := paginate(s.childBlocks(parent["id"].(string)), cursor, size)
+	if err != nil {
+		return nil, err
+	}
+	for i, b := range results {
+		results[i] = s.blockResponse(b)
+	}
+	return list(results, next, hasMore, notionapi.ObjectTypeBlock.String()), nil
+}
+
+func (s *Server) appendBlockChildren(r *http.Request, body object, ids []string) (interface{}, error) {
+	parent, err := s.blockParent(ids[0])
+	if err != nil {
+		return nil, err
+	}
+	children, ok := body["children"].([]interface{})
+	if !ok {
+		return nil, validationError("body failed validation: body.children should be defined, instead was `undefined`.")
+	}
+	if err := validateBlocks(children, 0); err != nil {
+		return nil, err
+	}
+	after, _ := body["after"].(string)
+	if after != "" {
+		found := false
+		for _, id := range s.children[key(parent["id"].(string))] {
+			found = found || key(id) == key(after)
+		}
+		if !found {
+			return nil, validationError("The block %s to append after is not a child of %s.", after, ids[0])
+		}
+	}
+
+	results := s.insertBlocks(parent, children, after)
+	for i, b := range results {
+		results[i] = s.blockResponse(b)
+	}
+	return list(results, "", false, notionapi.ObjectTypeBlock.String()), nil
+}
@@ -0,0 +1,285 @@
+package notionapitest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/jomei/notionapi"
+)
+
+// propertyTypes are the types of database properties.
+var propertyTypes = []string{
+	"title", "rich_text", "number", "select", "multi_select", "status", "date",
+	"people", "files", "checkbox", "url", "email", "phone_number", "formula",
+	"relation", "rollup", "created_time", "created_by", "last_edited_time",
+	"last_edited_by", "unique_id", "verification", "button",
+}
+
+func (s *Server) database(id string) (object, error) {
+	db, ok := s.databases[key(id)]
+	if !ok {
+		return nil, notFoundError("database", id)
+	}
+	return db, nil
+}
+
+// configType returns the type of a property schema object of a request.
+func configType(config object) string {
+	if kind, ok := config["type"].(string); ok && kind != "" {
+		return kind
+	}
+	for _, kind := range propertyTypes {
+		if _, ok := config[kind]; ok {
+			return kind
+		}
+	}
+	return ""
+}
+
+// newPropertyConfig returns a property schema object as in API responses.
+func (s *Server) newPropertyConfig(name, kind string, v interface{}) object {
+	id := "title"
+	if kind != "title" {
+		s.seq++
+		id = fmt.Sprintf("%04x", s.seq)
+	}
+	settings, _ := v.(object)
+	if settings == nil {
+		settings = object{}
+	}
+	switch kind {
+	case "number":
+		if settings["format"] == nil {
+			settings["format"] = "number"
+		}
+	case "select", "multi_select", "status":
+		options, _ := settings["options"].([]interface{})
+		if kind == "status" && len(options) == 0 {
+			for _, name := range []string{"Not started", "In progress", "Done"} {
+				options = append(options, object{"name": name})
+			}
+		}
+		normalized := []interface{}{}
+		for _, o := range options {
+			option, ok := o.(object)
+			if !ok {
+				continue
+			}
+			if option["id"] == nil {
+				option["id"] = s.newID()
+			}
+			if option["color"] == nil {
+				option["color"] = "default"
+			}
+			normalized = append(normalized, option)
+		}
+		settings["options"] = normalized
+	}
+	return object{"id": id, "name": name, "type": kind, kind: settings}
+}
+
+func (s *Server) createDatabase(r *http.Request, body object, _ []string) (interface{}, error) {
+	parent, err := s.parent(body["parent"], false)
+	if err != nil {
+		return nil, err
+	}
+	if parent["page_id"] == nil {
+		return nil, validationError("body failed validation: body.parent.page_id should be defined.")
+	}
+
+	values, _ := body["properties"].(object)
+	configs := object{}
+	titles := 0
+	for name, v := range values {
+		config, _ := v.(object)
+		kind := configType(config)
+		if kind == "" {
+			return nil, validationError("body failed validation: body.properties.%s should be a property schema object.", name)
+		}
+		if kind == "title" {
+			titles++
+		}
+		configs[name] = s.newPropertyConfig(name, kind, config[kind])
+	}
+	if titles != 1 {
+		return nil, validationError("Title property must be specified exactly once in the properties of a database.")
+	}
+
+	now := s.timestamp()
+	id := s.newID()
+	isInline, _ := body["is_inline"].(bool)
+	db := object{
+		"object":           notionapi.ObjectTypeDatabase.String(),
+		"id":               id,
+		"created_time":     now,
+		"last_edited_time": now,
+		"created_by":       partialUser(s.bot),
+		"last_edited_by":   partialUser(s.bot),
+		"title":            normalizeRichText(body["title"]),
+		"description":      normalizeRichText(body["description"]),
+		"icon":             body["icon"],
+		"cover":            body["cover"],
+		"properties":       configs,
+		"parent":           parent,
+		"url":              "https://www.notion.so/" + key(id),
+		"public_url":       nil,
+		"archived":         false,
+		"is_inline":        isInline,
+	}
+	s.databases[key(id)] = db
+	s.order = append(s.order, id)
+	s.insertChildBlock(parent["page_id"].(string), db, notionapi.BlockTypeChildDatabase.String(), object{"title": plainText(db["title"])})
+	return db, nil
+}
+
+func (s *Server) getDatabase(r *http.Request, _ object, ids []string) (interface{}, error) {
+	return s.database(ids[0])
+}
+
+func (s *Server) updateDatabase(r *http.Request, body object, ids []string) (interface{}, error) {
+	db, err := s.database(ids[0])
+	if err != nil {
+		return nil, err
+	}
+
+	values, _ := body["properties"].(object)
+	if err := s.updatePropertyConfigs(db, values); err != nil {
+		return nil, err
+	}
+	for _, k := range []string{"title", "description"} {
+		if v, ok := body[k]; ok {
+			db[k] = normalizeRichText(v)
+		}
+	}
+	for _, k := range []string{"icon", "cover"} {
+		if v, ok := body[k]; ok {
+			db[k] = v
+		}
+	}
+	for _, k := range []string{"archived", "is_inline"} {
+		if v, ok := body[k].(bool); ok {
+			db[k] = v
+		}
+	}
+	if archived, ok := body["archived"].(bool); ok {
+		if b, ok := s.blocks[key(ids[0])]; ok {
+			b["archived"] = archived
+		}
+	}
+
+	db["last_edited_time"] = s.timestamp()
+	db["last_edited_by"] = partialUser(s.bot)
+	if b, ok := s.blocks[key(ids[0])]; ok {
+		b[notionapi.BlockTypeChildDatabase.String()] = object{"title": plainText(db["title"])}
+	}
+	return db, nil
+}
+
+// updatePropertyConfigs adds, changes, renames and removes the properties of
+// the database, updating the values of its pages accordingly. Properties are
+// removed with a null value and renamed with an object with only a name.
+func (s *Server) updatePropertyConfigs(db object, values object) error {
+	configs := db["properties"].(object)
+	pages := s.databasePages(db, true)
+	for nameOrID, v := range values {
+		name, config := findProperty(configs, nameOrID)
+		in, _ := v.(object)
+
+		switch {
+		case v == nil:
+			if config == nil {
+				return validationError("%s is not a property that exists.", nameOrID)
+			}
+			if config["type"] == "title" {
+				return validationError("Cannot delete the title property %s.", name)
+			}
+			delete(configs, name)
+			for _, page := range pages {
+				delete(page["properties"].(object), name)
+			}
+			continue
+		case in == nil:
+			return validationError("body failed validation: body.properties.%s should be an object or null.", nameOrID)
+		case config == nil:
+			kind := configType(in)
+			if kind == "" {
+				return validationError("body failed validation: body.properties.%s should be a property schema object.", nameOrID)
+			}
+			configs[nameOrID] = s.newPropertyConfig(nameOrID, kind, in[kind])
+			for _, page := range pages {
+				s.fillProperties(db, page)
+			}
+			continue
+		}
+
+		if kind := configType(in); kind != "" && kind != config["type"] {
+			if config["type"] == "title" || kind == "title" {
+				return validationError("Cannot change the type of the title property %s.", name)
+			}
+			id := config["id"]
+			config = s.newPropertyConfig(name, kind, in[kind])
+			config["id"] = id
+			configs[name] = config
+			for _, page := range pages {
+				delete(page["properties"].(object), name)
+				s.fillProperties(db, page)
+			}
+		} else if kind != "" {
+			config[kind] = s.newPropertyConfig(name, kind, in[kind])[kind]
+		}
+
+		if newName, ok := in["name"].(string); ok && newName != name {
+			if _, exists := configs[newName]; exists {
+				return validationError("A property named %s already exists.", newName)
+			}
+			config["name"] = newName
+			configs[newName] = config
+			delete(configs, name)
+			for _, page := range pages {
+				props := page["properties"].(object)
+				props[newName] = props[name]
+				delete(props, name)
+			}
+		}
+	}
+	return nil
+}
+
+// queryDatabase returns the pages of the database matching the filter of the
+// request. Unless sorts are given, pages are returned in creation order.
+func (s *Server) queryDatabase(r *http.Request, body object, ids []string) (interface{}, error) {
+	db, err := s.database(ids[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var pages []object
+	for _, page := range s.databasePages(db, false) {
+		if filter, ok := body["filter"].(object); ok {
+			match, err := s.matchFilter(db, page, filter, 0)
+			if err != nil {
+				return nil, err
+			}
+			if !match {
+				continue
+			}
+		}
+		pages = append(pages, page)
+	}
+
+	if sorts, ok := body["sorts"].([]interface{}); ok {
+		if err := s.sortPages(db, pages, sorts); err != nil {
+			return nil, err
+		}
+	}
+
+	cursor, size, err := pagination(r, body)
+	if err != nil {
+		return nil, err
+	}
+	results, next, hasMore, err := paginate(pages, cursor, size)
+	if err != nil {
+		return nil, err
+	}
+	return list(results, next, hasMore, "page_or_database"), nil
+}
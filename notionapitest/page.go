@@ -0,0 +1,433 @@
+package notionapitest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jomei/notionapi"
+)
+
+// computedProperties are the property types whose values are set by Notion
+// and cannot be updated through the API.
+var computedProperties = map[string]bool{
+	"formula":          true,
+	"rollup":           true,
+	"created_time":     true,
+	"created_by":       true,
+	"last_edited_time": true,
+	"last_edited_by":   true,
+	"unique_id":        true,
+	"button":           true,
+}
+
+func (s *Server) page(id string) (object, error) {
+	page, ok := s.pages[key(id)]
+	if !ok {
+		return nil, notFoundError("page", id)
+	}
+	return page, nil
+}
+
+// parent resolves the parent of a new page or database.
+func (s *Server) parent(v interface{}, allowDatabase bool) (object, error) {
+	p, _ := v.(object)
+	if id, ok := p["database_id"].(string); ok && allowDatabase {
+		db, err := s.database(id)
+		if err != nil {
+			return nil, err
+		}
+		return object{"type": string(notionapi.ParentTypeDatabaseID), "database_id": db["id"]}, nil
+	}
+	if id, ok := p["page_id"].(string); ok {
+		page, err := s.page(id)
+		if err != nil {
+			return nil, err
+		}
+		return object{"type": string(notionapi.ParentTypePageID), "page_id": page["id"]}, nil
+	}
+	if p["workspace"] == true {
+		return object{"type": string(notionapi.ParentTypeWorkspace), "workspace": true}, nil
+	}
+	return nil, validationError("body failed validation: body.parent should be a page or a database.")
+}
+
+// parentDatabase returns the database the page belongs to, if any.
+func (s *Server) parentDatabase(page object) object {
+	parent, _ := page["parent"].(object)
+	id, ok := parent["database_id"].(string)
+	if !ok {
+		return nil
+	}
+	return s.databases[key(id)]
+}
+
+// newPage returns a new page with no properties. It is not stored until
+// insertPage is called.
+func (s *Server) newPage(parent object) object {
+	now := s.timestamp()
+	id := s.newID()
+	return object{
+		"object":           notionapi.ObjectTypePage.String(),
+		"id":               id,
+		"created_time":     now,
+		"last_edited_time": now,
+		"created_by":       partialUser(s.bot),
+		"last_edited_by":   partialUser(s.bot),
+		"cover":            nil,
+		"icon":             nil,
+		"parent":           parent,
+		"archived":         false,
+		"properties":       object{},
+		"url":              "https://www.notion.so/" + key(id),
+		"public_url":       nil,
+	}
+}
+
+func (s *Server) insertPage(page object) {
+	id := page["id"].(string)
+	s.pages[key(id)] = page
+	s.order = append(s.order, id)
+
+	parent, _ := page["parent"].(object)
+	if parentID, ok := parent["page_id"].(string); ok {
+		s.insertChildBlock(parentID, page, notionapi.BlockTypeChildPage.String(), object{"title": pageTitle(page)})
+	}
+}
+
+// pageTitle returns the plain text of the title property of the page.
+func pageTitle(page object) string {
+	props, _ := page["properties"].(object)
+	for _, v := range props {
+		if p, ok := v.(object); ok && p["type"] == "title" {
+			return plainText(p["title"])
+		}
+	}
+	return ""
+}
+
+func (s *Server) createPage(r *http.Request, body object, _ []string) (interface{}, error) {
+	parent, err := s.parent(body["parent"], true)
+	if err != nil {
+		return nil, err
+	}
+	page := s.newPage(parent)
+	db := s.parentDatabase(page)
+	if db != nil {
+		s.fillProperties(db, page)
+	}
+	values, _ := body["properties"].(object)
+	if err := s.setProperties(db, page, values); err != nil {
+		return nil, err
+	}
+	if db != nil {
+		s.updateComputedProperties(db, page)
+	}
+	for _, k := range []string{"icon", "cover"} {
+		if v, ok := body[k]; ok {
+			page[k] = v
+		}
+	}
+
+	children, _ := body["children"].([]interface{})
+	if err := validateBlocks(children, 0); err != nil {
+		return nil, err
+	}
+	s.insertPage(page)
+	s.insertBlocks(page, children, "")
+	return page, nil
+}
+
+func (s *Server) getPage(r *http.Request, _ object, ids []string) (interface{}, error) {
+	return s.page(ids[0])
+}
+
+func (s *Server) updatePage(r *http.Request, body object, ids []string) (interface{}, error) {
+	page, err := s.page(ids[0])
+	if err != nil {
+		return nil, err
+	}
+	db := s.parentDatabase(page)
+	values, _ := body["properties"].(object)
+	if err := s.setProperties(db, page, values); err != nil {
+		return nil, err
+	}
+	for _, k := range []string{"icon", "cover"} {
+		if v, ok := body[k]; ok {
+			page[k] = v
+		}
+	}
+	if archived, ok := body["archived"].(bool); ok {
+		page["archived"] = archived
+		if b, ok := s.blocks[key(ids[0])]; ok {
+			b["archived"] = archived
+		}
+	}
+
+	page["last_edited_time"] = s.timestamp()
+	page["last_edited_by"] = partialUser(s.bot)
+	if db != nil {
+		s.updateComputedProperties(db, page)
+	}
+	if b, ok := s.blocks[key(ids[0])]; ok {
+		b[notionapi.BlockTypeChildPage.String()] = object{"title": pageTitle(page)}
+	}
+	return page, nil
+}
+
+// setProperties validates and sets the values of the properties of a page.
+// Pages that are not in a database only have a title.
+func (s *Server) setProperties(db, page object, values object) error {
+	props := page["properties"].(object)
+	if db == nil {
+		for name, v := range values {
+			value, _ := v.(object)
+			title, ok := value["title"]
+			if !ok || name != "title" {
+				return validationError("Invalid property identifier for page not in a database: %s.", name)
+			}
+			props["title"] = object{"id": "title", "type": "title", "title": normalizeRichText(title)}
+		}
+		return nil
+	}
+
+	configs := db["properties"].(object)
+	for nameOrID, v := range values {
+		name, config := findProperty(configs, nameOrID)
+		if config == nil {
+			return validationError("%s is not a property that exists.", nameOrID)
+		}
+		kind := config["type"].(string)
+		if computedProperties[kind] {
+			return validationError("%s is a %s property and cannot be updated.", name, kind)
+		}
+		value, _ := v.(object)
+		if value == nil {
+			return validationError("body failed validation: body.properties.%s should be an object.", name)
+		}
+		normalized, err := s.propertyValue(config, value[kind])
+		if err != nil {
+			return err
+		}
+		prop := object{"id": config["id"], "type": kind, kind: normalized}
+		if kind == "relation" {
+			prop["has_more"] = false
+		}
+		props[name] = prop
+	}
+	return nil
+}
+
+// findProperty returns the name and configuration of the database property
+// with the given name or ID.
+func findProperty(configs object, nameOrID string) (string, object) {
+	if c, ok := configs[nameOrID].(object); ok {
+		return nameOrID, c
+	}
+	for name, v := range configs {
+		if c, ok := v.(object); ok && c["id"] == nameOrID {
+			return name, c
+		}
+	}
+	return "", nil
+}
+
+// propertyValue normalizes the value of a property of the given
+// configuration, as in API responses.
+func (s *Server) propertyValue(config object, v interface{}) (interface{}, error) {
+	kind := config["type"].(string)
+	switch kind {
+	case "title", "rich_text":
+		return normalizeRichText(v), nil
+	case "select", "status":
+		if v == nil {
+			return nil, nil
+		}
+		return s.option(config, v)
+	case "multi_select":
+		items, _ := v.([]interface{})
+		options := []interface{}{}
+		for _, item := range items {
+			option, err := s.option(config, item)
+			if err != nil {
+				return nil, err
+			}
+			options = append(options, option)
+		}
+		return options, nil
+	case "people":
+		items, _ := v.([]interface{})
+		users := []interface{}{}
+		for _, item := range items {
+			u, _ := item.(object)
+			id, _ := u["id"].(string)
+			if user := s.user(id); user != nil {
+				users = append(users, user)
+			} else {
+				users = append(users, object{"object": notionapi.ObjectTypeUser.String(), "id": id})
+			}
+		}
+		return users, nil
+	case "relation":
+		items, _ := v.([]interface{})
+		relations := []interface{}{}
+		for _, item := range items {
+			r, _ := item.(object)
+			relations = append(relations, object{"id": r["id"]})
+		}
+		return relations, nil
+	case "files":
+		if v == nil {
+			return []interface{}{}, nil
+		}
+	}
+	return v, nil
+}
+
+// option returns the select option with the given name or ID. Select options
+// that do not exist yet are added to the database, like the API does.
+func (s *Server) option(config object, v interface{}) (object, error) {
+	kind := config["type"].(string)
+	in, _ := v.(object)
+	settings, _ := config[kind].(object)
+	options, _ := settings["options"].([]interface{})
+	for _, o := range options {
+		option := o.(object)
+		if (in["id"] != nil && option["id"] == in["id"]) || (in["name"] != nil && option["name"] == in["name"]) {
+			return option, nil
+		}
+	}
+
+	name, _ := in["name"].(string)
+	if kind == "status" || name == "" {
+		return nil, validationError("Invalid %s option %v.", kind, v)
+	}
+	option := object{"id": s.newID(), "name": name, "color": "default"}
+	if color, ok := in["color"].(string); ok {
+		option["color"] = color
+	}
+	settings["options"] = append(options, option)
+	return option, nil
+}
+
+// emptyPropertyValue returns the value of a property of a new page.
+func emptyPropertyValue(kind string) interface{} {
+	switch kind {
+	case "title", "rich_text", "multi_select", "people", "relation", "files":
+		return []interface{}{}
+	case "checkbox":
+		return false
+	case "formula":
+		return object{"type": "string", "string": nil}
+	case "rollup":
+		return object{"type": "array", "array": []interface{}{}, "function": "show_original"}
+	}
+	return nil
+}
+
+// fillProperties sets every property of the database on the page to its
+// empty value.
+func (s *Server) fillProperties(db, page object) {
+	props := page["properties"].(object)
+	for name, v := range db["properties"].(object) {
+		config := v.(object)
+		if _, ok := props[name]; ok {
+			continue
+		}
+		kind := config["type"].(string)
+		prop := object{"id": config["id"], "type": kind, kind: emptyPropertyValue(kind)}
+		if kind == "relation" {
+			prop["has_more"] = false
+		}
+		if kind == "unique_id" {
+			settings, _ := config[kind].(object)
+			prop[kind] = object{"prefix": settings["prefix"], "number": len(s.databasePages(db, true)) + 1}
+		}
+		props[name] = prop
+	}
+}
+
+// updateComputedProperties sets the values of the properties of the page
+// that depend on its creation and last edition.
+func (s *Server) updateComputedProperties(db, page object) {
+	props := page["properties"].(object)
+	for name, v := range db["properties"].(object) {
+		kind := v.(object)["type"].(string)
+		switch kind {
+		case "created_time", "last_edited_time", "created_by", "last_edited_by":
+			props[name].(object)[kind] = page[kind]
+		}
+	}
+}
+
+// databasePages returns the pages of the database in creation order.
+func (s *Server) databasePages(db object, archived bool) []object {
+	var pages []object
+	for _, id := range s.order {
+		page, ok := s.pages[key(id)]
+		if !ok || (!archived && page["archived"] == true) {
+			continue
+		}
+		if p := s.parentDatabase(page); p != nil && p["id"] == db["id"] {
+			pages = append(pages, page)
+		}
+	}
+	return pages
+}
+
+// paginatedProperties are returned by the page property endpoint as a list
+// of property items.
+var paginatedProperties = map[string]bool{
+	"title":     true,
+	"rich_text": true,
+	"people":    true,
+	"relation":  true,
+}
+
+func (s *Server) getPageProperty(r *http.Request, _ object, ids []string) (interface{}, error) {
+	page, err := s.page(ids[0])
+	if err != nil {
+		return nil, err
+	}
+	var prop object
+	for name, v := range page["properties"].(object) {
+		if p := v.(object); p["id"] == ids[1] || name == ids[1] {
+			prop = p
+		}
+	}
+	if prop == nil {
+		return nil, notFoundError("property", ids[1])
+	}
+
+	kind := prop["type"].(string)
+	if !paginatedProperties[kind] {
+		return object{"object": notionapi.ObjectTypePropertyItem.String(), "id": prop["id"], "type": kind, kind: prop[kind]}, nil
+	}
+
+	values, _ := prop[kind].([]interface{})
+	cursor, size, err := pagination(r, nil)
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 || size > 100 {
+		size = 100
+	}
+	start, _ := strconv.Atoi(cursor)
+	if start > len(values) {
+		start = len(values)
+	}
+	end := start + size
+	next := ""
+	if end < len(values) {
+		next = strconv.Itoa(end)
+	} else {
+		end = len(values)
+	}
+
+	items := []object{}
+	for _, v := range values[start:end] {
+		items = append(items, object{"object": notionapi.ObjectTypePropertyItem.String(), "id": prop["id"], "type": kind, kind: v})
+	}
+	res := list(items, next, next != "", notionapi.ObjectTypePropertyItem.String())
+	res["property_item"] = object{"id": prop["id"], "type": kind, "next_url": nil, kind: object{}}
+	return res, nil
+}
@@ -0,0 +1,529 @@
+package notionapitest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxFilterNesting is the maximum depth of compound filters accepted by the
+// API.
+const maxFilterNesting = 2
+
+// matchFilter reports whether the page of the database matches the filter of
+// a database query.
+func (s *Server) matchFilter(db, page object, filter object, depth int) (bool, error) {
+	for _, op := range []string{"and", "or"} {
+		v, ok := filter[op]
+		if !ok {
+			continue
+		}
+		if depth >= maxFilterNesting {
+			return false, validationError("body failed validation: compound filters can only be nested %d levels deep.", maxFilterNesting)
+		}
+		filters, ok := v.([]interface{})
+		if !ok {
+			return false, validationError("body failed validation: body.filter.%s should be an array.", op)
+		}
+		for _, f := range filters {
+			sub, _ := f.(object)
+			match, err := s.matchFilter(db, page, sub, depth+1)
+			if err != nil {
+				return false, err
+			}
+			if op == "and" && !match {
+				return false, nil
+			}
+			if op == "or" && match {
+				return true, nil
+			}
+		}
+		return op == "and", nil
+	}
+
+	if ts, ok := filter["timestamp"].(string); ok {
+		if ts != "created_time" && ts != "last_edited_time" {
+			return false, validationError("body failed validation: body.filter.timestamp should be `\"created_time\"` or `\"last_edited_time\"`.")
+		}
+		cond, _ := filter[ts].(object)
+		return s.matchDate(parseTime(page[ts]), cond)
+	}
+
+	nameOrID, ok := filter["property"].(string)
+	if !ok {
+		return false, validationError("body failed validation: body.filter should be a property, timestamp or compound filter.")
+	}
+	name, config := findProperty(db["properties"].(object), nameOrID)
+	if config == nil {
+		return false, validationError("Could not find property with name or id: %s", nameOrID)
+	}
+	var cond object
+	for k, v := range filter {
+		if k != "property" && k != "type" {
+			cond, _ = v.(object)
+		}
+	}
+	if cond == nil {
+		return false, validationError("body failed validation: body.filter should define a condition for property %s.", name)
+	}
+	prop, _ := page["properties"].(object)[name].(object)
+	kind := config["type"].(string)
+	return s.matchProperty(kind, prop[kind], cond)
+}
+
+// matchProperty reports whether the value of a property of the given type
+// matches the condition.
+func (s *Server) matchProperty(kind string, value interface{}, cond object) (bool, error) {
+	switch kind {
+	case "title", "rich_text":
+		return matchText(plainText(value), cond)
+	case "url", "email", "phone_number":
+		text, _ := value.(string)
+		return matchText(text, cond)
+	case "number":
+		n, ok := value.(float64)
+		if !ok {
+			return matchNumber(nil, cond)
+		}
+		return matchNumber(&n, cond)
+	case "unique_id":
+		id, _ := value.(object)
+		n, ok := id["number"].(float64)
+		if !ok {
+			if i, isInt := id["number"].(int); isInt {
+				n, ok = float64(i), true
+			}
+		}
+		if !ok {
+			return matchNumber(nil, cond)
+		}
+		return matchNumber(&n, cond)
+	case "checkbox":
+		checked, _ := value.(bool)
+		return matchCheckbox(checked, cond)
+	case "select", "status":
+		option, _ := value.(object)
+		name, _ := option["name"].(string)
+		return matchText(name, cond)
+	case "multi_select":
+		return matchList(names(value, "name"), cond)
+	case "people", "relation":
+		return matchList(names(value, "id"), cond)
+	case "created_by", "last_edited_by":
+		user, _ := value.(object)
+		return matchList([]string{fmt.Sprint(user["id"])}, cond)
+	case "files":
+		return matchList(names(value, "name"), cond)
+	case "date":
+		date, _ := value.(object)
+		return s.matchDate(parseTime(date["start"]), cond)
+	case "created_time", "last_edited_time":
+		return s.matchDate(parseTime(value), cond)
+	case "formula":
+		formula, _ := value.(object)
+		for k, c := range cond {
+			sub, _ := c.(object)
+			switch k {
+			case "string", "text":
+				text, _ := formula["string"].(string)
+				return matchText(text, sub)
+			case "number":
+				return s.matchProperty("number", formula["number"], sub)
+			case "checkbox":
+				return s.matchProperty("checkbox", formula["boolean"], sub)
+			case "date":
+				return s.matchProperty("date", formula["date"], sub)
+			}
+		}
+	case "rollup":
+		rollup, _ := value.(object)
+		for k, c := range cond {
+			sub, _ := c.(object)
+			switch k {
+			case "number", "date":
+				return s.matchProperty(k, rollup[k], sub)
+			case "any", "every", "none":
+				return s.matchRollupArray(k, rollup["array"], sub)
+			}
+		}
+	default:
+		return false, validationError("Filtering on %s properties is not supported.", kind)
+	}
+	return false, validationError("body failed validation: invalid %s filter condition.", kind)
+}
+
+// matchRollupArray matches the items of an array rollup against a condition,
+// as the any, every and none rollup filters do.
+func (s *Server) matchRollupArray(op string, array interface{}, cond object) (bool, error) {
+	items, _ := array.([]interface{})
+	var kind string
+	var sub object
+	for k, v := range cond {
+		kind = k
+		sub, _ = v.(object)
+	}
+	// The multi_select rollup condition is spelled multiSelect.
+	if kind == "multiSelect" {
+		kind = "multi_select"
+	}
+	matches := 0
+	for _, item := range items {
+		value, _ := item.(object)
+		match, err := s.matchProperty(kind, value[kind], sub)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			matches++
+		}
+	}
+	switch op {
+	case "any":
+		return matches > 0, nil
+	case "every":
+		return matches == len(items), nil
+	}
+	return matches == 0, nil
+}
+
+// names returns the value of the field of each object of the list.
+func names(v interface{}, field string) []string {
+	items, _ := v.([]interface{})
+	var result []string
+	for _, item := range items {
+		o, _ := item.(object)
+		result = append(result, fmt.Sprint(o[field]))
+	}
+	return result
+}
+
+func matchText(text string, cond object) (bool, error) {
+	lower := strings.ToLower(text)
+	for op, v := range cond {
+		value, _ := v.(string)
+		var match bool
+		switch op {
+		case "equals":
+			match = text == value
+		case "does_not_equal":
+			match = text != value
+		case "contains":
+			match = strings.Contains(lower, strings.ToLower(value))
+		case "does_not_contain":
+			match = !strings.Contains(lower, strings.ToLower(value))
+		case "starts_with":
+			match = strings.HasPrefix(lower, strings.ToLower(value))
+		case "ends_with":
+			match = strings.HasSuffix(lower, strings.ToLower(value))
+		case "is_empty":
+			match = (text == "") == (v == true)
+		case "is_not_empty":
+			match = (text != "") == (v == true)
+		default:
+			return false, validationError("body failed validation: unsupported text filter condition %s.", op)
+		}
+		if !match {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func matchNumber(n *float64, cond object) (bool, error) {
+	for op, v := range cond {
+		switch op {
+		case "is_empty":
+			if (n == nil) != (v == true) {
+				return false, nil
+			}
+			continue
+		case "is_not_empty":
+			if (n != nil) != (v == true) {
+				return false, nil
+			}
+			continue
+		}
+
+		value, ok := v.(float64)
+		if !ok {
+			return false, validationError("body failed validation: number filter condition %s should be a number.", op)
+		}
+		if n == nil {
+			if op != "does_not_equal" {
+				return false, nil
+			}
+			continue
+		}
+		var match bool
+		switch op {
+		case "equals":
+			match = *n == value
+		case "does_not_equal":
+			match = *n != value
+		case "greater_than":
+			match = *n > value
+		case "less_than":
+			match = *n < value
+		case "greater_than_or_equal_to":
+			match = *n >= value
+		case "less_than_or_equal_to":
+			match = *n <= value
+		default:
+			return false, validationError("body failed validation: unsupported number filter condition %s.", op)
+		}
+		if !match {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func matchCheckbox(checked bool, cond object) (bool, error) {
+	for op, v := range cond {
+		value, _ := v.(bool)
+		switch op {
+		case "equals":
+			if checked != value {
+				return false, nil
+			}
+		case "does_not_equal":
+			if checked == value {
+				return false, nil
+			}
+		default:
+			return false, validationError("body failed validation: unsupported checkbox filter condition %s.", op)
+		}
+	}
+	return true, nil
+}
+
+func matchList(values []string, cond object) (bool, error) {
+	for op, v := range cond {
+		var match bool
+		switch op {
+		case "contains", "does_not_contain":
+			value, _ := v.(string)
+			found := false
+			for _, item := range values {
+				found = found || item == value || key(item) == key(value)
+			}
+			match = found == (op == "contains")
+		case "is_empty":
+			match = (len(values) == 0) == (v == true)
+		case "is_not_empty":
+			match = (len(values) > 0) == (v == true)
+		default:
+			return false, validationError("body failed validation: unsupported filter condition %s.", op)
+		}
+		if !match {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// relativeDates are the date filter conditions relative to the current time,
+// with the bounds of the matching period.
+var relativeDates = map[string]func(now time.Time) (time.Time, time.Time){
+	"past_week":  func(now time.Time) (time.Time, time.Time) { return now.AddDate(0, 0, -7), now },
+	"past_month": func(now time.Time) (time.Time, time.Time) { return now.AddDate(0, -1, 0), now },
+	"past_year":  func(now time.Time) (time.Time, time.Time) { return now.AddDate(-1, 0, 0), now },
+	"next_week":  func(now time.Time) (time.Time, time.Time) { return now, now.AddDate(0, 0, 7) },
+	"next_month": func(now time.Time) (time.Time, time.Time) { return now, now.AddDate(0, 1, 0) },
+	"next_year":  func(now time.Time) (time.Time, time.Time) { return now, now.AddDate(1, 0, 0) },
+}
+
+func (s *Server) matchDate(t *time.Time, cond object) (bool, error) {
+	for op, v := range cond {
+		switch op {
+		case "is_empty":
+			if (t == nil) != (v == true) {
+				return false, nil
+			}
+			continue
+		case "is_not_empty":
+			if (t != nil) != (v == true) {
+				return false, nil
+			}
+			continue
+		}
+		if t == nil {
+			return false, nil
+		}
+
+		if period, ok := relativeDates[op]; ok {
+			start, end := period(s.now())
+			if t.Before(start) || t.After(end) {
+				return false, nil
+			}
+			continue
+		}
+
+		value := parseTime(v)
+		if value == nil {
+			return false, validationError("body failed validation: date filter condition %s should be a date, instead was `%v`.", op, v)
+		}
+		// Dates without a time are compared to the day of the value.
+		a, b := *t, *value
+		if str, _ := v.(string); len(str) == len("2006-01-02") {
+			a = time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+		}
+		var match bool
+		switch op {
+		case "equals":
+			match = a.Equal(b)
+		case "before":
+			match = a.Before(b)
+		case "after":
+			match = a.After(b)
+		case "on_or_before":
+			match = !a.After(b)
+		case "on_or_after":
+			match = !a.Before(b)
+		default:
+			return false, validationError("body failed validation: unsupported date filter condition %s.", op)
+		}
+		if !match {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// parseTime parses a date or a date time as formatted by the API. It returns
+// nil if the value is not a date.
+func parseTime(v interface{}) *time.Time {
+	str, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+		if t, err := time.Parse(layout, str); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// sortPages sorts the pages of a database query by the given sort criteria.
+// Empty values always come last, as in Notion.
+func (s *Server) sortPages(db object, pages []object, sorts []interface{}) error {
+	type criterion struct {
+		property  string
+		kind      string
+		timestamp string
+		desc      bool
+	}
+	var criteria []criterion
+	for _, v := range sorts {
+		so, _ := v.(object)
+		c := criterion{desc: so["direction"] == "descending"}
+		if ts, ok := so["timestamp"].(string); ok && ts != "" {
+			c.timestamp = ts
+		} else {
+			nameOrID, _ := so["property"].(string)
+			name, config := findProperty(db["properties"].(object), nameOrID)
+			if config == nil {
+				return validationError("Could not find sort property with name or id: %s", nameOrID)
+			}
+			c.property, c.kind = name, config["type"].(string)
+		}
+		criteria = append(criteria, c)
+	}
+
+	value := func(page object, c criterion) interface{} {
+		if c.timestamp != "" {
+			return sortValue(c.timestamp, page[c.timestamp])
+		}
+		prop, _ := page["properties"].(object)[c.property].(object)
+		return sortValue(c.kind, prop[c.kind])
+	}
+	sort.SliceStable(pages, func(i, j int) bool {
+		for _, c := range criteria {
+			a, b := value(pages[i], c), value(pages[j], c)
+			switch {
+			case a == nil && b == nil:
+				continue
+			case a == nil:
+				return false
+			case b == nil:
+				return true
+			}
+			cmp := compare(a, b)
+			if cmp == 0 {
+				continue
+			}
+			return (cmp < 0) != c.desc
+		}
+		return false
+	})
+	return nil
+}
+
+// sortValue returns a comparable value of a property, or nil if it is empty.
+func sortValue(kind string, v interface{}) interface{} {
+	var result interface{}
+	switch kind {
+	case "title", "rich_text":
+		result = strings.ToLower(plainText(v))
+	case "select", "status":
+		option, _ := v.(object)
+		result, _ = option["name"].(string)
+	case "multi_select", "people":
+		result = strings.Join(names(v, "name"), ",")
+	case "date":
+		date, _ := v.(object)
+		if t := parseTime(date["start"]); t != nil {
+			result = *t
+		}
+	case "created_time", "last_edited_time":
+		if t := parseTime(v); t != nil {
+			result = *t
+		}
+	case "unique_id":
+		id, _ := v.(object)
+		result = id["number"]
+	default:
+		result = v
+	}
+	if result == "" {
+		return nil
+	}
+	return result
+}
+
+func compare(a, b interface{}) int {
+	switch a := a.(type) {
+	case string:
+		return strings.Compare(a, fmt.Sprint(b))
+	case float64:
+		b, _ := b.(float64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	case int:
+		b, _ := b.(int)
+		return a - b
+	case bool:
+		b, _ := b.(bool)
+		switch {
+		case !a && b:
+			return -1
+		case a && !b:
+			return 1
+		}
+	case time.Time:
+		b, _ := b.(time.Time)
+		switch {
+		case a.Before(b):
+			return -1
+		case a.After(b):
+			return 1
+		}
+	}
+	return 0
+}
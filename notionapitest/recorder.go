@@ -0,0 +1,380 @@
+package notionapitest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/jomei/notionapi"
+)
+
+// Mode is the mode of a Recorder.
+type Mode int
+
+const (
+	// ModeReplay answers requests with the responses of the cassette,
+	// without network access.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the API and records them in the
+	// cassette, replacing its content.
+	ModeRecord
+	// ModeReplayOrRecord replays the cassette if it exists and records it
+	// otherwise.
+	ModeReplayOrRecord
+)
+
+// redacted replaces the values of redacted headers in cassettes.
+const redacted = "REDACTED"
+
+// RecorderOption configures a Recorder.
+type RecorderOption func(*Recorder)
+
+// WithTransport sets the transport sending requests in record mode. It
+// defaults to http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) RecorderOption {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// WithRedactedHeaders adds headers whose values are not written to the
+// cassette, in addition to Authorization.
+func WithRedactedHeaders(names ...string) RecorderOption {
+	return func(r *Recorder) {
+		for _, name := range names {
+			r.redacted = append(r.redacted, http.CanonicalHeaderKey(name))
+		}
+	}
+}
+
+// Recorder is an http.RoundTripper recording requests to the Notion API and
+// their responses in a cassette file, and replaying them in later runs, so
+// that tests can use realistic responses without network access:
+//
+//	rec, err := notionapitest.NewRecorder("testdata/query.jsonl", notionapitest.ModeReplayOrRecord)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer rec.Save()
+//	client := notionapi.NewClient(token, rec.Option())
+//
+// Cassettes are JSON Lines files with one request and its response per line.
+// The Authorization header is redacted. In replay mode, requests are matched
+// on their method, path, query and JSON body, ignoring the order of object
+// keys and whitespace, or multipart body, ignoring its boundary. Bodies that
+// are not UTF-8 are stored base64-encoded. Every recorded interaction is replayed once, in order,
+// so that the same request can get different responses.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	redacted  []string
+
+	mu           sync.Mutex
+	interactions []*interaction
+	replayed     []bool
+}
+
+// interaction is a line of a cassette.
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Query  string          `json:"query,omitempty"`
+	Header http.Header     `json:"header,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	Status int             `json:"status"`
+	Header http.Header     `json:"header,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// NewRecorder returns a Recorder for the cassette at path. In replay mode the
+// cassette is read immediately.
+func NewRecorder(path string, mode Mode, opts ...RecorderOption) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		redacted:  []string{"Authorization"},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.mode == ModeReplayOrRecord {
+		r.mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		}
+	}
+	if r.mode == ModeReplay {
+		if err := r.load(); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *Recorder) load() error {
+	f, err := os.Open(r.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 64<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var i interaction
+		if err := json.Unmarshal(scanner.Bytes(), &i); err != nil {
+			return fmt.Errorf("%s:%d: %w", r.path, line, err)
+		}
+		r.interactions = append(r.interactions, &i)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	r.replayed = make([]bool, len(r.interactions))
+	return nil
+}
+
+// Mode returns whether the recorder replays or records requests.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// HTTPClient returns an http.Client using the recorder as its transport.
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Option returns the option making a client send its requests through the
+// recorder.
+func (r *Recorder) Option() notionapi.ClientOption {
+	return notionapi.WithHTTPClient(r.HTTPClient())
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	header := r.redact(req.Header)
+	recorded := recordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query().Encode(),
+		Header: header,
+		Body:   encodeBody(replaceBoundary(header, body)),
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	res, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, &interaction{
+		Request: recorded,
+		Response: recordedResponse{
+			Status: res.StatusCode,
+			Header: r.redact(res.Header),
+			Body:   encodeBody(data),
+		},
+	})
+	r.mu.Unlock()
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded recordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for n, i := range r.interactions {
+		if r.replayed[n] || !matchRequest(i.Request, recorded) {
+			continue
+		}
+		r.replayed[n] = true
+		body := decodeBody(i.Response.Body)
+		header := i.Response.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", i.Response.Status, http.StatusText(i.Response.Status)),
+			StatusCode:    i.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("notionapitest: no recorded response in %s for %s %s", r.path, req.Method, req.URL.RequestURI())
+}
+
+// Save writes the recorded interactions to the cassette. It does nothing in
+// replay mode.
+func (r *Recorder) Save() error {
+	if r.mode == ModeReplay {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var buf bytes.Buffer
+	for _, i := range r.interactions {
+		line, err := json.Marshal(i)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	if dir := filepath.Dir(r.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return ioutil.WriteFile(r.path, buf.Bytes(), 0o644)
+}
+
+// redact returns a copy of the header with the values of redacted headers
+// replaced.
+func (r *Recorder) redact(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range r.redacted {
+		if _, ok := header[name]; ok {
+			header[name] = []string{redacted}
+		}
+	}
+	return header
+}
+
+// recordedBoundary replaces the random boundary of multipart requests in
+// cassettes.
+const recordedBoundary = "notionapitest-boundary"
+
+// replaceBoundary replaces the boundary of a multipart body and of its
+// Content-Type header with recordedBoundary, so that requests sending the
+// same parts match.
+func replaceBoundary(header http.Header, body []byte) []byte {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return body
+	}
+	boundary := params["boundary"]
+	params["boundary"] = recordedBoundary
+	header.Set("Content-Type", mime.FormatMediaType(mediaType, params))
+	return bytes.ReplaceAll(body, []byte(boundary), []byte(recordedBoundary))
+}
+
+// base64Prefix marks the bodies stored base64-encoded in cassettes.
+const base64Prefix = "base64:"
+
+// encodeBody returns a body as it is stored in cassettes: JSON bodies as is,
+// UTF-8 bodies as JSON strings, and other bodies as JSON strings of their
+// base64 encoding prefixed with base64Prefix.
+func encodeBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	if json.Valid(body) && json.Compact(&buf, body) == nil && !strings.HasPrefix(buf.String(), `"`) {
+		return buf.Bytes()
+	}
+	text := string(body)
+	if !utf8.Valid(body) || strings.HasPrefix(text, base64Prefix) {
+		text = base64Prefix + base64.StdEncoding.EncodeToString(body)
+	}
+	s, _ := json.Marshal(text)
+	return s
+}
+
+func decodeBody(body json.RawMessage) []byte {
+	var s string
+	if json.Unmarshal(body, &s) != nil {
+		return body
+	}
+	if strings.HasPrefix(s, base64Prefix) {
+		if data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, base64Prefix)); err == nil {
+			return data
+		}
+	}
+	return []byte(s)
+}
+
+// matchRequest reports whether a request matches a recorded one.
+func matchRequest(recorded, req recordedRequest) bool {
+	if recorded.Method != req.Method || recorded.Path != req.Path || normalizeQuery(recorded.Query) != normalizeQuery(req.Query) {
+		return false
+	}
+	return normalizeBody(recorded.Body) == normalizeBody(req.Body)
+}
+
+func normalizeQuery(query string) string {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return query
+	}
+	return values.Encode()
+}
+
+// normalizeBody returns a JSON body with sorted object keys and without
+// whitespace.
+func normalizeBody(body json.RawMessage) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+	normalized, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+	return string(normalized)
+}
@@ -0,0 +1,182 @@
+package notionapitest_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jomei/notionapi"
+	"github.com/jomei/notionapi/notionapitest"
+)
+
+func TestRecorder(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "testdata", "tasks.jsonl")
+	ctx := context.Background()
+
+	// run creates a database with a task and queries it, returning the titles
+	// of the tasks.
+	run := func(client *notionapi.Client, root notionapi.PageID) []string {
+		db := newTasksDatabase(t, client, root)
+		createTask(t, client, db, "Record", "Todo", 1)
+		res, err := client.Database.Query(ctx, notionapi.DatabaseID(db.ID), &notionapi.DatabaseQueryRequest{
+			Filter: notionapi.PropertyFilter{Property: "Status", Select: &notionapi.SelectFilterCondition{Equals: "Todo"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return titles(res.Results)
+	}
+
+	srv := notionapitest.NewServer(notionapitest.WithToken("secret_recorded"))
+	rec, err := notionapitest.NewRecorder(cassette, notionapitest.ModeReplayOrRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != notionapitest.ModeRecord {
+		t.Fatalf("Mode() = %v without a cassette, want ModeRecord", rec.Mode())
+	}
+	recorded := run(srv.Client(rec.Option()), srv.RootPageID())
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+	baseURL, root := srv.URL, srv.RootPageID()
+	srv.Close()
+
+	data, err := ioutil.ReadFile(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret_recorded") {
+		t.Error("the cassette contains the token")
+	}
+	if got := strings.Count(string(data), "\n"); got != 3 {
+		t.Errorf("the cassette has %d lines, want 3", got)
+	}
+
+	rec, err = notionapitest.NewRecorder(cassette, notionapitest.ModeReplayOrRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != notionapitest.ModeReplay {
+		t.Fatalf("Mode() = %v with a cassette, want ModeReplay", rec.Mode())
+	}
+	client := notionapi.NewClient("other_token", notionapi.WithBaseURL(baseURL), rec.Option())
+	if replayed := run(client, root); !equal(replayed, recorded) {
+		t.Errorf("replayed %v, want %v", replayed, recorded)
+	}
+
+	t.Run("fails for requests that were not recorded", func(t *testing.T) {
+		if _, err := client.User.Me(ctx); err == nil {
+			t.Error("Me() error = nil, want an error")
+		}
+	})
+
+	t.Run("matches JSON bodies regardless of key order", func(t *testing.T) {
+		send := func(rec *notionapitest.Recorder, body string) *http.Response {
+			req, err := http.NewRequest(http.MethodPost, "http://notion.test/v1/search?b=2&a=1", strings.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := rec.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return res
+		}
+
+		path := filepath.Join(t.TempDir(), "search.jsonl")
+		rec, err := notionapitest.NewRecorder(path, notionapitest.ModeRecord,
+			notionapitest.WithTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       ioutil.NopCloser(strings.NewReader(`{"object":"list"}`)),
+				}, nil
+			})))
+		if err != nil {
+			t.Fatal(err)
+		}
+		send(rec, `{"query":"tasks","page_size":10}`)
+		if err := rec.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		rec, err = notionapitest.NewRecorder(path, notionapitest.ModeReplay)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res := send(rec, `{ "page_size": 10, "query": "tasks" }`)
+		body, _ := ioutil.ReadAll(res.Body)
+		if res.StatusCode != http.StatusOK || string(body) != `{"object":"list"}` {
+			t.Errorf("RoundTrip() got %d %s", res.StatusCode, body)
+		}
+	})
+}
+
+func TestRecorder_Multipart(t *testing.T) {
+	file := []byte{0x89, 'P', 'N', 'G', 0xff, 0xfe, 0x00}
+	send := func(rec *notionapitest.Recorder) []byte {
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		part, err := w.CreateFormFile("file", "image.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write(file)
+		w.Close()
+		req, err := http.NewRequest(http.MethodPost, "http://notion.test/v1/file_uploads/upload_id/send", &body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		res, err := rec.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := ioutil.ReadAll(res.Body)
+		return data
+	}
+
+	path := filepath.Join(t.TempDir(), "upload.jsonl")
+	rec, err := notionapitest.NewRecorder(path, notionapitest.ModeRecord,
+		notionapitest.WithTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			// The response echoes the file.
+			r, err := req.MultipartReader()
+			if err != nil {
+				return nil, err
+			}
+			part, err := r.NextPart()
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: part}, nil
+		})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := send(rec); !bytes.Equal(got, file) {
+		t.Fatalf("recorded RoundTrip() = %q, want %q", got, file)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err = notionapitest.NewRecorder(path, notionapitest.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := send(rec); !bytes.Equal(got, file) {
+		t.Errorf("replayed RoundTrip() = %q, want %q", got, file)
+	}
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,101 @@
+package notionapitest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// normalizeRichText fills in the fields of rich text objects that the API
+// computes, such as plain_text, href and annotations.
+func normalizeRichText(v interface{}) []interface{} {
+	items, _ := v.([]interface{})
+	result := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		in, ok := item.(object)
+		if !ok {
+			continue
+		}
+		rt := object{}
+		for k, v := range in {
+			rt[k] = v
+		}
+
+		kind, _ := rt["type"].(string)
+		if kind == "" {
+			for _, t := range []string{"text", "mention", "equation"} {
+				if _, ok := rt[t]; ok {
+					kind = t
+				}
+			}
+			rt["type"] = kind
+		}
+
+		href := rt["href"]
+		plain, _ := rt["plain_text"].(string)
+		switch kind {
+		case "text":
+			text, _ := rt["text"].(object)
+			plain, _ = text["content"].(string)
+			if link, ok := text["link"].(object); ok {
+				href = link["url"]
+			} else {
+				text["link"] = nil
+			}
+		case "equation":
+			equation, _ := rt["equation"].(object)
+			plain, _ = equation["expression"].(string)
+		case "mention":
+			if plain == "" {
+				plain = mentionText(rt["mention"])
+			}
+		}
+		rt["plain_text"] = plain
+		rt["href"] = href
+
+		annotations := object{
+			"bold":          false,
+			"italic":        false,
+			"strikethrough": false,
+			"underline":     false,
+			"code":          false,
+			"color":         "default",
+		}
+		if a, ok := rt["annotations"].(object); ok {
+			for k, v := range a {
+				annotations[k] = v
+			}
+		}
+		rt["annotations"] = annotations
+		result = append(result, rt)
+	}
+	return result
+}
+
+func mentionText(v interface{}) string {
+	mention, _ := v.(object)
+	switch mention["type"] {
+	case "user":
+		user, _ := mention["user"].(object)
+		if name, ok := user["name"].(string); ok {
+			return "@" + name
+		}
+		return "@Anonymous"
+	case "date":
+		date, _ := mention["date"].(object)
+		return fmt.Sprint(date["start"])
+	}
+	return "Untitled"
+}
+
+// plainText returns the concatenated plain text of rich text.
+func plainText(v interface{}) string {
+	items, _ := v.([]interface{})
+	var sb strings.Builder
+	for _, item := range items {
+		rt, _ := item.(object)
+		if text, ok := rt["plain_text"].(string); ok {
+			sb.WriteString(text)
+		}
+	}
+	return sb.String()
+}
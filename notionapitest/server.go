@@ -0,0 +1,382 @@
+// Package notionapitest provides an in-memory fake of the Notion API for
+// testing code that uses the notionapi package.
+//
+// The fake implements pages, databases, blocks, comments, users and search,
+// including database query filters and sorts and cursor pagination, so that
+// stateful flows such as creating a page and then querying its database can be
+// tested without network access:
+//
+//	srv := notionapitest.NewServer()
+//	defer srv.Close()
+//
+//	client := srv.Client()
+//	db, err := client.Database.Create(ctx, &notionapi.DatabaseCreateRequest{
+//		Parent: notionapi.Parent{Type: notionapi.ParentTypePageID, PageID: srv.RootPageID()},
+//		...
+//	})
+//
+// The fake aims to behave like the real API for the common cases but does not
+// compute formulas or rollups.
+package notionapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jomei/notionapi"
+)
+
+// object is the JSON representation of a Notion object, as stored by the
+// fake.
+type object = map[string]interface{}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithClock sets the function used to timestamp created and edited objects.
+// It defaults to time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// WithToken makes the server reject requests that are not authorized with the
+// given token. By default any bearer token is accepted.
+func WithToken(token notionapi.Token) Option {
+	return func(s *Server) {
+		s.token = token
+	}
+}
+
+// WithUsers adds users to the workspace, in addition to the bot user of the
+// integration.
+func WithUsers(users ...notionapi.User) Option {
+	return func(s *Server) {
+		for _, u := range users {
+			o := toObject(u)
+			o["object"] = notionapi.ObjectTypeUser.String()
+			s.users = append(s.users, o)
+		}
+	}
+}
+
+// Server is an in-memory fake of the Notion API served over HTTP. All of its
+// methods are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	now   func() time.Time
+	token notionapi.Token
+	seq   int
+
+	bot   object
+	users []object
+	root  string
+
+	// pages, databases and blocks are indexed by their ID without dashes.
+	pages     map[string]object
+	databases map[string]object
+	blocks    map[string]object
+	// children holds the IDs of the child blocks of pages and blocks, in
+	// order.
+	children map[string][]string
+	// order holds the IDs of pages and databases in creation order.
+	order    []string
+	comments []object
+}
+
+// NewServer starts and returns a new fake server. The workspace contains a
+// single root page, whose ID is returned by RootPageID, under which pages and
+// databases can be created. The caller should call Close when finished.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		now:       time.Now,
+		pages:     make(map[string]object),
+		databases: make(map[string]object),
+		blocks:    make(map[string]object),
+		children:  make(map[string][]string),
+	}
+	s.bot = object{
+		"object": notionapi.ObjectTypeUser.String(),
+		"id":     s.newID(),
+		"type":   "bot",
+		"name":   "Test Integration",
+		"bot": object{
+			"owner":          object{"type": "workspace", "workspace": true},
+			"workspace_name": "Test Workspace",
+		},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	root := s.newPage(object{"type": "workspace", "workspace": true})
+	root["properties"] = object{
+		"title": object{"id": "title", "type": "title", "title": normalizeRichText([]interface{}{
+			object{"type": "text", "text": object{"content": "Root"}},
+		})},
+	}
+	s.insertPage(root)
+	s.root = root["id"].(string)
+
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Client returns a client for the server. The options are applied after the
+// ones pointing the client to the server.
+func (s *Server) Client(opts ...notionapi.ClientOption) *notionapi.Client {
+	token := s.token
+	if token == "" {
+		token = "secret_test"
+	}
+	opts = append([]notionapi.ClientOption{notionapi.WithBaseURL(s.URL)}, opts...)
+	return notionapi.NewClient(token, opts...)
+}
+
+// RootPageID returns the ID of the page at the top of the workspace.
+func (s *Server) RootPageID() notionapi.PageID {
+	return notionapi.PageID(s.root)
+}
+
+// BotUserID returns the ID of the bot user of the integration, which is the
+// author of every object created through the API.
+func (s *Server) BotUserID() notionapi.UserID {
+	return notionapi.UserID(s.bot["id"].(string))
+}
+
+// apiError is an error response of the API.
+type apiError struct {
+	status  int
+	code    notionapi.ErrorCode
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func validationError(format string, args ...interface{}) error {
+	return &apiError{status: http.StatusBadRequest, code: "validation_error", message: fmt.Sprintf(format, args...)}
+}
+
+func notFoundError(kind, id string) error {
+	return &apiError{
+		status:  http.StatusNotFound,
+		code:    "object_not_found",
+		message: fmt.Sprintf("Could not find %s with ID: %s. Make sure the relevant pages and databases are shared with your integration.", kind, id),
+	}
+}
+
+// route is a request handler. ids holds the path segments following the
+// resource name, such as the ID of a page.
+type route func(s *Server, r *http.Request, body object, ids []string) (interface{}, error)
+
+var routes = map[string]route{
+	"POST pages":               (*Server).createPage,
+	"GET pages/*":              (*Server).getPage,
+	"PATCH pages/*":            (*Server).updatePage,
+	"GET pages/*/properties/*": (*Server).getPageProperty,
+	"POST databases":           (*Server).createDatabase,
+	"GET databases/*":          (*Server).getDatabase,
+	"PATCH databases/*":        (*Server).updateDatabase,
+	"POST databases/*/query":   (*Server).queryDatabase,
+	"GET blocks/*":             (*Server).getBlock,
+	"PATCH blocks/*":           (*Server).updateBlock,
+	"DELETE blocks/*":          (*Server).deleteBlock,
+	"GET blocks/*/children":    (*Server).getBlockChildren,
+	"PATCH blocks/*/children":  (*Server).appendBlockChildren,
+	"GET comments":             (*Server).getComments,
+	"POST comments":            (*Server).createComment,
+	"GET users":                (*Server).listUsers,
+	"GET users/me":             (*Server).me,
+	"GET users/*":              (*Server).getUser,
+	"POST search":              (*Server).search,
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := s.serve(r)
+	if err != nil {
+		e, ok := err.(*apiError)
+		if !ok {
+			e = &apiError{status: http.StatusInternalServerError, code: "internal_server_error", message: err.Error()}
+		}
+		writeJSON(w, e.status, object{
+			"object":  notionapi.ObjectTypeError.String(),
+			"status":  e.status,
+			"code":    e.code,
+			"message": e.message,
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) serve(r *http.Request) (interface{}, error) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") || (s.token != "" && auth != "Bearer "+s.token.String()) {
+		return nil, &apiError{status: http.StatusUnauthorized, code: "unauthorized", message: "API token is invalid."}
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) < 2 || segments[0] != "v1" {
+		return nil, invalidURLError(r)
+	}
+	segments = segments[1:]
+
+	// Every other segment is an ID, which is replaced by a wildcard to find
+	// the route.
+	var ids []string
+	pattern := make([]string, len(segments))
+	for i, segment := range segments {
+		if i%2 == 1 && segment != "me" {
+			ids = append(ids, segment)
+			segment = "*"
+		}
+		pattern[i] = segment
+	}
+	handler, ok := routes[r.Method+" "+strings.Join(pattern, "/")]
+	if !ok {
+		return nil, invalidURLError(r)
+	}
+
+	var body object
+	if r.Method == http.MethodPost || r.Method == http.MethodPatch {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return nil, &apiError{status: http.StatusBadRequest, code: "invalid_json", message: "Error parsing JSON body."}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return handler(s, r, body, ids)
+}
+
+func invalidURLError(r *http.Request) error {
+	return &apiError{
+		status:  http.StatusBadRequest,
+		code:    "invalid_request_url",
+		message: fmt.Sprintf("Invalid request URL: %s %s", r.Method, r.URL.Path),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// newID returns a new UUID. IDs are sequential to make tests deterministic.
+func (s *Server) newID() string {
+	s.seq++
+	return fmt.Sprintf("%08x-0000-4000-8000-%012x", s.seq, s.seq)
+}
+
+// key returns the key used to index objects from an ID, with or without
+// dashes.
+func key(id string) string {
+	return strings.ReplaceAll(id, "-", "")
+}
+
+// timestamp returns the current time formatted as in API responses.
+func (s *Server) timestamp() string {
+	return s.now().UTC().Format("2006-01-02T15:04:05.000Z07:00")
+}
+
+// partialUser returns the user as referenced in created_by and similar
+// fields.
+func partialUser(u object) object {
+	return object{"object": notionapi.ObjectTypeUser.String(), "id": u["id"]}
+}
+
+// toObject converts a value to its JSON object representation.
+func toObject(v interface{}) object {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	var o object
+	if err := json.Unmarshal(data, &o); err != nil {
+		panic(err)
+	}
+	return o
+}
+
+// list is a page of results of a paginated endpoint.
+func list(results []object, next string, hasMore bool, kind string) object {
+	res := object{
+		"object":      notionapi.ObjectTypeList.String(),
+		"results":     results,
+		"next_cursor": nil,
+		"has_more":    hasMore,
+		"type":        kind,
+		kind:          object{},
+	}
+	if results == nil {
+		res["results"] = []object{}
+	}
+	if next != "" {
+		res["next_cursor"] = next
+	}
+	return res
+}
+
+// paginate returns the page of items starting at the cursor. Cursors are the
+// IDs of the first item of the next page.
+func paginate(items []object, cursor string, pageSize int) ([]object, string, bool, error) {
+	if pageSize < 0 || pageSize > 100 {
+		return nil, "", false, validationError("body failed validation: body.page_size should be ≤ `100`, instead was `%d`.", pageSize)
+	}
+	if pageSize == 0 {
+		pageSize = 100
+	}
+
+	start := 0
+	if cursor != "" {
+		start = -1
+		for i, item := range items {
+			if key(fmt.Sprint(item["id"])) == key(cursor) {
+				start = i
+				break
+			}
+		}
+		if start < 0 {
+			return nil, "", false, validationError("The start_cursor provided is invalid: %s", cursor)
+		}
+	}
+
+	end := start + pageSize
+	if end >= len(items) {
+		return items[start:], "", false, nil
+	}
+	return items[start:end], fmt.Sprint(items[end]["id"]), true, nil
+}
+
+// pagination returns the cursor and page size of a request, from its body or
+// its query parameters.
+func pagination(r *http.Request, body object) (string, int, error) {
+	if body != nil {
+		cursor, _ := body["start_cursor"].(string)
+		size, _ := body["page_size"].(float64)
+		return cursor, int(size), nil
+	}
+
+	q := r.URL.Query()
+	var size int
+	if v := q.Get("page_size"); v != "" {
+		var err error
+		if size, err = strconv.Atoi(v); err != nil {
+			return "", 0, validationError("page_size should be a number, instead was `%s`.", v)
+		}
+	}
+	return q.Get("start_cursor"), size, nil
+}
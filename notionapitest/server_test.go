@@ -0,0 +1,307 @@
+package notionapitest_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/jomei/notionapi/notionapitest"
+)
+
+func richText(content string) []notionapi.RichText {
+	return []notionapi.RichText{{Type: notionapi.ObjectTypeText, Text: &notionapi.Text{Content: content}}}
+}
+
+// newTasksDatabase creates a database of tasks with a title, a status select
+// and a number of points.
+func newTasksDatabase(t *testing.T, client *notionapi.Client, parent notionapi.PageID) *notionapi.Database {
+	t.Helper()
+	db, err := client.Database.Create(context.Background(), &notionapi.DatabaseCreateRequest{
+		Parent: notionapi.Parent{Type: notionapi.ParentTypePageID, PageID: parent},
+		Title:  richText("Tasks"),
+		Properties: notionapi.PropertyConfigs{
+			"Name": notionapi.TitlePropertyConfig{Type: notionapi.PropertyConfigTypeTitle},
+			"Status": notionapi.SelectPropertyConfig{
+				Type:   notionapi.PropertyConfigTypeSelect,
+				Select: notionapi.Select{Options: []notionapi.Option{{Name: "Todo"}, {Name: "Done"}}},
+			},
+			"Points": notionapi.NumberPropertyConfig{Type: notionapi.PropertyConfigTypeNumber},
+			"Due":    notionapi.DatePropertyConfig{Type: notionapi.PropertyConfigTypeDate},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func createTask(t *testing.T, client *notionapi.Client, db *notionapi.Database, name, status string, points float64) *notionapi.Page {
+	t.Helper()
+	page, err := client.Page.Create(context.Background(), &notionapi.PageCreateRequest{
+		Parent: notionapi.Parent{Type: notionapi.ParentTypeDatabaseID, DatabaseID: notionapi.DatabaseID(db.ID)},
+		Properties: notionapi.Properties{
+			"Name":   notionapi.TitleProperty{Title: richText(name)},
+			"Status": notionapi.SelectProperty{Select: notionapi.Option{Name: status}},
+			"Points": notionapi.NumberProperty{Number: points},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return page
+}
+
+func titles(pages []notionapi.Page) []string {
+	var result []string
+	for _, p := range pages {
+		result = append(result, p.Properties["Name"].(*notionapi.TitleProperty).Title[0].PlainText)
+	}
+	return result
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestServer_Database(t *testing.T) {
+	srv := notionapitest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	db := newTasksDatabase(t, client, srv.RootPageID())
+	createTask(t, client, db, "Write tests", "Todo", 3)
+	createTask(t, client, db, "Fix bug", "Done", 5)
+	createTask(t, client, db, "Release", "Todo", 1)
+	createTask(t, client, db, "Document", "Todo", 8)
+
+	got, err := client.Database.Get(ctx, notionapi.DatabaseID(db.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Properties["Status"].GetType() != notionapi.PropertyConfigTypeSelect {
+		t.Errorf("Get() status property = %+v", got.Properties["Status"])
+	}
+
+	points := 2.0
+	res, err := client.Database.Query(ctx, notionapi.DatabaseID(db.ID), &notionapi.DatabaseQueryRequest{
+		Filter: notionapi.AndCompoundFilter{
+			notionapi.PropertyFilter{Property: "Status", Select: &notionapi.SelectFilterCondition{Equals: "Todo"}},
+			notionapi.PropertyFilter{Property: "Points", Number: &notionapi.NumberFilterCondition{GreaterThan: &points}},
+		},
+		Sorts: []notionapi.SortObject{{Property: "Points", Direction: "descending"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Document", "Write tests"}; !equal(titles(res.Results), want) {
+		t.Errorf("Query() got %v, want %v", titles(res.Results), want)
+	}
+
+	t.Run("paginates", func(t *testing.T) {
+		pages, err := client.Database.QueryAll(ctx, notionapi.DatabaseID(db.ID), &notionapi.DatabaseQueryRequest{
+			Sorts: []notionapi.SortObject{{Property: "Name", Direction: "ascending"}},
+		}, notionapi.WithPageSize(3)).All()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"Document", "Fix bug", "Release", "Write tests"}; !equal(titles(pages), want) {
+			t.Errorf("QueryAll() got %v, want %v", titles(pages), want)
+		}
+	})
+
+	t.Run("updates and archives pages", func(t *testing.T) {
+		page := createTask(t, client, db, "Temporary", "Todo", 0)
+		if _, err := client.Page.Update(ctx, notionapi.PageID(page.ID), &notionapi.PageUpdateRequest{
+			Properties: notionapi.Properties{"Status": notionapi.SelectProperty{Select: notionapi.Option{Name: "Done"}}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		res, err := client.Database.Query(ctx, notionapi.DatabaseID(db.ID), &notionapi.DatabaseQueryRequest{
+			Filter: notionapi.PropertyFilter{Property: "Status", Select: &notionapi.SelectFilterCondition{Equals: "Done"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"Fix bug", "Temporary"}; !equal(titles(res.Results), want) {
+			t.Errorf("Query() got %v, want %v", titles(res.Results), want)
+		}
+
+		if _, err := client.Page.Update(ctx, notionapi.PageID(page.ID), &notionapi.PageUpdateRequest{Archived: true}); err != nil {
+			t.Fatal(err)
+		}
+		all, err := client.Database.QueryAll(ctx, notionapi.DatabaseID(db.ID), nil).All()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(all) != 4 {
+			t.Errorf("QueryAll() got %d pages after archiving, want 4", len(all))
+		}
+	})
+
+	t.Run("validates properties", func(t *testing.T) {
+		_, err := client.Database.Query(ctx, notionapi.DatabaseID(db.ID), &notionapi.DatabaseQueryRequest{
+			Filter: notionapi.PropertyFilter{Property: "Missing", Checkbox: &notionapi.CheckboxFilterCondition{Equals: true}},
+		})
+		var apiErr *notionapi.Error
+		if !errors.As(err, &apiErr) || apiErr.Code != "validation_error" {
+			t.Errorf("Query() error = %v, want a validation error", err)
+		}
+	})
+}
+
+func TestServer_Blocks(t *testing.T) {
+	srv := notionapitest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	page, err := client.Page.Create(ctx, &notionapi.PageCreateRequest{
+		Parent:     notionapi.Parent{Type: notionapi.ParentTypePageID, PageID: srv.RootPageID()},
+		Properties: notionapi.Properties{"title": notionapi.TitleProperty{Title: richText("Notes")}},
+		Children:   notionapi.MarkdownToBlocks("# Notes\n\n- one\n  - nested\n- two\n"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := client.Block.AppendChildren(ctx, notionapi.BlockID(page.ID), &notionapi.AppendBlockChildrenRequest{
+		Children: notionapi.MarkdownToBlocks("Last paragraph"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Results) != 1 {
+		t.Fatalf("AppendChildren() got %d results, want 1", len(res.Results))
+	}
+
+	tree, err := client.Block.GetTree(ctx, notionapi.BlockID(page.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Notes\n\n- one\n  - nested\n- two\n\nLast paragraph\n"
+	if got := notionapi.BlocksToMarkdown(tree); got != want {
+		t.Errorf("GetTree() rendered as %q, want %q", got, want)
+	}
+
+	if _, err := client.Block.Delete(ctx, res.Results[0].GetID()); err != nil {
+		t.Fatal(err)
+	}
+	children, err := client.Block.GetAllChildren(ctx, notionapi.BlockID(page.ID)).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(children) != 3 {
+		t.Errorf("GetAllChildren() got %d blocks after deletion, want 3", len(children))
+	}
+
+	root, err := client.Block.GetAllChildren(ctx, notionapi.BlockID(srv.RootPageID())).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(root) != 1 || root[0].GetType() != notionapi.BlockTypeChildPage || root[0].GetID() != notionapi.BlockID(page.ID) {
+		t.Errorf("GetAllChildren() of the root page = %+v, want the child page", root)
+	}
+}
+
+func TestServer_CommentsUsersAndSearch(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	srv := notionapitest.NewServer(
+		notionapitest.WithClock(func() time.Time {
+			now = now.Add(time.Minute)
+			return now
+		}),
+		notionapitest.WithUsers(notionapi.User{ID: "user-1", Type: notionapi.UserTypePerson, Name: "Ada"}),
+		notionapitest.WithToken("secret_token"),
+	)
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	db := newTasksDatabase(t, client, srv.RootPageID())
+	page := createTask(t, client, db, "Plan", "Todo", 1)
+
+	comment, err := client.Comment.Create(ctx, &notionapi.CommentCreateRequest{
+		Parent:   notionapi.Parent{PageID: notionapi.PageID(page.ID)},
+		RichText: richText("First"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Comment.Create(ctx, &notionapi.CommentCreateRequest{
+		DiscussionID: comment.DiscussionID,
+		RichText:     richText("Reply"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	comments, err := client.Comment.GetAll(ctx, notionapi.BlockID(page.ID)).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 2 || comments[1].RichText[0].PlainText != "Reply" || comments[1].DiscussionID != comment.DiscussionID {
+		t.Errorf("GetAll() got %+v", comments)
+	}
+
+	users, err := client.User.ListAll(ctx).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[0].Name != "Ada" {
+		t.Errorf("ListAll() got %+v", users)
+	}
+	me, err := client.User.Me(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if me.ID != srv.BotUserID() || me.Bot == nil {
+		t.Errorf("Me() got %+v", me)
+	}
+
+	res, err := client.Search.Do(ctx, &notionapi.SearchRequest{Query: "tas"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Results) != 1 || res.Results[0].GetObject() != notionapi.ObjectTypeDatabase {
+		t.Errorf("Search() got %+v, want the database", res.Results)
+	}
+	res, err = client.Search.Do(ctx, &notionapi.SearchRequest{
+		Filter: notionapi.SearchFilter{Property: "object", Value: "page"},
+		Sort:   &notionapi.SortObject{Timestamp: "last_edited_time", Direction: "ascending"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Results) != 2 || res.Results[1].(*notionapi.Page).ID != page.ID {
+		t.Errorf("Search() got %+v, want the root page then the task", res.Results)
+	}
+
+	emptyFilter := srv.Client(notionapi.WithMiddleware(func(next notionapi.Handler) notionapi.Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			body := `{"filter":{"value":"","property":""}}`
+			req.Body, req.ContentLength = ioutil.NopCloser(strings.NewReader(body)), int64(len(body))
+			return next(req)
+		}
+	}))
+	if _, err := emptyFilter.Search.Do(ctx, &notionapi.SearchRequest{}); err == nil {
+		t.Error("Search() with an empty filter error = nil")
+	}
+
+	_, err = notionapi.NewClient("wrong", notionapi.WithBaseURL(srv.URL)).User.Me(ctx)
+	var apiErr *notionapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Status != 401 {
+		t.Errorf("Me() with a wrong token error = %v, want unauthorized", err)
+	}
+}
@@ -0,0 +1,175 @@
+package notionapitest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/jomei/notionapi"
+)
+
+func (s *Server) createComment(r *http.Request, body object, _ []string) (interface{}, error) {
+	var parent interface{}
+	discussionID, _ := body["discussion_id"].(string)
+	if discussionID != "" {
+		for _, c := range s.comments {
+			if key(c["discussion_id"].(string)) == key(discussionID) {
+				parent = c["parent"]
+				discussionID = c["discussion_id"].(string)
+			}
+		}
+		if parent == nil {
+			return nil, notFoundError("discussion", discussionID)
+		}
+	} else {
+		p, _ := body["parent"].(object)
+		if id, ok := p["page_id"].(string); ok {
+			page, err := s.page(id)
+			if err != nil {
+				return nil, err
+			}
+			parent = object{"type": string(notionapi.ParentTypePageID), "page_id": page["id"]}
+		} else if id, ok := p["block_id"].(string); ok {
+			b, err := s.block(id)
+			if err != nil {
+				return nil, err
+			}
+			parent = object{"type": string(notionapi.ParentTypeBlockID), "block_id": b["id"]}
+		} else {
+			return nil, validationError("body failed validation: exactly one of body.parent or body.discussion_id should be defined.")
+		}
+		discussionID = s.newID()
+	}
+
+	now := s.timestamp()
+	comment := object{
+		"object":           notionapi.ObjectTypeComment.String(),
+		"id":               s.newID(),
+		"parent":           parent,
+		"discussion_id":    discussionID,
+		"created_time":     now,
+		"last_edited_time": now,
+		"created_by":       partialUser(s.bot),
+		"rich_text":        normalizeRichText(body["rich_text"]),
+	}
+	s.comments = append(s.comments, comment)
+	return comment, nil
+}
+
+func (s *Server) getComments(r *http.Request, _ object, _ []string) (interface{}, error) {
+	id := r.URL.Query().Get("block_id")
+	if id == "" {
+		return nil, validationError("body failed validation: query.block_id should be defined, instead was `undefined`.")
+	}
+	var comments []object
+	for _, c := range s.comments {
+		parent := c["parent"].(object)
+		for _, k := range []string{"page_id", "block_id"} {
+			if v, ok := parent[k].(string); ok && key(v) == key(id) {
+				comments = append(comments, c)
+			}
+		}
+	}
+
+	cursor, size, err := pagination(r, nil)
+	if err != nil {
+		return nil, err
+	}
+	results, next, hasMore, err := paginate(comments, cursor, size)
+	if err != nil {
+		return nil, err
+	}
+	return list(results, next, hasMore, notionapi.ObjectTypeComment.String()), nil
+}
+
+// user returns the user with the given ID, or nil if there is none.
+func (s *Server) user(id string) object {
+	for _, u := range append([]object{s.bot}, s.users...) {
+		if key(u["id"].(string)) == key(id) {
+			return u
+		}
+	}
+	return nil
+}
+
+func (s *Server) listUsers(r *http.Request, _ object, _ []string) (interface{}, error) {
+	cursor, size, err := pagination(r, nil)
+	if err != nil {
+		return nil, err
+	}
+	results, next, hasMore, err := paginate(append(append([]object{}, s.users...), s.bot), cursor, size)
+	if err != nil {
+		return nil, err
+	}
+	return list(results, next, hasMore, notionapi.ObjectTypeUser.String()), nil
+}
+
+func (s *Server) getUser(r *http.Request, _ object, ids []string) (interface{}, error) {
+	if u := s.user(ids[0]); u != nil {
+		return u, nil
+	}
+	return nil, notFoundError("user", ids[0])
+}
+
+func (s *Server) me(r *http.Request, _ object, _ []string) (interface{}, error) {
+	return s.bot, nil
+}
+
+// search returns the pages and databases whose title contains the query,
+// most recently edited first unless sorted otherwise.
+func (s *Server) search(r *http.Request, body object, _ []string) (interface{}, error) {
+	query, _ := body["query"].(string)
+	query = strings.ToLower(query)
+
+	kind := ""
+	if filter, ok := body["filter"].(object); ok {
+		if filter["property"] != "object" || (filter["value"] != "page" && filter["value"] != "database") {
+			return nil, validationError("body failed validation: body.filter.value should be `\"page\"` or `\"database\"`.")
+		}
+		kind = filter["value"].(string)
+	}
+
+	var results []object
+	for _, id := range s.order {
+		var o object
+		var title string
+		if page, ok := s.pages[key(id)]; ok {
+			o, title = page, pageTitle(page)
+		} else {
+			db := s.databases[key(id)]
+			o, title = db, plainText(db["title"])
+		}
+		if o["archived"] == true || (kind != "" && o["object"] != kind) {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(title), query) {
+			continue
+		}
+		results = append(results, o)
+	}
+
+	ascending := false
+	if sortObject, ok := body["sort"].(object); ok {
+		if ts, ok := sortObject["timestamp"].(string); ok && ts != "last_edited_time" {
+			return nil, validationError("body failed validation: body.sort.timestamp should be `\"last_edited_time\"`.")
+		}
+		ascending = sortObject["direction"] == "ascending"
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := parseTime(results[i]["last_edited_time"]), parseTime(results[j]["last_edited_time"])
+		if ascending {
+			return a.Before(*b)
+		}
+		return a.After(*b)
+	})
+
+	cursor, size, err := pagination(r, body)
+	if err != nil {
+		return nil, err
+	}
+	page, next, hasMore, err := paginate(results, cursor, size)
+	if err != nil {
+		return nil, err
+	}
+	return list(page, next, hasMore, "page_or_database"), nil
+}
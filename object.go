@@ -149,10 +149,11 @@ func (d *Date) UnmarshalText(data []byte) error {
 type FileType string
 
 type File struct {
-	Name     string      `json:"name"`
-	Type     FileType    `json:"type"`
-	File     *FileObject `json:"file,omitempty"`
-	External *FileObject `json:"external,omitempty"`
+	Name       string            `json:"name"`
+	Type       FileType          `json:"type"`
+	File       *FileObject       `json:"file,omitempty"`
+	External   *FileObject       `json:"external,omitempty"`
+	FileUpload *FileUploadObject `json:"file_upload,omitempty"`
 }
 
 type FileObject struct {
@@ -160,12 +161,20 @@ type FileObject struct {
 	ExpiryTime *time.Time `json:"expiry_time,omitempty"`
 }
 
+// FileUploadObject references a file uploaded with FileUploadService, in
+// files of type FileTypeFileUpload. Once attached, Notion returns the file as
+// a file of type FileTypeFile.
+type FileUploadObject struct {
+	ID FileUploadID `json:"id"`
+}
+
 type Icon struct {
-	Type        FileType     `json:"type"`
-	Emoji       *Emoji       `json:"emoji,omitempty"`
-	CustomEmoji *CustomEmoji `json:"custom_emoji,omitempty"`
-	File        *FileObject  `json:"file,omitempty"`
-	External    *FileObject  `json:"external,omitempty"`
+	Type        FileType          `json:"type"`
+	Emoji       *Emoji            `json:"emoji,omitempty"`
+	CustomEmoji *CustomEmoji      `json:"custom_emoji,omitempty"`
+	File        *FileObject       `json:"file,omitempty"`
+	External    *FileObject       `json:"external,omitempty"`
+	FileUpload  *FileUploadObject `json:"file_upload,omitempty"`
 }
 
 // GetURL returns the external or internal URL depending on the image type.
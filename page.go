@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -19,6 +20,8 @@ type PageService interface {
 	Create(context.Context, *PageCreateRequest) (*Page, error)
 	Get(context.Context, PageID) (*Page, error)
 	Update(context.Context, PageID, *PageUpdateRequest) (*Page, error)
+	GetProperty(context.Context, PageID, PropertyID, *Pagination) (*PropertyItemResponse, error)
+	GetFullProperty(context.Context, PageID, PropertyID) (Property, error)
 }
 
 type PageClient struct {
@@ -98,6 +101,62 @@ func (pc *PageClient) Get(ctx context.Context, id PageID) (*Page, error) {
 	return handlePageResponse(res)
 }
 
+// Retrieves a property_item object for a given page ID and property ID.
+// Depending on the property type, the object returned will either be a value
+// or a paginated list of property item values.
+//
+// Title, rich_text, relation and people properties, as well as rollups of
+// those, are returned as a paginated list. Use GetFullProperty to retrieve all
+// of their values at once.
+//
+// See https://developers.notion.com/reference/retrieve-a-page-property
+func (pc *PageClient) GetProperty(ctx context.Context, pageID PageID, propertyID PropertyID, pagination *Pagination) (*PropertyItemResponse, error) {
+	res, err := pc.apiClient.request(ctx, http.MethodGet, fmt.Sprintf("pages/%s/properties/%s", pageID.String(), url.PathEscape(propertyID.String())), pagination.ToQuery(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if errClose := res.Body.Close(); errClose != nil {
+			log.Println("failed to close body, should never happen")
+		}
+	}()
+
+	var response PropertyItemResponse
+	err = json.NewDecoder(res.Body).Decode(&response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// GetFullProperty retrieves a page property with all of its values, following
+// the pagination of the Retrieve a page property endpoint. Unlike the
+// properties returned by Get, the result is not limited to 25 references.
+func (pc *PageClient) GetFullProperty(ctx context.Context, pageID PageID, propertyID PropertyID) (Property, error) {
+	pagination := &Pagination{PageSize: 100}
+	var items []PropertyItem
+	for {
+		res, err := pc.GetProperty(ctx, pageID, propertyID, pagination)
+		if err != nil {
+			return nil, err
+		}
+		if res.Object != ObjectTypeList {
+			return res.Item, nil
+		}
+
+		items = append(items, res.Results...)
+		if !res.HasMore || res.NextCursor == "" {
+			if res.PropertyItem == nil {
+				return nil, fmt.Errorf("notionapi: list of items of property %s has no property_item", propertyID)
+			}
+			return res.PropertyItem.toProperty(items)
+		}
+		pagination.StartCursor = res.NextCursor
+	}
+}
+
 // Updates the properties of a page in a database. The properties body param of
 // this endpoint can only be used to update the properties of a page that is a
 // child of a database. The page’s properties schema must match the parent
@@ -183,6 +242,181 @@ type Parent struct {
 	Workspace  bool       `json:"workspace,omitempty"`
 }
 
+// PropertyItemResponse is the response of PageClient.GetProperty. Object is
+// either "property_item", in which case Item holds the property value, or
+// "list", in which case Results holds one page of property item values.
+type PropertyItemResponse struct {
+	Object ObjectType `json:"object"`
+	// Item is the value of properties which are not paginated.
+	Item Property `json:"-"`
+	// Results, PropertyItem, HasMore and NextCursor are only set for paginated
+	// properties.
+	Results      []PropertyItem    `json:"results,omitempty"`
+	PropertyItem *PropertyItemList `json:"property_item,omitempty"`
+	HasMore      bool              `json:"has_more,omitempty"`
+	NextCursor   Cursor            `json:"next_cursor,omitempty"`
+}
+
+func (r *PropertyItemResponse) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if ObjectType(fmt.Sprint(raw["object"])) == ObjectTypePropertyItem {
+		p, err := decodeProperty(raw)
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(data, p); err != nil {
+			return err
+		}
+		*r = PropertyItemResponse{Object: ObjectTypePropertyItem, Item: p}
+		return nil
+	}
+
+	type list PropertyItemResponse
+	var tmp list
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	*r = PropertyItemResponse(tmp)
+	return nil
+}
+
+// PropertyItem is a single value of a paginated page property.
+type PropertyItem struct {
+	Object   ObjectType   `json:"object"`
+	ID       PropertyID   `json:"id"`
+	Type     PropertyType `json:"type"`
+	Title    *RichText    `json:"title,omitempty"`
+	RichText *RichText    `json:"rich_text,omitempty"`
+	Relation *Relation    `json:"relation,omitempty"`
+	People   *User        `json:"people,omitempty"`
+	// Value is the item as a Property of its type, such as the values of
+	// rollups of numbers or dates.
+	Value Property `json:"-"`
+}
+
+func (pi *PropertyItem) UnmarshalJSON(data []byte) error {
+	type item PropertyItem
+	var tmp item
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	if tmp.Type == "" {
+		return fmt.Errorf("property item %s has no type", tmp.ID)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	switch tmp.Type {
+	case PropertyTypeTitle, PropertyTypeRichText, PropertyTypeRelation, PropertyTypePeople:
+		// Items hold a single value of the list of these properties.
+		raw[string(tmp.Type)] = []interface{}{raw[string(tmp.Type)]}
+	}
+	value, err := decodeProperty(raw)
+	if err != nil {
+		return err
+	}
+	data, err = json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return err
+	}
+	tmp.Value = value
+	*pi = PropertyItem(tmp)
+	return nil
+}
+
+// toProperty converts the item to the equivalent single-valued Property.
+func (pi PropertyItem) toProperty() Property {
+	if pi.Value != nil {
+		return pi.Value
+	}
+	switch {
+	case pi.Title != nil:
+		return &TitleProperty{ID: pi.ID, Type: PropertyTypeTitle, Title: []RichText{*pi.Title}}
+	case pi.RichText != nil:
+		return &RichTextProperty{ID: pi.ID, Type: PropertyTypeRichText, RichText: []RichText{*pi.RichText}}
+	case pi.Relation != nil:
+		return &RelationProperty{ID: ObjectID(pi.ID), Type: PropertyTypeRelation, Relation: []Relation{*pi.Relation}}
+	case pi.People != nil:
+		return &PeopleProperty{ID: ObjectID(pi.ID), Type: PropertyTypePeople, People: []User{*pi.People}}
+	}
+	return nil
+}
+
+// PropertyItemList describes the property a paginated list of property items
+// belongs to.
+type PropertyItemList struct {
+	ID      PropertyID   `json:"id"`
+	Type    PropertyType `json:"type"`
+	NextURL string       `json:"next_url,omitempty"`
+	// Rollup holds the aggregated value of rollup properties.
+	Rollup *Rollup `json:"rollup,omitempty"`
+}
+
+// toProperty assembles the values of all the pages of a property item list
+// into a single Property.
+func (l *PropertyItemList) toProperty(items []PropertyItem) (Property, error) {
+	switch l.Type {
+	case PropertyTypeTitle:
+		p := &TitleProperty{ID: l.ID, Type: l.Type, Title: []RichText{}}
+		for _, item := range items {
+			if item.Title != nil {
+				p.Title = append(p.Title, *item.Title)
+			}
+		}
+		return p, nil
+	case PropertyTypeRichText:
+		p := &RichTextProperty{ID: l.ID, Type: l.Type, RichText: []RichText{}}
+		for _, item := range items {
+			if item.RichText != nil {
+				p.RichText = append(p.RichText, *item.RichText)
+			}
+		}
+		return p, nil
+	case PropertyTypeRelation:
+		p := &RelationProperty{ID: ObjectID(l.ID), Type: l.Type, Relation: []Relation{}}
+		for _, item := range items {
+			if item.Relation != nil {
+				p.Relation = append(p.Relation, *item.Relation)
+			}
+		}
+		return p, nil
+	case PropertyTypePeople:
+		p := &PeopleProperty{ID: ObjectID(l.ID), Type: l.Type, People: []User{}}
+		for _, item := range items {
+			if item.People != nil {
+				p.People = append(p.People, *item.People)
+			}
+		}
+		return p, nil
+	case PropertyTypeRollup:
+		p := &RollupProperty{ID: ObjectID(l.ID), Type: l.Type}
+		if l.Rollup != nil {
+			p.Rollup = *l.Rollup
+		}
+		if p.Rollup.Type == RollupTypeArray && len(p.Rollup.Array) == 0 {
+			p.Rollup.Array = PropertyArray{}
+			for _, item := range items {
+				v := item.toProperty()
+				if v == nil {
+					return nil, fmt.Errorf("unsupported property item type %q of rollup %s", item.Type, l.ID)
+				}
+				p.Rollup.Array = append(p.Rollup.Array, v)
+			}
+		}
+		return p, nil
+	}
+	return nil, fmt.Errorf("unsupported paginated property type %q of property %s", l.Type, l.ID)
+}
+
 func handlePageResponse(res *http.Response) (*Page, error) {
 	var response Page
 	err := json.NewDecoder(res.Body).Decode(&response)
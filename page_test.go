@@ -3,8 +3,11 @@ package notionapi_test
 import (
 	"context"
 	"encoding/json"
+	"io/ioutil"
 	"net/http"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -340,6 +343,182 @@ func TestPageClient(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("GetProperty", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			filePath   string
+			statusCode int
+			pageID     notionapi.PageID
+			propertyID notionapi.PropertyID
+			want       *notionapi.PropertyItemResponse
+			wantErr    bool
+		}{
+			{
+				name:       "returns property item value",
+				pageID:     "some_id",
+				propertyID: "kjPO",
+				filePath:   "testdata/page_get_property.json",
+				statusCode: http.StatusOK,
+				want: &notionapi.PropertyItemResponse{
+					Object: notionapi.ObjectTypePropertyItem,
+					Item: &notionapi.NumberProperty{
+						ID:     "kjPO",
+						Type:   notionapi.PropertyTypeNumber,
+						Number: 2,
+					},
+				},
+			},
+			{
+				name:       "returns paginated property items",
+				pageID:     "some_id",
+				propertyID: "vYdV",
+				filePath:   "testdata/page_get_property_list.json",
+				statusCode: http.StatusOK,
+				want: &notionapi.PropertyItemResponse{
+					Object: notionapi.ObjectTypeList,
+					Results: []notionapi.PropertyItem{
+						{
+							Object:   notionapi.ObjectTypePropertyItem,
+							ID:       "vYdV",
+							Type:     notionapi.PropertyTypeRelation,
+							Relation: &notionapi.Relation{ID: "535c3fb2-95e6-4b37-a696-036e5eac5cf6"},
+							Value: &notionapi.RelationProperty{
+								ID:       "vYdV",
+								Type:     notionapi.PropertyTypeRelation,
+								Relation: []notionapi.Relation{{ID: "535c3fb2-95e6-4b37-a696-036e5eac5cf6"}},
+							},
+						},
+						{
+							Object:   notionapi.ObjectTypePropertyItem,
+							ID:       "vYdV",
+							Type:     notionapi.PropertyTypeRelation,
+							Relation: &notionapi.Relation{ID: "2e8e5c4f-b8fb-4a42-a1cc-bc8e0a5c1b1c"},
+							Value: &notionapi.RelationProperty{
+								ID:       "vYdV",
+								Type:     notionapi.PropertyTypeRelation,
+								Relation: []notionapi.Relation{{ID: "2e8e5c4f-b8fb-4a42-a1cc-bc8e0a5c1b1c"}},
+							},
+						},
+					},
+					PropertyItem: &notionapi.PropertyItemList{
+						ID:      "vYdV",
+						Type:    notionapi.PropertyTypeRelation,
+						NextURL: "https://api.notion.com/v1/pages/some_id/properties/vYdV?start_cursor=some_cursor",
+					},
+					HasMore:    true,
+					NextCursor: "some_cursor",
+				},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				c := newMockedClient(t, tt.filePath, tt.statusCode)
+				client := notionapi.NewClient("some_token", notionapi.WithHTTPClient(c))
+				got, err := client.Page.GetProperty(context.Background(), tt.pageID, tt.propertyID, nil)
+
+				if (err != nil) != tt.wantErr {
+					t.Errorf("GetProperty() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("GetProperty() got = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("GetFullProperty", func(t *testing.T) {
+		c := newTestClient(func(req *http.Request) *http.Response {
+			filePath := "testdata/page_get_property_list.json"
+			if req.URL.Query().Get("start_cursor") == "some_cursor" {
+				filePath = "testdata/page_get_property_list_2.json"
+			}
+			b, err := os.Open(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       b,
+				Header:     make(http.Header),
+			}
+		})
+		client := notionapi.NewClient("some_token", notionapi.WithHTTPClient(c))
+
+		got, err := client.Page.GetFullProperty(context.Background(), "some_id", "vYdV")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &notionapi.RelationProperty{
+			ID:   "vYdV",
+			Type: notionapi.PropertyTypeRelation,
+			Relation: []notionapi.Relation{
+				{ID: "535c3fb2-95e6-4b37-a696-036e5eac5cf6"},
+				{ID: "2e8e5c4f-b8fb-4a42-a1cc-bc8e0a5c1b1c"},
+				{ID: "8a4a4a1e-2d6e-4f1f-a1f6-2e5c0c8b3a7d"},
+			},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GetFullProperty() got = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("GetFullProperty of a rollup", func(t *testing.T) {
+		c := newMockedClient(t, "testdata/page_get_property_rollup.json", http.StatusOK)
+		client := notionapi.NewClient("some_token", notionapi.WithHTTPClient(c))
+
+		got, err := client.Page.GetFullProperty(context.Background(), "some_id", "qUsF")
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := notionapi.Date(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+		want := &notionapi.RollupProperty{
+			ID:   "qUsF",
+			Type: notionapi.PropertyTypeRollup,
+			Rollup: notionapi.Rollup{
+				Type:     notionapi.RollupTypeArray,
+				Function: "show_original",
+				Array: notionapi.PropertyArray{
+					&notionapi.NumberProperty{ID: "qUsF", Type: notionapi.PropertyTypeNumber, Number: 3},
+					&notionapi.DateProperty{ID: "qUsF", Type: notionapi.PropertyTypeDate, Date: &notionapi.DateObject{Start: &start}},
+					&notionapi.SelectProperty{ID: "qUsF", Type: notionapi.PropertyTypeSelect, Select: notionapi.Option{ID: "ff8e9269", Name: "Done", Color: notionapi.ColorGreen}},
+				},
+			},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GetFullProperty() got = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("GetProperty with an unsupported item type", func(t *testing.T) {
+		c := newTestClient(func(req *http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: ioutil.NopCloser(strings.NewReader(`{"object": "list", "results": [{"object": "property_item", "id": "qUsF", "type": "hologram"}],` +
+					`"has_more": false, "property_item": {"id": "qUsF", "type": "rollup", "rollup": {"type": "array", "array": []}}}`)),
+				Header: make(http.Header),
+			}
+		})
+		client := notionapi.NewClient("some_token", notionapi.WithHTTPClient(c))
+		if _, err := client.Page.GetFullProperty(context.Background(), "some_id", "qUsF"); err == nil {
+			t.Error("GetFullProperty() error = nil, want an error for the unsupported item")
+		}
+	})
+
+	t.Run("GetFullProperty of a list without property item", func(t *testing.T) {
+		c := newTestClient(func(req *http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"object": "list", "results": [], "has_more": false}`)),
+				Header:     make(http.Header),
+			}
+		})
+		client := notionapi.NewClient("some_token", notionapi.WithHTTPClient(c))
+		if _, err := client.Page.GetFullProperty(context.Background(), "some_id", "vYdV"); err == nil {
+			t.Error("GetFullProperty() error = nil, want an error for the missing property item")
+		}
+	})
 }
 
 func TestPageCreateRequest_MarshallJSON(t *testing.T) {
@@ -197,10 +197,11 @@ type RollupProperty struct {
 type RollupType string
 
 type Rollup struct {
-	Type   RollupType    `json:"type,omitempty"`
-	Number float64       `json:"number,omitempty"`
-	Date   *DateObject   `json:"date,omitempty"`
-	Array  PropertyArray `json:"array,omitempty"`
+	Type     RollupType    `json:"type,omitempty"`
+	Number   float64       `json:"number,omitempty"`
+	Date     *DateObject   `json:"date,omitempty"`
+	Array    PropertyArray `json:"array,omitempty"`
+	Function FunctionType  `json:"function,omitempty"`
 }
 
 func (p RollupProperty) GetID() string {
@@ -252,9 +252,9 @@ type RollupPropertyConfig struct {
 
 type RollupConfig struct {
 	RelationPropertyName string       `json:"relation_property_name"`
-	RelationPropertyID   PropertyID   `json:"relation_property_id"`
+	RelationPropertyID   PropertyID   `json:"relation_property_id,omitempty"`
 	RollupPropertyName   string       `json:"rollup_property_name"`
-	RollupPropertyID     PropertyID   `json:"rollup_property_id"`
+	RollupPropertyID     PropertyID   `json:"rollup_property_id,omitempty"`
 	Function             FunctionType `json:"function"`
 }
 
@@ -394,6 +394,57 @@ func (p ButtonPropertyConfig) GetID() PropertyID {
 	return p.ID
 }
 
+// RenamePropertyConfig renames a property in DatabaseUpdateRequest.Properties,
+// where it is keyed by the current name or ID of the property. If Config is
+// set, the configuration of the property is changed as well.
+type RenamePropertyConfig struct {
+	Name   string
+	Config PropertyConfig
+}
+
+func (p RenamePropertyConfig) GetType() PropertyConfigType {
+	if p.Config == nil {
+		return ""
+	}
+	return p.Config.GetType()
+}
+
+func (p RenamePropertyConfig) GetID() PropertyID {
+	return ""
+}
+
+func (p RenamePropertyConfig) MarshalJSON() ([]byte, error) {
+	fields := map[string]interface{}{}
+	if p.Config != nil {
+		b, err := json.Marshal(p.Config)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &fields); err != nil {
+			return nil, err
+		}
+		delete(fields, "id")
+	}
+	fields["name"] = p.Name
+	return json.Marshal(fields)
+}
+
+// DeletePropertyConfig removes a property in DatabaseUpdateRequest.Properties,
+// where it is keyed by the name or ID of the property. It is encoded as null.
+type DeletePropertyConfig struct{}
+
+func (p DeletePropertyConfig) GetType() PropertyConfigType {
+	return ""
+}
+
+func (p DeletePropertyConfig) GetID() PropertyID {
+	return ""
+}
+
+func (p DeletePropertyConfig) MarshalJSON() ([]byte, error) {
+	return []byte("null"), nil
+}
+
 type PropertyConfigs map[string]PropertyConfig
 
 func (p *PropertyConfigs) UnmarshalJSON(data []byte) error {
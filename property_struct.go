@@ -0,0 +1,621 @@
+package notionapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// UnmarshalPage decodes the properties of a page into the struct pointed to
+// by v, see UnmarshalProperties. It also sets the fields with the id option,
+// of type PageID, to the ID of the page:
+//
+//	type Task struct {
+//		ID   PageID `notion:",id"`
+//		Name string `notion:"Name"`
+//	}
+func UnmarshalPage(page *Page, v interface{}) error {
+	if page == nil {
+		return fmt.Errorf("notionapi: cannot unmarshal nil page")
+	}
+	if err := UnmarshalProperties(page.Properties, v); err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(v).Elem()
+	fields, err := structFields(rv.Type())
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		if f.pageID {
+			rv.FieldByIndex(f.index).SetString(page.ID.String())
+		}
+	}
+	return nil
+}
+
+// UnmarshalProperties decodes page properties into the struct pointed to by
+// v. The property of each field is given by its notion tag, fields without
+// tags or tagged "-" are ignored:
+//
+//	type Task struct {
+//		Name   string    `notion:"Name"`
+//		Status string    `notion:"Status"`
+//		Points float64   `notion:"Points"`
+//		Done   bool      `notion:"Done"`
+//		Due    time.Time `notion:"Due"`
+//		Tags   []string  `notion:"Tags"`
+//	}
+//
+// Properties are converted to the types of fields as follows:
+//   - strings, including types based on strings such as enums: the plain
+//     text of title and rich text properties, the name of select and status
+//     options, URLs, emails, phone numbers, string formulas and unique IDs,
+//   - integers and floats: numbers, number formulas and rollups, and the
+//     number of unique IDs,
+//   - booleans: checkboxes and boolean formulas,
+//   - time.Time: the start of dates, date formulas and rollups, and the
+//     created and last edited times,
+//   - User: the creator and last editor,
+//   - slices of strings: the names of multi-select options, the IDs of
+//     related pages and people, and the URLs of files,
+//   - fields of property types, such as *DateProperty, are set when the
+//     property is assignable to them.
+//
+// Pointer fields are allocated. Properties missing from the page leave their
+// fields unchanged.
+func UnmarshalProperties(props Properties, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("notionapi: cannot unmarshal properties into %T, want a pointer to a struct", v)
+	}
+	fields, err := structFields(rv.Elem().Type())
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		if f.pageID {
+			continue
+		}
+		prop, ok := props[f.property]
+		if !ok || prop == nil {
+			continue
+		}
+		if err := decodePropertyValue(propertyPointer(prop), rv.Elem().FieldByIndex(f.index)); err != nil {
+			return fmt.Errorf("notionapi: cannot unmarshal %s property %q into field %s of type %s: %w",
+				prop.GetType(), f.property, f.name, f.typ, err)
+		}
+	}
+	return nil
+}
+
+// MarshalProperties encodes the struct v, or a pointer to it, into page
+// properties for PageCreateRequest and PageUpdateRequest. Fields are mapped to
+// properties by their notion tag, whose optional second element is the type
+// of property, and whose omitempty option skips zero values:
+//
+//	type Task struct {
+//		Name   string   `notion:"Name,title"`
+//		Status string   `notion:"Status,status,omitempty"`
+//		Tags   []string `notion:"Tags"`
+//	}
+//
+// Without a type, strings are encoded as rich text, numbers as numbers,
+// booleans as checkboxes, times as dates and slices of strings as
+// multi-select options. Strings can also be encoded as title, select, status,
+// url, email and phone_number properties, and slices of strings as relation
+// or people properties of page and user IDs, or as files of external URLs.
+// Fields of a Property type are encoded as is. Fields of read-only types, such
+// as formula, rollup or created_time, are skipped, as are nil pointers and
+// page IDs. Empty select, status, url, email and phone_number values are
+// encoded as null, which clears them.
+func MarshalProperties(v interface{}) (Properties, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("notionapi: cannot marshal nil %T", v)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("notionapi: cannot marshal %T into properties, want a struct", v)
+	}
+	fields, err := structFields(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+	props := Properties{}
+	for _, f := range fields {
+		value := rv.FieldByIndex(f.index)
+		if f.pageID || f.omitEmpty && value.IsZero() {
+			continue
+		}
+		prop, err := encodePropertyValue(f.kind, value)
+		if err != nil {
+			return nil, fmt.Errorf("notionapi: cannot marshal field %s of type %s into property %q: %w", f.name, f.typ, f.property, err)
+		}
+		if prop != nil {
+			props[f.property] = prop
+		}
+	}
+	return props, nil
+}
+
+// structField is a field of a struct mapped to a property by its tag.
+type structField struct {
+	name      string
+	index     []int
+	typ       reflect.Type
+	property  string
+	kind      PropertyType
+	omitEmpty bool
+	// pageID tells whether the field holds the ID of the page rather than a
+	// property.
+	pageID bool
+}
+
+// structFields returns the fields of a struct with a notion tag, including
+// the fields of embedded structs.
+func structFields(t reflect.Type) ([]structField, error) {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("notion")
+		if !ok {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				embedded, err := structFields(field.Type)
+				if err != nil {
+					return nil, err
+				}
+				for _, f := range embedded {
+					f.index = append([]int{i}, f.index...)
+					fields = append(fields, f)
+				}
+			}
+			continue
+		}
+		if tag == "-" {
+			continue
+		}
+		if field.PkgPath != "" {
+			return nil, fmt.Errorf("notionapi: field %s of %s has a notion tag but is not exported", field.Name, t)
+		}
+		parts := strings.Split(tag, ",")
+		f := structField{name: field.Name, index: field.Index, typ: field.Type, property: parts[0]}
+		if f.property == "" {
+			f.property = field.Name
+		}
+		for _, option := range parts[1:] {
+			switch option {
+			case "":
+			case "omitempty":
+				f.omitEmpty = true
+			case "id":
+				f.pageID = true
+			default:
+				f.kind = PropertyType(option)
+			}
+		}
+		if f.pageID && field.Type.Kind() != reflect.String {
+			return nil, fmt.Errorf("notionapi: page ID field %s of %s should be a PageID, got %s", field.Name, t, field.Type)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// propertyPointer returns a pointer to the property, as properties may be
+// stored as values or pointers.
+func propertyPointer(p Property) Property {
+	rv := reflect.ValueOf(p)
+	if rv.Kind() == reflect.Ptr {
+		return p
+	}
+	ptr := reflect.New(rv.Type())
+	ptr.Elem().Set(rv)
+	return ptr.Interface().(Property)
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	userType = reflect.TypeOf(User{})
+)
+
+func decodePropertyValue(prop Property, field reflect.Value) error {
+	if _, ok := prop.(*clearedProperty); ok {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	if reflect.TypeOf(prop).AssignableTo(field.Type()) {
+		field.Set(reflect.ValueOf(prop))
+		return nil
+	}
+	if reflect.TypeOf(prop).Elem().AssignableTo(field.Type()) {
+		field.Set(reflect.ValueOf(prop).Elem())
+		return nil
+	}
+	if field.Kind() == reflect.Ptr {
+		if isEmptyProperty(prop) {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+		elem := reflect.New(field.Type().Elem())
+		if err := decodePropertyValue(prop, elem.Elem()); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	switch {
+	case field.Type() == userType:
+		u, ok := propertyUser(prop)
+		if !ok {
+			return errUnsupportedConversion
+		}
+		field.Set(reflect.ValueOf(u))
+		return nil
+	case field.Type() == timeType:
+		t, ok := propertyTime(prop)
+		if !ok {
+			return errUnsupportedConversion
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	case field.Kind() == reflect.String:
+		s, ok := propertyString(prop)
+		if !ok {
+			return errUnsupportedConversion
+		}
+		field.SetString(s)
+		return nil
+	case field.Kind() == reflect.Bool:
+		b, ok := propertyBool(prop)
+		if !ok {
+			return errUnsupportedConversion
+		}
+		field.SetBool(b)
+		return nil
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		values, ok := propertyStrings(prop)
+		if !ok {
+			return errUnsupportedConversion
+		}
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, s := range values {
+			slice.Index(i).SetString(s)
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	n, ok := propertyNumber(prop)
+	if !ok {
+		return errUnsupportedConversion
+	}
+	switch field.Kind() {
+	case reflect.Float32, reflect.Float64:
+		field.SetFloat(n)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if field.OverflowInt(int64(n)) {
+			return fmt.Errorf("%v overflows the field", n)
+		}
+		field.SetInt(int64(n))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n < 0 || field.OverflowUint(uint64(n)) {
+			return fmt.Errorf("%v overflows the field", n)
+		}
+		field.SetUint(uint64(n))
+	default:
+		return errUnsupportedConversion
+	}
+	return nil
+}
+
+var errUnsupportedConversion = fmt.Errorf("unsupported conversion")
+
+// richTextString returns the plain text of rich text, which is only set in
+// responses, or the content of its text objects.
+func richTextString(richText []RichText) string {
+	var b strings.Builder
+	for _, rt := range richText {
+		if rt.PlainText == "" && rt.Text != nil {
+			b.WriteString(rt.Text.Content)
+		} else {
+			b.WriteString(rt.PlainText)
+		}
+	}
+	return b.String()
+}
+
+func propertyString(prop Property) (string, bool) {
+	switch p := prop.(type) {
+	case *TitleProperty:
+		return richTextString(p.Title), true
+	case *RichTextProperty:
+		return richTextString(p.RichText), true
+	case *TextProperty:
+		return richTextString(p.Text), true
+	case *SelectProperty:
+		return p.Select.Name, true
+	case *StatusProperty:
+		return p.Status.Name, true
+	case *URLProperty:
+		return p.URL, true
+	case *EmailProperty:
+		return p.Email, true
+	case *PhoneNumberProperty:
+		return p.PhoneNumber, true
+	case *FormulaProperty:
+		return p.Formula.String, true
+	case *UniqueIDProperty:
+		return p.UniqueID.String(), true
+	}
+	return "", false
+}
+
+func propertyNumber(prop Property) (float64, bool) {
+	switch p := prop.(type) {
+	case *NumberProperty:
+		return p.Number, true
+	case *FormulaProperty:
+		return p.Formula.Number, true
+	case *RollupProperty:
+		return p.Rollup.Number, true
+	case *UniqueIDProperty:
+		return float64(p.UniqueID.Number), true
+	}
+	return 0, false
+}
+
+func propertyBool(prop Property) (bool, bool) {
+	switch p := prop.(type) {
+	case *CheckboxProperty:
+		return p.Checkbox, true
+	case *FormulaProperty:
+		return p.Formula.Boolean, true
+	}
+	return false, false
+}
+
+func dateStart(d *DateObject) time.Time {
+	if d == nil || d.Start == nil {
+		return time.Time{}
+	}
+	return time.Time(*d.Start)
+}
+
+func propertyTime(prop Property) (time.Time, bool) {
+	switch p := prop.(type) {
+	case *DateProperty:
+		return dateStart(p.Date), true
+	case *FormulaProperty:
+		return dateStart(p.Formula.Date), true
+	case *RollupProperty:
+		return dateStart(p.Rollup.Date), true
+	case *CreatedTimeProperty:
+		return p.CreatedTime, true
+	case *LastEditedTimeProperty:
+		return p.LastEditedTime, true
+	}
+	return time.Time{}, false
+}
+
+func propertyUser(prop Property) (User, bool) {
+	switch p := prop.(type) {
+	case *CreatedByProperty:
+		return p.CreatedBy, true
+	case *LastEditedByProperty:
+		return p.LastEditedBy, true
+	}
+	return User{}, false
+}
+
+func propertyStrings(prop Property) ([]string, bool) {
+	var values []string
+	switch p := prop.(type) {
+	case *MultiSelectProperty:
+		for _, o := range p.MultiSelect {
+			values = append(values, o.Name)
+		}
+	case *RelationProperty:
+		for _, r := range p.Relation {
+			values = append(values, r.ID.String())
+		}
+	case *PeopleProperty:
+		for _, u := range p.People {
+			values = append(values, u.ID.String())
+		}
+	case *FilesProperty:
+		for _, f := range p.Files {
+			values = append(values, fileURL(f.File, f.External))
+		}
+	default:
+		return nil, false
+	}
+	return values, true
+}
+
+// isEmptyProperty reports whether a property has no value, in which case
+// pointer fields are set to nil.
+func isEmptyProperty(prop Property) bool {
+	switch p := prop.(type) {
+	case *DateProperty:
+		return p.Date == nil || p.Date.Start == nil
+	case *SelectProperty:
+		return p.Select.Name == ""
+	case *StatusProperty:
+		return p.Status.Name == ""
+	case *URLProperty:
+		return p.URL == ""
+	case *EmailProperty:
+		return p.Email == ""
+	case *PhoneNumberProperty:
+		return p.PhoneNumber == ""
+	case *clearedProperty:
+		return true
+	}
+	return false
+}
+
+// readOnlyPropertyTypes are the types of properties computed by Notion, which
+// cannot be set.
+var readOnlyPropertyTypes = map[PropertyType]bool{
+	PropertyTypeFormula:        true,
+	PropertyTypeRollup:         true,
+	PropertyTypeCreatedTime:    true,
+	PropertyTypeCreatedBy:      true,
+	PropertyTypeLastEditedTime: true,
+	PropertyTypeLastEditedBy:   true,
+	PropertyTypeUniqueID:       true,
+	PropertyTypeVerification:   true,
+	PropertyTypeButton:         true,
+}
+
+var propertyInterface = reflect.TypeOf((*Property)(nil)).Elem()
+
+func encodePropertyValue(kind PropertyType, value reflect.Value) (Property, error) {
+	if readOnlyPropertyTypes[kind] {
+		return nil, nil
+	}
+	if value.Type().Implements(propertyInterface) {
+		if value.Kind() == reflect.Ptr && value.IsNil() {
+			return nil, nil
+		}
+		return value.Interface().(Property), nil
+	}
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, nil
+		}
+		return encodePropertyValue(kind, value.Elem())
+	}
+
+	switch {
+	case value.Type() == timeType:
+		if kind != "" && kind != PropertyTypeDate {
+			return nil, errUnsupportedConversion
+		}
+		t := value.Interface().(time.Time)
+		if t.IsZero() {
+			return DateProperty{Date: nil}, nil
+		}
+		d := Date(t)
+		return DateProperty{Date: &DateObject{Start: &d}}, nil
+	case value.Kind() == reflect.String:
+		return encodeString(kind, value.String())
+	case value.Kind() == reflect.Bool:
+		if kind != "" && kind != PropertyTypeCheckbox {
+			return nil, errUnsupportedConversion
+		}
+		return CheckboxProperty{Checkbox: value.Bool()}, nil
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String:
+		values := make([]string, value.Len())
+		for i := range values {
+			values[i] = value.Index(i).String()
+		}
+		return encodeStrings(kind, values)
+	}
+
+	if kind != "" && kind != PropertyTypeNumber {
+		return nil, errUnsupportedConversion
+	}
+	switch value.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return NumberProperty{Number: value.Float()}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return NumberProperty{Number: float64(value.Int())}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return NumberProperty{Number: float64(value.Uint())}, nil
+	}
+	return nil, errUnsupportedConversion
+}
+
+func encodeString(kind PropertyType, s string) (Property, error) {
+	text := []RichText{{Type: ObjectTypeText, Text: &Text{Content: s}}}
+	if s == "" {
+		text = []RichText{}
+	}
+	switch kind {
+	case "", PropertyTypeRichText:
+		return RichTextProperty{RichText: text}, nil
+	case PropertyTypeTitle:
+		return TitleProperty{Title: text}, nil
+	case PropertyTypeSelect:
+		if s == "" {
+			return clearedProperty{Type: kind}, nil
+		}
+		return SelectProperty{Select: Option{Name: s}}, nil
+	case PropertyTypeStatus:
+		if s == "" {
+			return clearedProperty{Type: kind}, nil
+		}
+		return StatusProperty{Status: Status{Name: s}}, nil
+	case PropertyTypeURL:
+		if s == "" {
+			return clearedProperty{Type: kind}, nil
+		}
+		return URLProperty{URL: s}, nil
+	case PropertyTypeEmail:
+		if s == "" {
+			return clearedProperty{Type: kind}, nil
+		}
+		return EmailProperty{Email: s}, nil
+	case PropertyTypePhoneNumber:
+		if s == "" {
+			return clearedProperty{Type: kind}, nil
+		}
+		return PhoneNumberProperty{PhoneNumber: s}, nil
+	}
+	return nil, errUnsupportedConversion
+}
+
+// clearedProperty is a property without value, which is encoded as null to
+// clear the values of select, status, url, email and phone_number properties.
+type clearedProperty struct {
+	Type PropertyType
+}
+
+func (p clearedProperty) GetID() string {
+	return ""
+}
+
+func (p clearedProperty) GetType() PropertyType {
+	return p.Type
+}
+
+func (p clearedProperty) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{string(p.Type): nil})
+}
+
+func encodeStrings(kind PropertyType, values []string) (Property, error) {
+	switch kind {
+	case "", PropertyTypeMultiSelect:
+		options := make([]Option, len(values))
+		for i, v := range values {
+			options[i] = Option{Name: v}
+		}
+		return MultiSelectProperty{MultiSelect: options}, nil
+	case PropertyTypeRelation:
+		relations := make([]Relation, len(values))
+		for i, v := range values {
+			relations[i] = Relation{ID: PageID(v)}
+		}
+		return RelationProperty{Relation: relations}, nil
+	case PropertyTypePeople:
+		people := make([]User, len(values))
+		for i, v := range values {
+			people[i] = User{ID: UserID(v)}
+		}
+		return PeopleProperty{People: people}, nil
+	case PropertyTypeFiles:
+		files := make([]File, len(values))
+		for i, v := range values {
+			files[i] = File{Name: v, Type: FileTypeExternal, External: &FileObject{URL: v}}
+		}
+		return FilesProperty{Files: files}, nil
+	}
+	return nil, errUnsupportedConversion
+}
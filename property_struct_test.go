@@ -0,0 +1,194 @@
+package notionapi_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+)
+
+type taskStatus string
+
+type task struct {
+	PageID   notionapi.PageID        `notion:",id"`
+	Name     string                  `notion:"Name,title"`
+	Notes    string                  `notion:"Notes"`
+	Status   taskStatus              `notion:"Status,status,omitempty"`
+	Points   float64                 `notion:"Points"`
+	Estimate *int                    `notion:"Estimate"`
+	Done     bool                    `notion:"Done"`
+	Due      time.Time               `notion:"Due"`
+	Tags     []string                `notion:"Tags"`
+	Blocked  []string                `notion:"Blocked by,relation"`
+	Link     string                  `notion:"Link,url,omitempty"`
+	Number   int                     `notion:"ID,unique_id"`
+	Created  time.Time               `notion:"Created,created_time"`
+	Owner    notionapi.User          `notion:"Owner,created_by"`
+	Ignored  string                  `notion:"-"`
+	Raw      *notionapi.DateProperty `notion:"Due"`
+}
+
+const taskPage = `{
+	"object": "page",
+	"id": "59833787-2cf9-4fdf-8782-e53db20768a5",
+	"properties": {
+		"Name": {"id": "title", "type": "title", "title": [{"type": "text", "text": {"content": "Ship it"}, "plain_text": "Ship it"}]},
+		"Notes": {"id": "a", "type": "rich_text", "rich_text": [{"type": "text", "text": {"content": "one "}, "plain_text": "one "}, {"type": "text", "text": {"content": "two"}, "plain_text": "two"}]},
+		"Status": {"id": "b", "type": "status", "status": {"id": "s", "name": "In progress", "color": "blue"}},
+		"Points": {"id": "c", "type": "number", "number": 3.5},
+		"Estimate": {"id": "d", "type": "number", "number": 8},
+		"Done": {"id": "e", "type": "checkbox", "checkbox": true},
+		"Due": {"id": "f", "type": "date", "date": {"start": "2024-03-01T00:00:00.000Z", "end": null}},
+		"Tags": {"id": "g", "type": "multi_select", "multi_select": [{"name": "a"}, {"name": "b"}]},
+		"Blocked by": {"id": "h", "type": "relation", "relation": [{"id": "2a5bbce5-3a70-4d5e-8d27-e7a7d5b4a6ff"}]},
+		"Link": {"id": "i", "type": "url", "url": "https://example.com"},
+		"ID": {"id": "j", "type": "unique_id", "unique_id": {"prefix": "T", "number": 42}},
+		"Created": {"id": "k", "type": "created_time", "created_time": "2024-02-01T10:00:00.000Z"},
+		"Owner": {"id": "l", "type": "created_by", "created_by": {"object": "user", "id": "u1"}}
+	}
+}`
+
+func TestUnmarshalPage(t *testing.T) {
+	var page notionapi.Page
+	if err := json.Unmarshal([]byte(taskPage), &page); err != nil {
+		t.Fatal(err)
+	}
+
+	var got task
+	if err := notionapi.UnmarshalPage(&page, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	estimate := 8
+	due := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	dueDate := notionapi.Date(due)
+	want := task{
+		PageID:   "59833787-2cf9-4fdf-8782-e53db20768a5",
+		Name:     "Ship it",
+		Notes:    "one two",
+		Status:   "In progress",
+		Points:   3.5,
+		Estimate: &estimate,
+		Done:     true,
+		Due:      due,
+		Tags:     []string{"a", "b"},
+		Blocked:  []string{"2a5bbce5-3a70-4d5e-8d27-e7a7d5b4a6ff"},
+		Link:     "https://example.com",
+		Number:   42,
+		Created:  time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC),
+		Owner:    notionapi.User{Object: "user", ID: "u1"},
+		Raw: &notionapi.DateProperty{
+			ID:   "f",
+			Type: notionapi.PropertyTypeDate,
+			Date: &notionapi.DateObject{Start: &dueDate},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UnmarshalPage() = %+v, want %+v", got, want)
+	}
+
+	t.Run("mismatched types", func(t *testing.T) {
+		var v struct {
+			Done float64 `notion:"Done"`
+		}
+		if err := notionapi.UnmarshalPage(&page, &v); err == nil {
+			t.Error("UnmarshalPage() error = nil for a checkbox decoded into a float64")
+		}
+	})
+
+	t.Run("non-pointer", func(t *testing.T) {
+		if err := notionapi.UnmarshalPage(&page, task{}); err == nil {
+			t.Error("UnmarshalPage() error = nil for a struct passed by value")
+		}
+	})
+}
+
+func TestMarshalProperties(t *testing.T) {
+	estimate := 8
+	v := task{
+		Name:     "Ship it",
+		Points:   3.5,
+		Estimate: &estimate,
+		Done:     true,
+		Due:      time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Tags:     []string{"a"},
+		Blocked:  []string{"2a5bbce5-3a70-4d5e-8d27-e7a7d5b4a6ff"},
+		Number:   42,
+		Ignored:  "x",
+		PageID:   "59833787-2cf9-4fdf-8782-e53db20768a5",
+	}
+	props, err := notionapi.MarshalProperties(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(props)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{` +
+		`"Blocked by":{"relation":[{"id":"2a5bbce5-3a70-4d5e-8d27-e7a7d5b4a6ff"}]},` +
+		`"Done":{"checkbox":true},` +
+		`"Due":{"date":{"start":"2024-03-01T00:00:00Z","end":null}},` +
+		`"Estimate":{"number":8},` +
+		`"Name":{"title":[{"type":"text","text":{"content":"Ship it"}}]},` +
+		`"Notes":{"rich_text":[]},` +
+		`"Points":{"number":3.5},` +
+		`"Tags":{"multi_select":[{"name":"a"}]}` +
+		`}`
+	if string(got) != want {
+		t.Errorf("MarshalProperties() = %s, want %s", got, want)
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		var decoded task
+		if err := notionapi.UnmarshalProperties(props, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if decoded.Name != v.Name || decoded.Points != v.Points || *decoded.Estimate != estimate ||
+			!decoded.Due.Equal(v.Due) || !reflect.DeepEqual(decoded.Tags, v.Tags) {
+			t.Errorf("UnmarshalProperties(MarshalProperties()) = %+v, want %+v", decoded, v)
+		}
+	})
+
+	t.Run("empty values", func(t *testing.T) {
+		v := struct {
+			Link   string     `notion:"Link,url"`
+			Email  string     `notion:"Email,email"`
+			Phone  string     `notion:"Phone,phone_number"`
+			Kind   string     `notion:"Kind,select"`
+			Status taskStatus `notion:"Status,status"`
+		}{}
+		props, err := notionapi.MarshalProperties(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := json.Marshal(props)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"Email":{"email":null},"Kind":{"select":null},"Link":{"url":null},"Phone":{"phone_number":null},"Status":{"status":null}}`
+		if string(got) != want {
+			t.Errorf("MarshalProperties() = %s, want %s", got, want)
+		}
+
+		v.Link, v.Kind = "x", "y"
+		if err := notionapi.UnmarshalProperties(props, &v); err != nil {
+			t.Fatal(err)
+		}
+		if v.Link != "" || v.Kind != "" {
+			t.Errorf("UnmarshalProperties() Link = %q, Kind = %q, want empty", v.Link, v.Kind)
+		}
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		v := struct {
+			Done bool `notion:"Done,title"`
+		}{}
+		if _, err := notionapi.MarshalProperties(v); err == nil {
+			t.Error("MarshalProperties() error = nil for a bool encoded as a title")
+		}
+	})
+}
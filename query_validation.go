@@ -0,0 +1,369 @@
+package notionapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// QueryProblem is a problem of a database query request found by
+// ValidateQuery.
+type QueryProblem struct {
+	// Path locates the problem in the request, such as filter.and[1] or
+	// sorts[0].
+	Path    string
+	Message string
+}
+
+func (p QueryProblem) String() string {
+	return p.Path + ": " + p.Message
+}
+
+// QueryValidationError is returned by ValidateQuery with every problem of the
+// request. It matches ErrInvalidRequest with errors.Is.
+type QueryValidationError struct {
+	Problems []QueryProblem
+}
+
+func (e *QueryValidationError) Error() string {
+	problems := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		problems[i] = p.String()
+	}
+	return "notionapi: invalid query: " + strings.Join(problems, "; ")
+}
+
+func (e *QueryValidationError) Is(target error) bool {
+	return target == ErrInvalidRequest
+}
+
+// ValidateQuery checks a query request against the schema of the database
+// before sending it, so that mistakes are reported precisely instead of as a
+// validation error of the API. It checks that:
+//   - filtered and sorted properties exist, by name or ID,
+//   - property filters have a single condition, of the type of the property
+//     or rich_text for title, url, email and phone_number properties,
+//   - conditions have a single operator,
+//   - rollup conditions match the result of the rollup function,
+//   - compound filters are not empty nor nested more than MaxFilterNesting
+//     levels deep,
+//   - sorts are on either a property or a timestamp, with a direction,
+//   - the page size is between 1 and 100, if set.
+//
+// Formula conditions are not checked against the configuration of the
+// formula: the schema only gives its expression, not the type of its result,
+// so they are only checked to have a single condition on the result.
+//
+// It returns a *QueryValidationError listing every problem, or nil.
+func ValidateQuery(db *Database, req *DatabaseQueryRequest) error {
+	if db == nil {
+		return errors.New("notionapi: no database to validate the query against")
+	}
+	if req == nil {
+		return nil
+	}
+	v := &queryValidator{properties: db.Properties}
+	if req.Filter != nil {
+		v.filter("filter", req.Filter, 0)
+	}
+	for i, s := range req.Sorts {
+		v.sort(fmt.Sprintf("sorts[%d]", i), s)
+	}
+	if req.PageSize < 0 || req.PageSize > 100 {
+		v.add("page_size", "should be between 1 and 100, got %d", req.PageSize)
+	}
+	if len(v.problems) > 0 {
+		return &QueryValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+type queryValidator struct {
+	properties PropertyConfigs
+	problems   []QueryProblem
+}
+
+func (v *queryValidator) add(path, format string, args ...interface{}) {
+	v.problems = append(v.problems, QueryProblem{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// property returns the configuration of the property with the given name or
+// ID, or nil and a problem if there is none.
+func (v *queryValidator) property(path, nameOrID string) PropertyConfig {
+	if nameOrID == "" {
+		v.add(path, "property is empty")
+		return nil
+	}
+	if config, ok := v.properties[nameOrID]; ok {
+		return config
+	}
+	for _, config := range v.properties {
+		if string(config.GetID()) == nameOrID {
+			return config
+		}
+	}
+	v.add(path, "property %q does not exist", nameOrID)
+	return nil
+}
+
+func (v *queryValidator) filter(path string, f Filter, depth int) {
+	switch f := f.(type) {
+	case AndCompoundFilter:
+		v.compound(path+".and", f, depth)
+	case *AndCompoundFilter:
+		v.compound(path+".and", *f, depth)
+	case OrCompoundFilter:
+		v.compound(path+".or", f, depth)
+	case *OrCompoundFilter:
+		v.compound(path+".or", *f, depth)
+	case PropertyFilter:
+		v.propertyFilter(path, f)
+	case *PropertyFilter:
+		v.propertyFilter(path, *f)
+	case TimestampFilter:
+		v.timestampFilter(path, f)
+	case *TimestampFilter:
+		v.timestampFilter(path, *f)
+	default:
+		v.add(path, "unsupported filter %T", f)
+	}
+}
+
+func (v *queryValidator) compound(path string, filters []Filter, depth int) {
+	if depth >= MaxFilterNesting {
+		v.add(path, "compound filters can only be nested %d levels deep", MaxFilterNesting)
+		return
+	}
+	if len(filters) == 0 {
+		v.add(path, "compound filter is empty")
+	}
+	for i, f := range filters {
+		v.filter(fmt.Sprintf("%s[%d]", path, i), f, depth+1)
+	}
+}
+
+// propertyCondition is a condition of a property filter with the type of
+// property it applies to.
+type propertyCondition struct {
+	key       string
+	kind      PropertyConfigType
+	condition interface{}
+}
+
+// textProperties are the types of property accepting a rich_text condition
+// besides rich_text properties, as Notion filters their text the same way.
+var textProperties = []PropertyConfigType{
+	PropertyConfigTypeTitle,
+	PropertyConfigTypeURL,
+	PropertyConfigTypeEmail,
+	PropertyConfigTypePhoneNumber,
+}
+
+// appliesTo tells whether the condition can be used on a property of the given
+// type.
+func (c propertyCondition) appliesTo(kind PropertyConfigType) bool {
+	if kind == c.kind {
+		return true
+	}
+	if c.kind == PropertyConfigTypeRichText {
+		for _, k := range textProperties {
+			if kind == k {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (v *queryValidator) propertyFilter(path string, f PropertyFilter) {
+	config := v.property(path+".property", f.Property)
+
+	var conditions []propertyCondition
+	for _, c := range []propertyCondition{
+		{"title", PropertyConfigTypeTitle, f.Title},
+		{"rich_text", PropertyConfigTypeRichText, f.RichText},
+		{"url", PropertyConfigTypeURL, f.URL},
+		{"email", PropertyConfigTypeEmail, f.Email},
+		{"phone_number", PropertyConfigTypePhoneNumber, f.PhoneNumber},
+		{"number", PropertyConfigTypeNumber, f.Number},
+		{"checkbox", PropertyConfigTypeCheckbox, f.Checkbox},
+		{"select", PropertyConfigTypeSelect, f.Select},
+		{"multi_select", PropertyConfigTypeMultiSelect, f.MultiSelect},
+		{"status", PropertyConfigStatus, f.Status},
+		{"date", PropertyConfigTypeDate, f.Date},
+		{"created_time", PropertyConfigCreatedTime, f.CreatedTime},
+		{"last_edited_time", PropertyConfigLastEditedTime, f.LastEditedTime},
+		{"people", PropertyConfigTypePeople, f.People},
+		{"created_by", PropertyConfigCreatedBy, f.CreatedBy},
+		{"last_edited_by", PropertyConfigLastEditedBy, f.LastEditedBy},
+		{"files", PropertyConfigTypeFiles, f.Files},
+		{"relation", PropertyConfigTypeRelation, f.Relation},
+		{"formula", PropertyConfigTypeFormula, f.Formula},
+		{"rollup", PropertyConfigTypeRollup, f.Rollup},
+		{"unique_id", PropertyConfigUniqueID, f.UniqueId},
+	} {
+		if !reflect.ValueOf(c.condition).IsNil() {
+			conditions = append(conditions, c)
+		}
+	}
+	if len(conditions) != 1 {
+		v.add(path, "should have exactly one condition, got %d", len(conditions))
+	}
+
+	for _, c := range conditions {
+		condPath := path + "." + c.key
+		// Configurations built without their type are not checked.
+		if config != nil && config.GetType() != "" && !c.appliesTo(config.GetType()) {
+			v.add(condPath, "%s condition cannot be used on %s property %q", c.key, config.GetType(), f.Property)
+			continue
+		}
+		switch cond := c.condition.(type) {
+		case *FormulaFilterCondition:
+			v.formula(condPath, cond)
+		case *RollupFilterCondition:
+			v.rollup(condPath, cond, config)
+		default:
+			v.operators(condPath, cond)
+		}
+	}
+}
+
+// operators checks that a condition has exactly one operator.
+func (v *queryValidator) operators(path string, condition interface{}) {
+	value := reflect.ValueOf(condition).Elem()
+	set := 0
+	for i := 0; i < value.NumField(); i++ {
+		if !value.Field(i).IsZero() {
+			set++
+		}
+	}
+	if set != 1 {
+		v.add(path, "should have exactly one operator, got %d", set)
+	}
+}
+
+// subcondition is a condition nested in a formula or rollup condition.
+type subcondition struct {
+	key       string
+	condition interface{}
+}
+
+// subconditions returns the non-nil conditions nested in a formula or rollup
+// condition, keyed by their JSON name.
+func subconditions(condition interface{}) []subcondition {
+	value := reflect.ValueOf(condition).Elem()
+	var subs []subcondition
+	for i := 0; i < value.NumField(); i++ {
+		if !value.Field(i).IsNil() {
+			key := strings.Split(value.Type().Field(i).Tag.Get("json"), ",")[0]
+			subs = append(subs, subcondition{key: key, condition: value.Field(i).Interface()})
+		}
+	}
+	return subs
+}
+
+func (v *queryValidator) formula(path string, cond *FormulaFilterCondition) {
+	subs := subconditions(cond)
+	if len(subs) != 1 {
+		v.add(path, "should have exactly one condition on the result of the formula, got %d", len(subs))
+		return
+	}
+	v.operators(path+"."+subs[0].key, subs[0].condition)
+}
+
+// rollupResult returns the conditions allowed on the result of a rollup
+// function: any, every and none for arrays, or date or number.
+func rollupResult(function FunctionType) []string {
+	switch function {
+	case "":
+		return nil
+	case FunctionShowOriginal, FunctionShowUnique:
+		return []string{"any", "every", "none"}
+	case FunctionEarliestDate, FunctionLatestDate, FunctionDateRange:
+		return []string{"date"}
+	}
+	return []string{"number"}
+}
+
+func (v *queryValidator) rollup(path string, cond *RollupFilterCondition, config PropertyConfig) {
+	subs := subconditions(cond)
+	if len(subs) != 1 {
+		v.add(path, "should have exactly one of any, every, none, date or number, got %d", len(subs))
+		return
+	}
+	sub := subs[0]
+
+	var function FunctionType
+	switch config := config.(type) {
+	case *RollupPropertyConfig:
+		function = config.Rollup.Function
+	case RollupPropertyConfig:
+		function = config.Rollup.Function
+	}
+	if allowed := rollupResult(function); allowed != nil && !containsString(allowed, sub.key) {
+		v.add(path+"."+sub.key, "%s condition cannot be used on a rollup computing %s", sub.key, function)
+	}
+
+	if values, ok := sub.condition.(*RollupSubfilterCondition); ok {
+		valueSubs := subconditions(values)
+		if len(valueSubs) != 1 {
+			v.add(path+"."+sub.key, "should have exactly one condition on the values of the rollup, got %d", len(valueSubs))
+			return
+		}
+		v.operators(path+"."+sub.key+"."+valueSubs[0].key, valueSubs[0].condition)
+		return
+	}
+	v.operators(path+"."+sub.key, sub.condition)
+}
+
+func (v *queryValidator) timestampFilter(path string, f TimestampFilter) {
+	var cond *DateFilterCondition
+	switch f.Timestamp {
+	case TimestampCreated:
+		cond = f.CreatedTime
+		if f.LastEditedTime != nil {
+			v.add(path+".last_edited_time", "condition does not match the created_time timestamp")
+		}
+	case TimestampLastEdited:
+		cond = f.LastEditedTime
+		if f.CreatedTime != nil {
+			v.add(path+".created_time", "condition does not match the last_edited_time timestamp")
+		}
+	default:
+		v.add(path+".timestamp", "should be created_time or last_edited_time, got %q", f.Timestamp)
+		return
+	}
+	if cond == nil {
+		v.add(path+"."+string(f.Timestamp), "condition is missing")
+		return
+	}
+	v.operators(path+"."+string(f.Timestamp), cond)
+}
+
+func (v *queryValidator) sort(path string, s SortObject) {
+	switch {
+	case s.Property != "" && s.Timestamp != "":
+		v.add(path, "should sort by either a property or a timestamp")
+	case s.Property != "":
+		v.property(path+".property", s.Property)
+	case s.Timestamp != "":
+		if s.Timestamp != TimestampCreated && s.Timestamp != TimestampLastEdited {
+			v.add(path+".timestamp", "should be created_time or last_edited_time, got %q", s.Timestamp)
+		}
+	default:
+		v.add(path, "should sort by a property or a timestamp")
+	}
+	if s.Direction != SortOrderASC && s.Direction != SortOrderDESC {
+		v.add(path+".direction", "should be ascending or descending, got %q", s.Direction)
+	}
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
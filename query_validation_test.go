@@ -0,0 +1,130 @@
+package notionapi_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jomei/notionapi"
+)
+
+func TestValidateQuery(t *testing.T) {
+	db := &notionapi.Database{
+		Properties: notionapi.PropertyConfigs{
+			"Name":   &notionapi.TitlePropertyConfig{ID: "title", Type: notionapi.PropertyConfigTypeTitle},
+			"Status": &notionapi.StatusPropertyConfig{ID: "a%3Bb", Type: notionapi.PropertyConfigStatus},
+			"Points": &notionapi.NumberPropertyConfig{ID: "c%3Dd", Type: notionapi.PropertyConfigTypeNumber},
+			"Total":  &notionapi.FormulaPropertyConfig{ID: "e%3Ff", Type: notionapi.PropertyConfigTypeFormula},
+			"Site":   &notionapi.URLPropertyConfig{ID: "k%3Al", Type: notionapi.PropertyConfigTypeURL},
+			"Email":  &notionapi.EmailPropertyConfig{ID: "m%3An", Type: notionapi.PropertyConfigTypeEmail},
+			"Phone":  &notionapi.PhoneNumberPropertyConfig{ID: "o%3Ap", Type: notionapi.PropertyConfigTypePhoneNumber},
+			"Tags": &notionapi.RollupPropertyConfig{
+				ID:     "g%3Ah",
+				Type:   notionapi.PropertyConfigTypeRollup,
+				Rollup: notionapi.RollupConfig{Function: notionapi.FunctionShowOriginal},
+			},
+			"Count": &notionapi.RollupPropertyConfig{
+				ID:     "i%3Aj",
+				Type:   notionapi.PropertyConfigTypeRollup,
+				Rollup: notionapi.RollupConfig{Function: notionapi.FunctionCountAll},
+			},
+		},
+	}
+	points := 3.0
+
+	t.Run("accepts valid queries", func(t *testing.T) {
+		filter, err := notionapi.Prop("Status").Status().Equals("Done").
+			And(
+				notionapi.Prop("c%3Dd").Number().GreaterThan(1),
+				notionapi.Prop("Total").Formula().String().Contains("x"),
+				notionapi.Prop("Tags").Rollup().Any().Select().Equals("a"),
+				notionapi.Prop("Count").Rollup().Number().Equals(2),
+				notionapi.LastEditedTime().PastWeek(),
+			).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = notionapi.ValidateQuery(db, &notionapi.DatabaseQueryRequest{
+			Filter: filter,
+			Sorts: []notionapi.SortObject{
+				{Property: "Name", Direction: notionapi.SortOrderASC},
+				{Timestamp: notionapi.TimestampCreated, Direction: notionapi.SortOrderDESC},
+			},
+			PageSize: 100,
+		})
+		if err != nil {
+			t.Errorf("ValidateQuery() error = %v", err)
+		}
+	})
+
+	t.Run("reports every problem", func(t *testing.T) {
+		err := notionapi.ValidateQuery(db, &notionapi.DatabaseQueryRequest{
+			Filter: notionapi.AndCompoundFilter{
+				notionapi.PropertyFilter{Property: "Missing", Checkbox: &notionapi.CheckboxFilterCondition{Equals: true}},
+				notionapi.PropertyFilter{Property: "Status", Select: &notionapi.SelectFilterCondition{Equals: "Done"}},
+				notionapi.PropertyFilter{Property: "Points", Number: &notionapi.NumberFilterCondition{GreaterThan: &points, LessThan: &points}},
+				notionapi.OrCompoundFilter{
+					notionapi.PropertyFilter{Property: "Total", Formula: &notionapi.FormulaFilterCondition{}},
+					notionapi.AndCompoundFilter{},
+				},
+				&notionapi.TimestampFilter{Timestamp: notionapi.TimestampCreated},
+				notionapi.PropertyFilter{Property: "Count", Rollup: &notionapi.RollupFilterCondition{
+					Any: &notionapi.RollupSubfilterCondition{Number: &notionapi.NumberFilterCondition{Equals: &points}},
+				}},
+			},
+			Sorts: []notionapi.SortObject{
+				{Property: "Unknown", Direction: notionapi.SortOrderASC},
+				{Direction: "up"},
+			},
+			PageSize: 101,
+		})
+
+		var validationErr *notionapi.QueryValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("ValidateQuery() error = %v, want a *QueryValidationError", err)
+		}
+		if !errors.Is(err, notionapi.ErrInvalidRequest) {
+			t.Errorf("ValidateQuery() error does not match ErrInvalidRequest")
+		}
+		var got []string
+		for _, p := range validationErr.Problems {
+			got = append(got, p.Path)
+		}
+		want := []string{
+			"filter.and[0].property",
+			"filter.and[1].select",
+			"filter.and[2].number",
+			"filter.and[3].or[0].formula",
+			"filter.and[3].or[1].and",
+			"filter.and[4].created_time",
+			"filter.and[5].rollup.any",
+			"sorts[0].property",
+			"sorts[1]",
+			"sorts[1].direction",
+			"page_size",
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ValidateQuery() problems at %v, want %v\n%v", got, want, err)
+		}
+	})
+
+	for _, property := range []string{"Name", "Site", "Email", "Phone"} {
+		t.Run("accepts rich_text conditions on "+property, func(t *testing.T) {
+			err := notionapi.ValidateQuery(db, &notionapi.DatabaseQueryRequest{
+				Filter: notionapi.PropertyFilter{Property: property, RichText: &notionapi.TextFilterCondition{Contains: "x"}},
+			})
+			if err != nil {
+				t.Errorf("ValidateQuery() error = %v", err)
+			}
+		})
+	}
+
+	t.Run("rejects rich_text conditions on other properties", func(t *testing.T) {
+		err := notionapi.ValidateQuery(db, &notionapi.DatabaseQueryRequest{
+			Filter: notionapi.PropertyFilter{Property: "Points", RichText: &notionapi.TextFilterCondition{Contains: "x"}},
+		})
+		if err == nil {
+			t.Error("ValidateQuery() error = nil")
+		}
+	})
+}
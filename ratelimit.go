@@ -0,0 +1,166 @@
+package notionapi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultRequestsPerSecond is the average rate of requests allowed by Notion
+// for an integration.
+//
+// See https://developers.notion.com/reference/request-limits#rate-limits
+const DefaultRequestsPerSecond = 3
+
+// RateLimiter is a token bucket limiting the rate of requests sent to the API.
+// It is safe for concurrent use, and may be shared by several clients using the
+// same token so that their requests count against the same limit.
+//
+// When a response with the 429 status code and a Retry-After header is
+// received, requests are paused for the delay asked by Notion.
+type RateLimiter struct {
+	mu    sync.Mutex
+	rate  float64
+	burst float64
+	// tokens is the number of tokens available at last, negative when
+	// requests are waiting for tokens.
+	tokens float64
+	// last is the time tokens were last updated. It is in the future while
+	// requests are paused.
+	last time.Time
+	// pauses counts the calls to Pause, so that waiting requests can tell
+	// they must wait again.
+	pauses int
+
+	// now and sleep are the clock of the limiter, replaced in tests.
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRateLimiter returns a RateLimiter allowing rate requests per second on
+// average, with bursts of up to burst requests. A rate of zero or less is
+// replaced by DefaultRequestsPerSecond.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if rate <= 0 {
+		rate = DefaultRequestsPerSecond
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		now:    time.Now,
+		sleep:  sleep,
+	}
+}
+
+// sleep waits for d, or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+var (
+	sharedRateLimitersMu sync.Mutex
+	sharedRateLimiters   = map[Token]*RateLimiter{}
+)
+
+// SharedRateLimiter returns the RateLimiter of the process for the token,
+// created with DefaultRequestsPerSecond on first use. Clients using the same
+// token should use it with WithRateLimiter so that they don't exceed the rate
+// limit together.
+func SharedRateLimiter(token Token) *RateLimiter {
+	sharedRateLimitersMu.Lock()
+	defer sharedRateLimitersMu.Unlock()
+	l, ok := sharedRateLimiters[token]
+	if !ok {
+		l = NewRateLimiter(DefaultRequestsPerSecond, DefaultRequestsPerSecond)
+		sharedRateLimiters[token] = l
+	}
+	return l
+}
+
+// Wait blocks until a request may be sent, or until the context is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		wait, pauses := l.reserve()
+		if wait > 0 {
+			if err := l.sleep(ctx, wait); err != nil {
+				l.cancel()
+				return err
+			}
+		}
+		l.mu.Lock()
+		paused := l.pauses != pauses
+		if paused {
+			// The token is reserved again after the pause.
+			l.tokens++
+		}
+		l.mu.Unlock()
+		if !paused {
+			return nil
+		}
+	}
+}
+
+// cancel gives back a token reserved by a request that is not sent, so that
+// it does not delay the following ones.
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens++
+}
+
+// reserve takes a token and returns how long to wait before using it.
+func (l *RateLimiter) reserve() (time.Duration, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if now.After(l.last) {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+	}
+	l.tokens--
+	wait := l.last.Sub(now)
+	if l.tokens < 0 {
+		wait += time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	return wait, l.pauses
+}
+
+// Pause holds requests for the given duration. Requests already waiting are
+// delayed after the pause, and no burst is allowed once it is over.
+func (l *RateLimiter) Pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := l.now().Add(d); until.After(l.last) {
+		l.last = until
+	}
+	if l.tokens > 1 {
+		l.tokens = 1
+	}
+	l.pauses++
+}
+
+// observe pauses requests after a rate limited response.
+func (l *RateLimiter) observe(res *http.Response) {
+	if res.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+	if wait, ok := retryAfter(res); ok {
+		l.Pause(wait)
+	}
+}
@@ -0,0 +1,203 @@
+package notionapi_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+)
+
+// fakeClock is a clock whose time only advances when sleeping.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	return nil
+}
+
+func TestRateLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("spaces requests after the burst", func(t *testing.T) {
+		clock := newFakeClock()
+		l := notionapi.NewRateLimiter(100, 2)
+		notionapi.SetRateLimiterClock(l, clock.Now, clock.Sleep)
+		start := clock.Now()
+		var got []time.Duration
+		for i := 0; i < 6; i++ {
+			if err := l.Wait(ctx); err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, clock.Now().Sub(start))
+		}
+		want := []time.Duration{0, 0, 10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond, 40 * time.Millisecond}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Wait() let requests through after %v, want %v", got, want)
+		}
+	})
+
+	t.Run("pauses requests", func(t *testing.T) {
+		clock := newFakeClock()
+		l := notionapi.NewRateLimiter(1000, 10)
+		notionapi.SetRateLimiterClock(l, clock.Now, clock.Sleep)
+		start := clock.Now()
+		l.Pause(50 * time.Millisecond)
+		if err := l.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if elapsed := clock.Now().Sub(start); elapsed != 50*time.Millisecond {
+			t.Errorf("Wait() returned after %v, want 50ms", elapsed)
+		}
+	})
+
+	t.Run("delays waiting requests once after a pause", func(t *testing.T) {
+		clock := newFakeClock()
+		l := notionapi.NewRateLimiter(100, 1)
+		paused := false
+		notionapi.SetRateLimiterClock(l, clock.Now, func(ctx context.Context, d time.Duration) error {
+			// A response asks to pause while the second request waits.
+			if !paused {
+				paused = true
+				l.Pause(50 * time.Millisecond)
+			}
+			return clock.Sleep(ctx, d)
+		})
+		start := clock.Now()
+		var got []time.Duration
+		for i := 0; i < 3; i++ {
+			if err := l.Wait(ctx); err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, clock.Now().Sub(start))
+		}
+		want := []time.Duration{0, 60 * time.Millisecond, 70 * time.Millisecond}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Wait() let requests through after %v, want %v", got, want)
+		}
+	})
+
+	t.Run("gives back the token of cancelled requests", func(t *testing.T) {
+		clock := newFakeClock()
+		l := notionapi.NewRateLimiter(100, 1)
+		notionapi.SetRateLimiterClock(l, clock.Now, clock.Sleep)
+		start := clock.Now()
+		if err := l.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		for i := 0; i < 3; i++ {
+			if err := l.Wait(cancelled); err != context.Canceled {
+				t.Fatalf("Wait() error = %v, want %v", err, context.Canceled)
+			}
+		}
+		if err := l.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if elapsed := clock.Now().Sub(start); elapsed != 10*time.Millisecond {
+			t.Errorf("Wait() after cancelled requests returned after %v, want 10ms", elapsed)
+		}
+	})
+
+	t.Run("replaces invalid rates", func(t *testing.T) {
+		clock := newFakeClock()
+		l := notionapi.NewRateLimiter(0, 1)
+		notionapi.SetRateLimiterClock(l, clock.Now, clock.Sleep)
+		start := clock.Now()
+		for i := 0; i < 2; i++ {
+			if err := l.Wait(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if elapsed, want := clock.Now().Sub(start), time.Second/notionapi.DefaultRequestsPerSecond; elapsed != want {
+			t.Errorf("Wait() with a rate of 0 returned after %v, want %v", elapsed, want)
+		}
+	})
+
+	t.Run("stops waiting when the context is done", func(t *testing.T) {
+		l := notionapi.NewRateLimiter(1, 1)
+		l.Pause(time.Hour)
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		if err := l.Wait(ctx); err != context.DeadlineExceeded {
+			t.Errorf("Wait() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("is shared by token", func(t *testing.T) {
+		if notionapi.SharedRateLimiter("a") != notionapi.SharedRateLimiter("a") {
+			t.Error("SharedRateLimiter() returned different limiters for the same token")
+		}
+		if notionapi.SharedRateLimiter("a") == notionapi.SharedRateLimiter("b") {
+			t.Error("SharedRateLimiter() returned the same limiter for different tokens")
+		}
+	})
+}
+
+func TestWithRateLimiter(t *testing.T) {
+	clock := newFakeClock()
+	var times []time.Duration
+	start := clock.Now()
+	c := newTestClient(func(req *http.Request) *http.Response {
+		times = append(times, clock.Now().Sub(start))
+		if len(times) == 1 {
+			return &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{"1"}},
+				Body:       ioutil.NopCloser(strings.NewReader(`{"object":"error","status":429,"code":"rate_limited"}`)),
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       ioutil.NopCloser(strings.NewReader(`{"object":"user","id":"some_id","type":"bot","bot":{}}`)),
+		}
+	})
+
+	limiter := notionapi.NewRateLimiter(50, 1)
+	notionapi.SetRateLimiterClock(limiter, clock.Now, clock.Sleep)
+	clients := []*notionapi.Client{
+		// The retry waits for the pause of the limiter only.
+		notionapi.NewClient("some_token", notionapi.WithHTTPClient(c), notionapi.WithRateLimiter(limiter),
+			notionapi.WithRetryPolicy(retryFunc(func(attempt int, req *http.Request, res *http.Response, err error) (time.Duration, bool) {
+				return 0, attempt == 1
+			}))),
+		notionapi.NewClient("some_token", notionapi.WithHTTPClient(c), notionapi.WithRateLimiter(limiter)),
+	}
+	for i := 0; i < 4; i++ {
+		if _, err := clients[i%2].User.Me(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The first request is rate limited for a second, and the requests of both
+	// clients are then spaced by 20ms from the end of the pause.
+	want := []time.Duration{0, time.Second + 20*time.Millisecond, time.Second + 40*time.Millisecond, time.Second + 60*time.Millisecond, time.Second + 80*time.Millisecond}
+	if !reflect.DeepEqual(times, want) {
+		t.Errorf("clients sent requests after %v, want %v", times, want)
+	}
+}
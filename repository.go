@@ -0,0 +1,132 @@
+package notionapi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Repository reads and writes the pages of a database as values of T, a
+// struct whose fields are tagged as for UnmarshalPage and MarshalProperties:
+//
+//	type Task struct {
+//		ID     notionapi.PageID `notion:",id"`
+//		Name   string           `notion:"Name,title"`
+//		Status string           `notion:"Status,status,omitempty"`
+//	}
+//
+//	tasks := notionapi.NewRepository[Task](client, "your_database_id")
+//	task, err := tasks.Insert(ctx, Task{Name: "Write docs", Status: "Not started"})
+//
+// Read-only properties, such as formulas or the creation time, are decoded
+// but not written.
+type Repository[T any] struct {
+	database DatabaseID
+	dbs      DatabaseService
+	pages    PageService
+}
+
+// NewRepository returns a repository of the pages of the database, using the
+// database and page services of the client.
+func NewRepository[T any](client *Client, database DatabaseID) *Repository[T] {
+	return &Repository[T]{database: database, dbs: client.Database, pages: client.Page}
+}
+
+// DatabaseID returns the ID of the database of the repository.
+func (r *Repository[T]) DatabaseID() DatabaseID {
+	return r.database
+}
+
+// Find returns every page matching the filter, in the order of the sorts,
+// following pagination. A nil filter matches every page.
+func (r *Repository[T]) Find(ctx context.Context, filter Filter, sorts ...SortObject) ([]T, error) {
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+	it := r.dbs.QueryAll(ctx, r.database, &DatabaseQueryRequest{Filter: filter, Sorts: sorts})
+	var values []T
+	for it.Next() {
+		v, err := r.decode(it.Value())
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, *v)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// All returns every page of the database, following pagination.
+func (r *Repository[T]) All(ctx context.Context) ([]T, error) {
+	return r.Find(ctx, nil)
+}
+
+// Get returns the page with the given ID.
+func (r *Repository[T]) Get(ctx context.Context, id PageID) (*T, error) {
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+	page, err := r.pages.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.decode(page)
+}
+
+// Insert creates a page in the database from v and returns it as created,
+// with its ID and computed properties.
+func (r *Repository[T]) Insert(ctx context.Context, v T) (*T, error) {
+	props, err := MarshalProperties(v)
+	if err != nil {
+		return nil, err
+	}
+	page, err := r.pages.Create(ctx, &PageCreateRequest{
+		Parent:     Parent{Type: ParentTypeDatabaseID, DatabaseID: r.database},
+		Properties: props,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.decode(page)
+}
+
+// Update sets the properties of the page with the given ID to the fields of
+// v and returns the updated page. Fields with the omitempty option and nil
+// pointers leave their properties unchanged.
+func (r *Repository[T]) Update(ctx context.Context, id PageID, v T) (*T, error) {
+	props, err := MarshalProperties(v)
+	if err != nil {
+		return nil, err
+	}
+	page, err := r.pages.Update(ctx, id, &PageUpdateRequest{Properties: props})
+	if err != nil {
+		return nil, err
+	}
+	return r.decode(page)
+}
+
+// Archive archives the page with the given ID.
+func (r *Repository[T]) Archive(ctx context.Context, id PageID) error {
+	_, err := r.pages.Update(ctx, id, &PageUpdateRequest{Archived: true})
+	return err
+}
+
+func (r *Repository[T]) decode(page *Page) (*T, error) {
+	v := new(T)
+	if err := UnmarshalPage(page, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// check reports the types that cannot be decoded before making requests.
+func (r *Repository[T]) check() error {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("notionapi: cannot use %s in a repository, want a struct", t)
+	}
+	_, err := structFields(t)
+	return err
+}
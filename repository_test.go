@@ -0,0 +1,131 @@
+package notionapi_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jomei/notionapi"
+	"github.com/jomei/notionapi/notionapitest"
+)
+
+type repoTask struct {
+	ID     notionapi.PageID `notion:",id"`
+	Name   string           `notion:"Name,title"`
+	Status string           `notion:"Status,status,omitempty"`
+	Points float64          `notion:"Points"`
+}
+
+func TestRepository(t *testing.T) {
+	ctx := context.Background()
+	srv := notionapitest.NewServer()
+	defer srv.Close()
+
+	var requests []string
+	client := srv.Client(notionapi.WithMiddleware(func(next notionapi.Handler) notionapi.Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			var body []byte
+			if req.Body != nil {
+				body, _ = ioutil.ReadAll(req.Body)
+				req.Body = ioutil.NopCloser(bytes.NewReader(body))
+			}
+			requests = append(requests, strings.TrimSpace(req.Method+" "+req.URL.Path+" "+string(body)))
+			return next(req)
+		}
+	}))
+	db, err := client.Database.Create(ctx, &notionapi.DatabaseCreateRequest{
+		Parent: notionapi.Parent{Type: notionapi.ParentTypePageID, PageID: srv.RootPageID()},
+		Title:  richText("Tasks"),
+		Properties: notionapi.PropertyConfigs{
+			"Name":   notionapi.TitlePropertyConfig{Type: notionapi.PropertyConfigTypeTitle},
+			"Status": notionapi.StatusPropertyConfig{Type: notionapi.PropertyConfigStatus},
+			"Points": notionapi.NumberPropertyConfig{Type: notionapi.PropertyConfigTypeNumber},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks := notionapi.NewRepository[repoTask](client, notionapi.DatabaseID(db.ID))
+
+	first, err := tasks.Insert(ctx, repoTask{Name: "Write docs", Status: "Not started", Points: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ID == "" {
+		t.Fatalf("Insert() = %+v, want the ID of the page", first)
+	}
+	want := &repoTask{ID: first.ID, Name: "Write docs", Status: "Not started", Points: 2}
+	if !reflect.DeepEqual(first, want) {
+		t.Errorf("Insert() = %+v, want %+v", first, want)
+	}
+	second, err := tasks.Insert(ctx, repoTask{Name: "Ship"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := tasks.Update(ctx, first.ID, repoTask{Name: "Write docs", Status: "Done", Points: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Status != "Done" || updated.Points != 3 {
+		t.Errorf("Update() = %+v", updated)
+	}
+
+	got, err := tasks.Get(ctx, first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, updated) {
+		t.Errorf("Get() = %+v, want %+v", got, updated)
+	}
+
+	filter, _ := notionapi.Prop("Status").Status().Equals("Done").Build()
+	requests = nil
+	done, err := tasks.Find(ctx, filter, notionapi.SortObject{Property: "Points", Direction: notionapi.SortOrderDESC})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 1 || done[0].ID != first.ID {
+		t.Errorf("Find() = %+v, want the done task", done)
+	}
+	wantQuery := fmt.Sprintf(`POST /v1/databases/%s/query {"sorts":[{"property":"Points","direction":"descending"}],"filter":{"property":"Status","status":{"equals":"Done"}}}`, db.ID)
+	if len(requests) != 1 || requests[0] != wantQuery {
+		t.Errorf("Find() requests = %v, want %s", requests, wantQuery)
+	}
+
+	if err := tasks.Archive(ctx, second.ID); err != nil {
+		t.Fatal(err)
+	}
+	all, err := tasks.All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[0].Name != "Write docs" {
+		t.Errorf("All() after Archive() = %+v, want the first task", all)
+	}
+
+	// All follows pagination.
+	for i := 0; i < 100; i++ {
+		if _, err := tasks.Insert(ctx, repoTask{Name: fmt.Sprint("Task ", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	requests = nil
+	all, err = tasks.All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 101 || len(requests) != 2 {
+		t.Errorf("All() = %d tasks in %d requests, want 101 tasks in 2 requests", len(all), len(requests))
+	}
+
+	if _, err := tasks.Get(ctx, "00000000-0000-4000-8000-000000000999"); !errors.Is(err, notionapi.ErrNotFound) {
+		t.Errorf("Get() error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,802 @@
+package notionapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// RestoreOptions configures Restore.
+type RestoreOptions struct {
+	// Parent is the page under which the pages and databases of the backup
+	// whose parent is not in the backup are recreated.
+	Parent PageID
+	// SkipComments does not recreate comments.
+	SkipComments bool
+	// SkipFiles does not upload the files of the backup. Files hosted by
+	// Notion are then left out, as their URLs have expired.
+	SkipFiles bool
+}
+
+// RestoreResult is the outcome of Restore.
+type RestoreResult struct {
+	// IDs maps the IDs of the pages, databases and blocks of the backup to
+	// the IDs of their copies.
+	IDs map[string]string
+	// Warnings lists what could not be restored as it was.
+	Warnings []string
+}
+
+// Restore recreates the pages, databases, blocks and comments of a backup
+// under a parent page, and returns the IDs of the copies.
+//
+// Pages and databases keep their hierarchy: pages nested in blocks are
+// recreated at the end of the page holding the block. Relations, in
+// properties of databases and in values of pages, are remapped to the
+// copies of the related databases and pages, and kept as they are for
+// those which are not in the backup.
+//
+// Some of the content of the backup cannot be recreated through the API:
+//   - status properties are recreated as select properties;
+//   - unique ID, verification and button properties, and values computed by
+//     Notion, such as formulas and rollups, are left out;
+//   - relations are recreated as one-way relations;
+//   - comments keep their discussions, but are attached to their page, and
+//     are authored by the integration;
+//   - child page, child database and link preview blocks are left out, as
+//     are blocks unsupported by the API;
+//   - link to page blocks and mentions in rich text keep pointing to the
+//     original pages and databases, and are listed in the warnings.
+func Restore(ctx context.Context, client *Client, archive *BackupArchive, opts RestoreOptions) (*RestoreResult, error) {
+	if opts.Parent == "" {
+		return nil, fmt.Errorf("notionapi: no parent page to restore to")
+	}
+	r := newRestorer(client, archive, opts)
+	for _, key := range r.children[""] {
+		if err := r.restore(ctx, key, Parent{Type: ParentTypePageID, PageID: opts.Parent}); err != nil {
+			return r.result, err
+		}
+	}
+	if err := r.relations(ctx); err != nil {
+		return r.result, err
+	}
+	if !opts.SkipComments {
+		if err := r.comments(ctx); err != nil {
+			return r.result, err
+		}
+	}
+	return r.result, nil
+}
+
+type restorer struct {
+	client  *Client
+	archive *BackupArchive
+	opts    RestoreOptions
+	result  *RestoreResult
+
+	pages     map[string]*PageBackup
+	databases map[string]*DatabaseBackup
+	// children lists the pages and databases of each page or database, and of
+	// the root under the empty key.
+	children map[string][]string
+	// files maps the files of the backup to their path.
+	files map[FileRef]string
+
+	// Relations set once all the databases and pages are created.
+	databaseRelations []databaseRelations
+	pageRelations     []pageRelations
+}
+
+// databaseRelations are the relations of a database, by name, and the
+// rollups over them.
+type databaseRelations struct {
+	id        DatabaseID
+	relations map[string]DatabaseID
+	rollups   PropertyConfigs
+}
+
+// pageRelations are the relations of a page, by name.
+type pageRelations struct {
+	id        PageID
+	relations map[string][]Relation
+}
+
+func newRestorer(client *Client, archive *BackupArchive, opts RestoreOptions) *restorer {
+	r := &restorer{
+		client:    client,
+		archive:   archive,
+		opts:      opts,
+		result:    &RestoreResult{IDs: map[string]string{}},
+		pages:     map[string]*PageBackup{},
+		databases: map[string]*DatabaseBackup{},
+		children:  map[string][]string{},
+		files:     map[FileRef]string{},
+	}
+
+	blockPages := map[string]string{}
+	for _, db := range archive.Databases {
+		r.databases[db.Database.ID.String()] = db
+		r.addFiles(db.Files)
+	}
+	for _, p := range archive.Pages {
+		r.pages[p.Page.ID.String()] = p
+		r.addFiles(p.Files)
+		walkBlocks(p.Blocks, func(b Block) {
+			blockPages[b.GetID().String()] = p.Page.ID.String()
+		})
+	}
+
+	parentKey := func(parent Parent) string {
+		var id string
+		switch {
+		case parent.PageID != "":
+			id = parent.PageID.String()
+		case parent.DatabaseID != "":
+			id = parent.DatabaseID.String()
+		case parent.BlockID != "":
+			id = blockPages[parent.BlockID.String()]
+		}
+		if r.pages[id] == nil && r.databases[id] == nil {
+			return ""
+		}
+		return id
+	}
+	for _, db := range archive.Databases {
+		key := parentKey(db.Database.Parent)
+		r.children[key] = append(r.children[key], db.Database.ID.String())
+	}
+	for _, p := range archive.Pages {
+		key := parentKey(p.Page.Parent)
+		r.children[key] = append(r.children[key], p.Page.ID.String())
+	}
+	return r
+}
+
+func (r *restorer) addFiles(files []BackupFile) {
+	for _, f := range files {
+		r.files[fileKey(f.FileRef)] = f.Path
+	}
+}
+
+// fileKey identifies a file by its location only.
+func fileKey(ref FileRef) FileRef {
+	return FileRef{Source: ref.Source, BlockID: ref.BlockID, PageID: ref.PageID, DatabaseID: ref.DatabaseID, Property: ref.Property, Index: ref.Index}
+}
+
+func (r *restorer) warnf(format string, args ...interface{}) {
+	r.result.Warnings = append(r.result.Warnings, fmt.Sprintf(format, args...))
+}
+
+// restore creates a page or a database, then its children.
+func (r *restorer) restore(ctx context.Context, key string, parent Parent) error {
+	var childParent Parent
+	if db := r.databases[key]; db != nil {
+		id, err := r.database(ctx, db, parent)
+		if err != nil {
+			return fmt.Errorf("notionapi: cannot restore database %s: %w", key, err)
+		}
+		childParent = Parent{Type: ParentTypeDatabaseID, DatabaseID: DatabaseID(id)}
+	} else {
+		id, err := r.page(ctx, r.pages[key], parent)
+		if err != nil {
+			return fmt.Errorf("notionapi: cannot restore page %s: %w", key, err)
+		}
+		childParent = Parent{Type: ParentTypePageID, PageID: PageID(id)}
+	}
+
+	for _, child := range r.children[key] {
+		if err := r.restore(ctx, child, childParent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *restorer) database(ctx context.Context, backup *DatabaseBackup, parent Parent) (string, error) {
+	db := backup.Database
+	id := db.ID.String()
+	properties := PropertyConfigs{}
+	deferred := databaseRelations{relations: map[string]DatabaseID{}, rollups: PropertyConfigs{}}
+	for _, name := range sortedPropertyNames(db.Properties) {
+		config := db.Properties[name]
+		t, err := propertyConfigType(config)
+		if err != nil {
+			return "", fmt.Errorf("property %q: %w", name, err)
+		}
+		switch t {
+		case PropertyConfigTypeRelation:
+			relation := config.(*RelationPropertyConfig).Relation
+			if relation.DualProperty != nil {
+				r.warnf("relation %q of database %s is restored as a one-way relation", name, id)
+			}
+			deferred.relations[name] = relation.DatabaseID
+		case PropertyConfigTypeRollup:
+			rollup := config.(*RollupPropertyConfig).Rollup
+			deferred.rollups[name] = &RollupPropertyConfig{Type: t, Rollup: RollupConfig{
+				RelationPropertyName: rollup.RelationPropertyName,
+				RollupPropertyName:   rollup.RollupPropertyName,
+				Function:             rollup.Function,
+			}}
+		case PropertyConfigStatus:
+			r.warnf("status property %q of database %s is restored as a select property", name, id)
+			properties[name] = &SelectPropertyConfig{Type: PropertyConfigTypeSelect, Select: Select{Options: restoredOptions(config.(*StatusPropertyConfig).Status.Options)}}
+		case PropertyConfigUniqueID, PropertyConfigVerification, PropertyConfigButton:
+			r.warnf("%s property %q of database %s cannot be restored", t, name, id)
+		default:
+			config = normalizePropertyConfig(config, t)
+			switch c := config.(type) {
+			case *SelectPropertyConfig:
+				c.Select.Options = restoredOptions(c.Select.Options)
+			case *MultiSelectPropertyConfig:
+				c.MultiSelect.Options = restoredOptions(c.MultiSelect.Options)
+			}
+			properties[name] = config
+		}
+	}
+
+	created, err := r.client.Database.Create(ctx, &DatabaseCreateRequest{
+		Parent:     parent,
+		Title:      db.Title,
+		Properties: properties,
+		IsInline:   db.IsInline,
+	})
+	if err != nil {
+		return "", err
+	}
+	newID := created.ID.String()
+	r.result.IDs[id] = newID
+
+	icon, err := r.icon(ctx, db.Icon, FileRef{Source: FileSourceIcon, DatabaseID: DatabaseID(id)})
+	if err != nil {
+		return "", err
+	}
+	cover, err := r.cover(ctx, db.Cover, FileRef{Source: FileSourceCover, DatabaseID: DatabaseID(id)})
+	if err != nil {
+		return "", err
+	}
+	if icon != nil || cover != nil || len(db.Description) > 0 {
+		update := &DatabaseUpdateRequest{Icon: icon, Cover: cover}
+		if len(db.Description) > 0 {
+			update.Description = db.Description
+		}
+		if _, err := r.client.Database.Update(ctx, DatabaseID(newID), update); err != nil {
+			return "", err
+		}
+	}
+
+	if len(deferred.relations) > 0 || len(deferred.rollups) > 0 {
+		deferred.id = DatabaseID(newID)
+		r.databaseRelations = append(r.databaseRelations, deferred)
+	}
+	return newID, nil
+}
+
+// restoredOptions returns options without their IDs, which belong to the
+// backed up database.
+func restoredOptions(options []Option) []Option {
+	restored := make([]Option, len(options))
+	for i, o := range options {
+		restored[i] = Option{Name: o.Name, Color: o.Color}
+	}
+	return restored
+}
+
+func (r *restorer) page(ctx context.Context, backup *PageBackup, parent Parent) (string, error) {
+	page := backup.Page
+	id := page.ID.String()
+	properties := Properties{}
+	relations := map[string][]Relation{}
+	for _, name := range sortedPageProperties(page.Properties) {
+		prop := page.Properties[name]
+		t := prop.GetType()
+		if parent.Type != ParentTypeDatabaseID && t != PropertyTypeTitle {
+			continue
+		}
+		if readOnlyPropertyTypes[t] || isEmptyProperty(prop) {
+			continue
+		}
+		if parent.Type != ParentTypeDatabaseID {
+			// The title of pages under a page is always named title, even
+			// when the database of the original is not in the backup.
+			name = "title"
+		}
+		switch p := prop.(type) {
+		case *RelationProperty:
+			relations[name] = p.Relation
+		case *StatusProperty:
+			properties[name] = SelectProperty{Type: PropertyTypeSelect, Select: Option{Name: p.Status.Name}}
+		case *SelectProperty:
+			properties[name] = SelectProperty{Type: t, Select: Option{Name: p.Select.Name}}
+		case *MultiSelectProperty:
+			properties[name] = MultiSelectProperty{Type: t, MultiSelect: restoredOptions(p.MultiSelect)}
+		case *FilesProperty:
+			files, err := r.propertyFiles(ctx, id, name, p.Files)
+			if err != nil {
+				return "", err
+			}
+			properties[name] = FilesProperty{Type: t, Files: files}
+		default:
+			properties[name] = prop
+		}
+	}
+
+	if err := r.warnLinks("page "+id, properties); err != nil {
+		return "", err
+	}
+
+	icon, err := r.icon(ctx, page.Icon, FileRef{Source: FileSourceIcon, PageID: PageID(id)})
+	if err != nil {
+		return "", err
+	}
+	cover, err := r.cover(ctx, page.Cover, FileRef{Source: FileSourceCover, PageID: PageID(id)})
+	if err != nil {
+		return "", err
+	}
+	created, err := r.client.Page.Create(ctx, &PageCreateRequest{
+		Parent:     parent,
+		Properties: properties,
+		Icon:       icon,
+		Cover:      cover,
+	})
+	if err != nil {
+		return "", err
+	}
+	newID := created.ID.String()
+	r.result.IDs[id] = newID
+
+	if err := r.blocks(ctx, BlockID(newID), backup.Blocks); err != nil {
+		return "", err
+	}
+	if len(relations) > 0 {
+		r.pageRelations = append(r.pageRelations, pageRelations{id: PageID(newID), relations: relations})
+	}
+	return newID, nil
+}
+
+func sortedPageProperties(properties Properties) []string {
+	configs := make(PropertyConfigs, len(properties))
+	for name := range properties {
+		configs[name] = nil
+	}
+	return sortedPropertyNames(configs)
+}
+
+// upload uploads a file of the backup, and returns nil if it was not
+// downloaded.
+func (r *restorer) upload(ctx context.Context, ref FileRef) (*FileUploadObject, error) {
+	path, ok := r.files[fileKey(ref)]
+	if !ok || r.opts.SkipFiles {
+		return nil, nil
+	}
+	f, err := os.Open(filepath.Join(r.archive.Dir, filepath.FromSlash(path)))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	upload, err := r.client.FileUpload.Upload(ctx, filepath.Base(path), f, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot upload %s: %w", path, err)
+	}
+	return &FileUploadObject{ID: upload.ID}, nil
+}
+
+func (r *restorer) icon(ctx context.Context, icon *Icon, ref FileRef) (*Icon, error) {
+	if icon == nil || icon.File == nil {
+		return icon, nil
+	}
+	upload, err := r.upload(ctx, ref)
+	if err != nil || upload == nil {
+		if err == nil {
+			r.warnf("icon of %s was not downloaded", ref.ownerID())
+		}
+		return nil, err
+	}
+	return &Icon{Type: FileTypeFileUpload, FileUpload: upload}, nil
+}
+
+func (r *restorer) cover(ctx context.Context, cover *Image, ref FileRef) (*Image, error) {
+	if cover == nil || cover.File == nil {
+		return cover, nil
+	}
+	upload, err := r.upload(ctx, ref)
+	if err != nil || upload == nil {
+		if err == nil {
+			r.warnf("cover of %s was not downloaded", ref.ownerID())
+		}
+		return nil, err
+	}
+	return &Image{Type: FileTypeFileUpload, FileUpload: upload}, nil
+}
+
+func (r *restorer) propertyFiles(ctx context.Context, pageID, name string, files []File) ([]File, error) {
+	restored := make([]File, 0, len(files))
+	for i, f := range files {
+		if f.File == nil {
+			restored = append(restored, f)
+			continue
+		}
+		upload, err := r.upload(ctx, FileRef{Source: FileSourceProperty, PageID: PageID(pageID), Property: name, Index: i})
+		if err != nil {
+			return nil, err
+		}
+		if upload == nil {
+			r.warnf("file %q of property %q of page %s was not downloaded", f.Name, name, pageID)
+			continue
+		}
+		restored = append(restored, File{Name: f.Name, Type: FileTypeFileUpload, FileUpload: upload})
+	}
+	return restored, nil
+}
+
+// maxAppendedBlocks is the maximum number of blocks of an append request.
+const maxAppendedBlocks = 100
+
+// blocks appends the blocks and their children to a block or a page.
+func (r *restorer) blocks(ctx context.Context, parent BlockID, blocks Blocks) error {
+	for len(blocks) > 0 {
+		n := len(blocks)
+		if n > maxAppendedBlocks {
+			n = maxAppendedBlocks
+		}
+		var created Blocks
+		var originals []createdBlock
+		for _, b := range blocks[:n] {
+			restored, children, err := r.block(ctx, b)
+			if err != nil {
+				return err
+			}
+			if restored != nil {
+				created = append(created, restored)
+				originals = append(originals, createdBlock{original: b, children: children})
+			}
+		}
+		blocks = blocks[n:]
+		if len(created) == 0 {
+			continue
+		}
+
+		res, err := r.client.Block.AppendChildren(ctx, parent, &AppendBlockChildrenRequest{Children: created})
+		if err != nil {
+			return err
+		}
+		if len(res.Results) != len(originals) {
+			return fmt.Errorf("notionapi: %d blocks appended to %s, want %d", len(res.Results), parent, len(originals))
+		}
+		for i, b := range originals {
+			if err := r.blockChildren(ctx, b, res.Results[i].GetID()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// createdBlock is a block of the backup which was created, with those of its
+// children which were created along with it, in order.
+type createdBlock struct {
+	original Block
+	children []createdBlock
+}
+
+// blockChildren records the ID of the copy of a block and appends its
+// children, except those created along with it.
+func (r *restorer) blockChildren(ctx context.Context, b createdBlock, id BlockID) error {
+	r.result.IDs[b.original.GetID().String()] = id.String()
+	children := blockChildren(b.original)
+	switch b.original.(type) {
+	case *ColumnListBlock:
+		// Columns are created with the list, and their first blocks with them.
+		columns, err := r.client.Block.GetAllChildren(ctx, id).All()
+		if err != nil {
+			return err
+		}
+		for i, column := range b.children {
+			if i >= len(columns) {
+				break
+			}
+			r.result.IDs[column.original.GetID().String()] = columns[i].GetID().String()
+			if err := r.createdChildren(ctx, column, columns[i].GetID()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *TableBlock:
+		// Rows are created with the table, up to the maximum of a request.
+		if len(children) > maxAppendedBlocks {
+			return r.blocks(ctx, id, children[maxAppendedBlocks:])
+		}
+		return nil
+	}
+	return r.blocks(ctx, id, children)
+}
+
+// createdChildren maps the blocks created along with their parent to their
+// copies, appends their children, and the blocks which did not fit in the
+// request.
+func (r *restorer) createdChildren(ctx context.Context, parent createdBlock, id BlockID) error {
+	copies, err := r.client.Block.GetAllChildren(ctx, id).All()
+	if err != nil {
+		return err
+	}
+	for i, b := range parent.children {
+		if i >= len(copies) {
+			break
+		}
+		if err := r.blockChildren(ctx, b, copies[i].GetID()); err != nil {
+			return err
+		}
+	}
+	if rest := blockChildren(parent.original); len(rest) > maxAppendedBlocks {
+		return r.blocks(ctx, id, rest[maxAppendedBlocks:])
+	}
+	return nil
+}
+
+// restorableBlockType reports whether blocks of the type can be created.
+func restorableBlockType(t BlockType) bool {
+	switch t {
+	case BlockTypeChildPage, BlockTypeChildDatabase, BlockTypeLinkPreview, BlockTypeTemplate, BlockTypeUnsupported, "":
+		return false
+	}
+	return true
+}
+
+// blockReadOnlyKeys are the keys of blocks set by Notion.
+var blockReadOnlyKeys = []string{"id", "created_time", "last_edited_time", "created_by", "last_edited_by", "has_children", "archived", "in_trash", "parent"}
+
+// block returns a copy of the block to create, or nil if it cannot be
+// created. Copies have no children, except for column lists and tables, which
+// must be created with their columns and rows: the children of the original
+// created along with it are returned with the copy.
+func (r *restorer) block(ctx context.Context, b Block) (Block, []createdBlock, error) {
+	if !restorableBlockType(b.GetType()) {
+		return nil, nil, nil
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, err
+	}
+	for _, key := range blockReadOnlyKeys {
+		delete(raw, key)
+	}
+	content, _ := raw[string(b.GetType())].(map[string]interface{})
+	if content != nil {
+		delete(content, "children")
+		if err := r.blockFile(ctx, b, content); err != nil {
+			return nil, nil, err
+		}
+		if content["type"] == string(FileTypeFile) {
+			// The file was not downloaded, and its URL has expired.
+			return nil, nil, nil
+		}
+	}
+
+	if err := r.warnLinks("block "+b.GetID().String(), content); err != nil {
+		return nil, nil, err
+	}
+
+	restored, err := decodeBlock(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	var children Blocks
+	var created []createdBlock
+	switch b.GetType() {
+	case BlockTypeColumnList:
+		for _, column := range blockChildren(b) {
+			c, _, err := r.block(ctx, column)
+			if err != nil {
+				return nil, nil, err
+			}
+			var content Blocks
+			var contentCreated []createdBlock
+			for _, child := range firstBlocks(blockChildren(column)) {
+				cc, ccCreated, err := r.block(ctx, child)
+				if err != nil {
+					return nil, nil, err
+				}
+				if cc != nil {
+					content = append(content, cc)
+					contentCreated = append(contentCreated, createdBlock{original: child, children: ccCreated})
+				}
+			}
+			setBlockChildren(c, content)
+			children = append(children, c)
+			created = append(created, createdBlock{original: column, children: contentCreated})
+		}
+	case BlockTypeTableBlock:
+		for _, row := range firstBlocks(blockChildren(b)) {
+			c, _, err := r.block(ctx, row)
+			if err != nil {
+				return nil, nil, err
+			}
+			children = append(children, c)
+			created = append(created, createdBlock{original: row})
+		}
+	}
+	if children != nil {
+		setBlockChildren(restored, children)
+	}
+	return restored, created, nil
+}
+
+func firstBlocks(blocks Blocks) Blocks {
+	if len(blocks) > maxAppendedBlocks {
+		return blocks[:maxAppendedBlocks]
+	}
+	return blocks
+}
+
+// blockFile replaces the file hosted by Notion of a block, or of the icon of
+// a callout, by its upload. Files which were not downloaded are left with the
+// file type, for the caller to leave the block out, and icons are removed.
+func (r *restorer) blockFile(ctx context.Context, b Block, content map[string]interface{}) error {
+	if b.GetType() == BlockTypeCallout {
+		icon, _ := content["icon"].(map[string]interface{})
+		if icon == nil || icon["type"] != string(FileTypeFile) {
+			return nil
+		}
+		upload, err := r.upload(ctx, FileRef{Source: FileSourceIcon, BlockID: b.GetID()})
+		if err != nil {
+			return err
+		}
+		if upload == nil {
+			r.warnf("icon of block %s was not downloaded", b.GetID())
+			delete(content, "icon")
+			return nil
+		}
+		content["icon"] = map[string]interface{}{"type": FileTypeFileUpload, "file_upload": upload}
+		return nil
+	}
+
+	if content["type"] != string(FileTypeFile) {
+		return nil
+	}
+	upload, err := r.upload(ctx, FileRef{Source: FileSourceBlock, BlockID: b.GetID()})
+	if err != nil {
+		return err
+	}
+	if upload == nil {
+		r.warnf("file of block %s was not downloaded", b.GetID())
+		return nil
+	}
+	delete(content, "file")
+	content["type"] = FileTypeFileUpload
+	content["file_upload"] = upload
+	return nil
+}
+
+// warnLinks warns about the link to page blocks and the mentions in rich text
+// of a block or of page properties pointing to pages and databases of the
+// backup, as they are not remapped to the copies.
+func (r *restorer) warnLinks(owner string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	for _, id := range r.backupLinks(v) {
+		r.warnf("%s links to %s, which is not remapped to its copy", owner, id)
+	}
+	return nil
+}
+
+// backupLinks returns the IDs of the pages and databases of the backup that a
+// JSON value links to.
+func (r *restorer) backupLinks(v interface{}) []string {
+	var ids []string
+	switch v := v.(type) {
+	case map[string]interface{}:
+		targets := []interface{}{v["page_id"], v["database_id"]}
+		if mention, ok := v["mention"].(map[string]interface{}); ok {
+			for _, kind := range []string{"page", "database"} {
+				if m, ok := mention[kind].(map[string]interface{}); ok {
+					targets = append(targets, m["id"])
+				}
+			}
+		}
+		for _, target := range targets {
+			if id, ok := target.(string); ok && (r.pages[id] != nil || r.databases[id] != nil) {
+				ids = append(ids, id)
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			ids = append(ids, r.backupLinks(v[key])...)
+		}
+	case []interface{}:
+		for _, e := range v {
+			ids = append(ids, r.backupLinks(e)...)
+		}
+	}
+	return ids
+}
+
+// relations sets the relations of databases, then their rollups, then the
+// relations of pages, once all their targets exist.
+func (r *restorer) relations(ctx context.Context) error {
+	for _, d := range r.databaseRelations {
+		properties := PropertyConfigs{}
+		for name, target := range d.relations {
+			properties[name] = &RelationPropertyConfig{
+				Type:     PropertyConfigTypeRelation,
+				Relation: RelationConfig{DatabaseID: DatabaseID(r.remap(target.String())), SingleProperty: &SingleProperty{}},
+			}
+		}
+		if len(properties) > 0 {
+			if _, err := r.client.Database.Update(ctx, d.id, &DatabaseUpdateRequest{Properties: properties}); err != nil {
+				return fmt.Errorf("notionapi: cannot restore relations of database %s: %w", d.id, err)
+			}
+		}
+	}
+	for _, d := range r.databaseRelations {
+		if len(d.rollups) > 0 {
+			if _, err := r.client.Database.Update(ctx, d.id, &DatabaseUpdateRequest{Properties: d.rollups}); err != nil {
+				return fmt.Errorf("notionapi: cannot restore rollups of database %s: %w", d.id, err)
+			}
+		}
+	}
+
+	for _, p := range r.pageRelations {
+		properties := Properties{}
+		for name, relation := range p.relations {
+			var remapped []Relation
+			for _, rel := range relation {
+				remapped = append(remapped, Relation{ID: PageID(r.remap(rel.ID.String()))})
+			}
+			properties[name] = RelationProperty{Type: PropertyTypeRelation, Relation: remapped}
+		}
+		if _, err := r.client.Page.Update(ctx, p.id, &PageUpdateRequest{Properties: properties}); err != nil {
+			return fmt.Errorf("notionapi: cannot restore relations of page %s: %w", p.id, err)
+		}
+	}
+	return nil
+}
+
+// remap returns the ID of the copy of an object, or the ID of the object if
+// it was not restored.
+func (r *restorer) remap(id string) string {
+	if newID, ok := r.result.IDs[id]; ok {
+		return newID
+	}
+	return id
+}
+
+// comments recreates the discussions of pages and blocks on their page.
+func (r *restorer) comments(ctx context.Context) error {
+	for _, p := range r.archive.Pages {
+		pageID, ok := r.result.IDs[p.Page.ID.String()]
+		if !ok {
+			continue
+		}
+		discussions := map[DiscussionID]DiscussionID{}
+		for _, c := range p.Comments {
+			req := &CommentCreateRequest{RichText: c.RichText}
+			if id, ok := discussions[c.DiscussionID]; ok {
+				req.DiscussionID = id
+			} else {
+				req.Parent = Parent{Type: ParentTypePageID, PageID: PageID(pageID)}
+			}
+			created, err := r.client.Comment.Create(ctx, req)
+			if err != nil {
+				return fmt.Errorf("notionapi: cannot restore comment %s: %w", c.ID, err)
+			}
+			discussions[c.DiscussionID] = created.DiscussionID
+		}
+	}
+	return nil
+}
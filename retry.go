@@ -0,0 +1,212 @@
+package notionapi
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 30 * time.Second
+)
+
+// RetryPolicy decides whether a failed request is retried and how long to
+// wait before the next attempt.
+type RetryPolicy interface {
+	// Retry is called after the attempt-th attempt of req failed, starting
+	// from 1. Either res is the response with a status code other than 200,
+	// in which case err is the error decoded from its body, or res is nil and
+	// err is the error returned by the http.Client or a middleware. The body
+	// of res has already been read.
+	Retry(attempt int, req *http.Request, res *http.Response, err error) (time.Duration, bool)
+}
+
+// BackoffPolicy is a RetryPolicy with exponential backoff and jitter, which is
+// used by default.
+//
+// Responses that indicate that the request was not processed, such as 429
+// rate limited and 409 conflict errors, are always retried. Other failures,
+// such as 5xx responses and network errors, may happen after the request was
+// applied, so POST and PATCH requests are only retried for them if
+// RetryNonIdempotent is set. Database queries and searches are retried as
+// they don't modify anything.
+type BackoffPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first
+	// one. Defaults to 3.
+	MaxAttempts int
+	// BaseDelay is the delay before the second attempt, doubled for every
+	// following attempt. Defaults to 500ms.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts, including the one asked by
+	// the Retry-After header of 429 responses. Defaults to 30s.
+	MaxDelay time.Duration
+	// RetryableStatuses are the HTTP status codes of responses that are
+	// retried. Defaults to 429, 500, 502, 503 and 504.
+	RetryableStatuses []int
+	// RetryableCodes are the codes of Notion errors that are retried, whatever
+	// their HTTP status. Defaults to rate_limited, conflict_error,
+	// internal_server_error, service_unavailable and
+	// database_connection_unavailable.
+	RetryableCodes []ErrorCode
+	// RetryNonIdempotent allows retrying POST and PATCH requests after
+	// failures that may happen once the request has been applied.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns the retry policy used by clients unless
+// WithRetryPolicy is given.
+func DefaultRetryPolicy() *BackoffPolicy {
+	return &BackoffPolicy{
+		MaxAttempts:       maxRetries,
+		BaseDelay:         defaultRetryBaseDelay,
+		MaxDelay:          defaultRetryMaxDelay,
+		RetryableStatuses: []int{http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+		RetryableCodes:    []ErrorCode{ErrorCodeRateLimited, ErrorCodeConflictError, ErrorCodeInternalServerError, ErrorCodeServiceUnavailable, ErrorCodeDatabaseConnectionUnavailable},
+	}
+}
+
+// Retry implements RetryPolicy.
+func (p *BackoffPolicy) Retry(attempt int, req *http.Request, res *http.Response, err error) (time.Duration, bool) {
+	defaults := DefaultRetryPolicy()
+	maxAttempts := p.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = defaults.MaxAttempts
+	}
+	if attempt >= maxAttempts {
+		return 0, false
+	}
+
+	if res == nil {
+		// Only network errors are retried, not errors returned by
+		// middlewares.
+		var urlErr *url.Error
+		if !errors.As(err, &urlErr) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, false
+		}
+		if !p.RetryNonIdempotent && !isIdempotent(req) {
+			return 0, false
+		}
+		return p.backoff(attempt), true
+	}
+
+	statuses := p.RetryableStatuses
+	if statuses == nil {
+		statuses = defaults.RetryableStatuses
+	}
+	codes := p.RetryableCodes
+	if codes == nil {
+		codes = defaults.RetryableCodes
+	}
+	var code ErrorCode
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		code = apiErr.Code
+	}
+	if !containsStatus(statuses, res.StatusCode) && !containsCode(codes, code) {
+		return 0, false
+	}
+
+	notProcessed := res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusConflict ||
+		code == ErrorCodeRateLimited || code == ErrorCodeConflictError
+	if !notProcessed && !p.RetryNonIdempotent && !isIdempotent(req) {
+		return 0, false
+	}
+
+	if wait, ok := retryAfter(res); ok {
+		if maxDelay := p.maxDelay(); wait > maxDelay {
+			wait = maxDelay
+		}
+		return wait, true
+	}
+	return p.backoff(attempt), true
+}
+
+func (p *BackoffPolicy) maxDelay() time.Duration {
+	if p.MaxDelay == 0 {
+		return defaultRetryMaxDelay
+	}
+	return p.MaxDelay
+}
+
+// backoff returns the delay before the attempt following the given one: the
+// exponential delay with up to half of it replaced by random jitter.
+func (p *BackoffPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	if delay == 0 {
+		delay = defaultRetryBaseDelay
+	}
+	maxDelay := p.maxDelay()
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// retryAfter returns the delay asked by the Retry-After header of a response,
+// either in seconds or as an HTTP date.
+//
+// See https://developers.notion.com/reference/request-limits#rate-limits
+func retryAfter(res *http.Response) (time.Duration, bool) {
+	header := strings.TrimSpace(res.Header.Get("Retry-After"))
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		wait := time.Until(t)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// isIdempotent reports whether sending the request several times has the same
+// effect as sending it once.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodDelete, http.MethodPut:
+		return true
+	case http.MethodPost:
+		path := strings.TrimSuffix(req.URL.Path, "/")
+		return strings.HasSuffix(path, "/query") || strings.HasSuffix(path, "/search")
+	}
+	return false
+}
+
+func containsStatus(statuses []int, status int) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func containsCode(codes []ErrorCode, code ErrorCode) bool {
+	if code == "" {
+		return false
+	}
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
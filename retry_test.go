@@ -0,0 +1,145 @@
+package notionapi_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+)
+
+// newFlakyClient returns a client whose requests fail with the given status
+// and body until the last attempt, which succeeds.
+func newFlakyClient(t *testing.T, failures int, status int, body string, policy notionapi.RetryPolicy) (*notionapi.Client, *[]string) {
+	var bodies []string
+	c := newTestClient(func(req *http.Request) *http.Response {
+		var b []byte
+		if req.Body != nil {
+			var err error
+			if b, err = ioutil.ReadAll(req.Body); err != nil {
+				t.Fatal(err)
+			}
+		}
+		bodies = append(bodies, string(b))
+		if len(bodies) <= failures {
+			return &http.Response{StatusCode: status, Header: make(http.Header), Body: ioutil.NopCloser(strings.NewReader(body))}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       ioutil.NopCloser(strings.NewReader(`{"object":"list","results":[]}`)),
+		}
+	})
+	return notionapi.NewClient("some_token", notionapi.WithHTTPClient(c), notionapi.WithRetryPolicy(policy)), &bodies
+}
+
+func TestBackoffPolicy(t *testing.T) {
+	policy := &notionapi.BackoffPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	ctx := context.Background()
+
+	t.Run("retries server errors and replays the body", func(t *testing.T) {
+		client, bodies := newFlakyClient(t, 2, http.StatusBadGateway, "", policy)
+		_, err := client.Database.Query(ctx, "some_id", &notionapi.DatabaseQueryRequest{PageSize: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(*bodies) != 3 {
+			t.Fatalf("Query() made %d attempts, want 3", len(*bodies))
+		}
+		for i, b := range *bodies {
+			if b != `{"page_size":10}` {
+				t.Errorf("attempt %d body = %q", i+1, b)
+			}
+		}
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		body := `{"object":"error","status":503,"code":"service_unavailable","message":"Notion is unavailable"}`
+		client, bodies := newFlakyClient(t, 3, http.StatusServiceUnavailable, body, policy)
+		_, err := client.Block.Get(ctx, "some_id")
+		var apiErr *notionapi.Error
+		if !errors.As(err, &apiErr) || apiErr.Code != "service_unavailable" {
+			t.Errorf("Get() error = %v, want the service_unavailable error", err)
+		}
+		if len(*bodies) != 3 {
+			t.Errorf("Get() made %d attempts, want 3", len(*bodies))
+		}
+	})
+
+	t.Run("does not retry non-idempotent requests after server errors", func(t *testing.T) {
+		client, bodies := newFlakyClient(t, 1, http.StatusBadGateway, "", policy)
+		if _, err := client.Page.Create(ctx, &notionapi.PageCreateRequest{}); err == nil {
+			t.Error("Create() error = nil, want an error")
+		}
+		if len(*bodies) != 1 {
+			t.Errorf("Create() made %d attempts, want 1", len(*bodies))
+		}
+
+		p := *policy
+		p.RetryNonIdempotent = true
+		client, bodies = newFlakyClient(t, 1, http.StatusBadGateway, "", &p)
+		if _, err := client.Page.Create(ctx, &notionapi.PageCreateRequest{}); err != nil {
+			t.Fatal(err)
+		}
+		if len(*bodies) != 2 {
+			t.Errorf("Create() made %d attempts, want 2", len(*bodies))
+		}
+	})
+
+	t.Run("retries conflicts of non-idempotent requests", func(t *testing.T) {
+		body := `{"object":"error","status":409,"code":"conflict_error","message":"Conflict"}`
+		client, bodies := newFlakyClient(t, 1, http.StatusConflict, body, policy)
+		if _, err := client.Page.Update(ctx, "some_id", &notionapi.PageUpdateRequest{}); err != nil {
+			t.Fatal(err)
+		}
+		if len(*bodies) != 2 {
+			t.Errorf("Update() made %d attempts, want 2", len(*bodies))
+		}
+	})
+
+	t.Run("retries rate limited requests without Retry-After", func(t *testing.T) {
+		client, bodies := newFlakyClient(t, 1, http.StatusTooManyRequests, "", policy)
+		if _, err := client.Block.GetChildren(ctx, "some_id", nil); err != nil {
+			t.Fatal(err)
+		}
+		if len(*bodies) != 2 {
+			t.Errorf("GetChildren() made %d attempts, want 2", len(*bodies))
+		}
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		body := `{"object":"error","status":400,"code":"validation_error","message":"Invalid"}`
+		client, bodies := newFlakyClient(t, 1, http.StatusBadRequest, body, policy)
+		if _, err := client.Block.Get(ctx, "some_id"); err == nil || err.Error() != "Invalid" {
+			t.Errorf("Get() error = %v, want Invalid", err)
+		}
+		if len(*bodies) != 1 {
+			t.Errorf("Get() made %d attempts, want 1", len(*bodies))
+		}
+	})
+}
+
+type retryFunc func(attempt int, req *http.Request, res *http.Response, err error) (time.Duration, bool)
+
+func (f retryFunc) Retry(attempt int, req *http.Request, res *http.Response, err error) (time.Duration, bool) {
+	return f(attempt, req, res, err)
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	var statuses []int
+	policy := retryFunc(func(attempt int, req *http.Request, res *http.Response, err error) (time.Duration, bool) {
+		statuses = append(statuses, res.StatusCode)
+		return 0, attempt < 4
+	})
+	client, bodies := newFlakyClient(t, 10, http.StatusNotFound, `{"object":"error","status":404,"code":"object_not_found"}`, policy)
+	if _, err := client.Block.Get(context.Background(), "some_id"); err == nil {
+		t.Errorf("Get() error = nil, want an error")
+	}
+	if len(*bodies) != 4 || len(statuses) != 4 {
+		t.Errorf("Get() made %d attempts and called the policy %d times", len(*bodies), len(statuses))
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 type SearchService interface {
 	Do(context.Context, *SearchRequest) (*SearchResponse, error)
+	DoAll(context.Context, *SearchRequest, ...IteratorOption) *ObjectIterator
 }
 
 type SearchClient struct {
@@ -50,6 +51,32 @@ func (sc *SearchClient) Do(ctx context.Context, request *SearchRequest) (*Search
 	return &response, nil
 }
 
+// DoAll returns an iterator over all the pages and databases matching the
+// request, following next_cursor until every page of results has been read.
+// The request is not modified; its StartCursor, if any, is used as the
+// starting point.
+func (sc *SearchClient) DoAll(ctx context.Context, request *SearchRequest, opts ...IteratorOption) *ObjectIterator {
+	var req SearchRequest
+	if request != nil {
+		req = *request
+	}
+
+	it := &ObjectIterator{}
+	it.cursorIterator = newCursorIterator(ctx, req.StartCursor, opts, func(ctx context.Context, cursor Cursor, pageSize int) (int, Cursor, bool, error) {
+		req.StartCursor = cursor
+		if pageSize != 0 {
+			req.PageSize = pageSize
+		}
+		res, err := sc.Do(ctx, &req)
+		if err != nil {
+			return 0, "", false, err
+		}
+		it.results = res.Results
+		return len(res.Results), res.NextCursor, res.HasMore, nil
+	})
+	return it
+}
+
 type SearchRequest struct {
 	// The text that the API compares page and database titles against.
 	Query string `json:"query,omitempty"`
@@ -70,6 +97,28 @@ type SearchRequest struct {
 	PageSize int `json:"page_size,omitempty"`
 }
 
+// MarshalJSON omits a zero Filter, as Notion rejects a filter without value
+// and property.
+func (sr SearchRequest) MarshalJSON() ([]byte, error) {
+	var filter *SearchFilter
+	if sr.Filter != (SearchFilter{}) {
+		filter = &sr.Filter
+	}
+	return json.Marshal(struct {
+		Query       string        `json:"query,omitempty"`
+		Sort        *SortObject   `json:"sort,omitempty"`
+		Filter      *SearchFilter `json:"filter,omitempty"`
+		StartCursor Cursor        `json:"start_cursor,omitempty"`
+		PageSize    int           `json:"page_size,omitempty"`
+	}{
+		Query:       sr.Query,
+		Sort:        sr.Sort,
+		Filter:      filter,
+		StartCursor: sr.StartCursor,
+		PageSize:    sr.PageSize,
+	})
+}
+
 type SearchResponse struct {
 	Object     ObjectType `json:"object"`
 	Results    []Object   `json:"results"`
@@ -0,0 +1,413 @@
+package notionapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+type ChangeType string
+
+const (
+	ChangeCreated  ChangeType = "created"
+	ChangeUpdated  ChangeType = "updated"
+	ChangeArchived ChangeType = "archived"
+)
+
+// Change is a change of a page, a database or a block detected by a Syncer.
+type Change struct {
+	Type   ChangeType
+	Object ObjectType
+	ID     string
+	// LastEditedTime is the last edited time of the object, or the time of
+	// the sync for objects found archived.
+	LastEditedTime time.Time
+	// The object as of the sync, for created and updated objects.
+	Page     *Page
+	Database *Database
+	Block    Block
+	// PageID is the page holding a block.
+	PageID PageID
+}
+
+// ChangeSink receives the changes detected by a Syncer.
+type ChangeSink interface {
+	Emit(ctx context.Context, change Change) error
+}
+
+// ChangeSinkFunc is a ChangeSink calling a function.
+type ChangeSinkFunc func(ctx context.Context, change Change) error
+
+func (f ChangeSinkFunc) Emit(ctx context.Context, change Change) error {
+	return f(ctx, change)
+}
+
+// SyncCheckpoint is the state of a sync, from which the next one detects
+// changes.
+type SyncCheckpoint struct {
+	// Time is the latest last edited time of the objects seen.
+	Time time.Time `json:"time"`
+	// SyncTime is the time the sync started.
+	SyncTime time.Time `json:"sync_time"`
+	// Objects are the pages, databases and blocks seen, by ID.
+	Objects map[string]SyncedObject `json:"objects"`
+}
+
+// SyncedObject is an object seen by a sync.
+type SyncedObject struct {
+	Object         ObjectType `json:"object"`
+	LastEditedTime time.Time  `json:"last_edited_time"`
+	// PageID is the page holding a block.
+	PageID PageID `json:"page_id,omitempty"`
+}
+
+// ReadSyncCheckpoint reads a checkpoint saved by SyncCheckpoint.Save, and
+// returns an empty checkpoint if the file does not exist.
+func ReadSyncCheckpoint(name string) (*SyncCheckpoint, error) {
+	var c SyncCheckpoint
+	if err := readJSONFile(name, &c); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &SyncCheckpoint{}, nil
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Save writes the checkpoint to a file, replacing it atomically.
+func (c *SyncCheckpoint) Save(name string) error {
+	tmp := name + ".tmp"
+	if err := writeJSONFile(tmp, c); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+// SyncOptions configures a Syncer.
+type SyncOptions struct {
+	// Databases are queried for pages edited since the checkpoint, in
+	// addition to the search, whose index may lag behind edits.
+	Databases []DatabaseID
+	// Blocks fetches the content of changed pages to detect changed blocks.
+	Blocks bool
+	// FullScan lists all the pages and databases, instead of those edited
+	// since the checkpoint, to detect the ones archived.
+	FullScan bool
+}
+
+// Syncer detects the pages, databases and blocks created, updated and
+// archived since a checkpoint, and emits the changes to a sink.
+//
+// Changes are found by searching for objects sorted by last edit, and by
+// querying databases with a filter on their last edited time. As Notion
+// rounds last edited times to the minute, objects edited during the minute
+// of the checkpoint are emitted again by the next sync. Archived pages and
+// databases are only detected by FullScan, and when their block is removed
+// from a page whose blocks are synced; archived blocks when the content of
+// their page is synced.
+type Syncer struct {
+	client *Client
+	sink   ChangeSink
+	opts   SyncOptions
+}
+
+// NewSyncer returns a syncer emitting the changes to sink.
+func NewSyncer(client *Client, sink ChangeSink, opts SyncOptions) *Syncer {
+	return &Syncer{client: client, sink: sink, opts: opts}
+}
+
+// Sync emits the changes since the checkpoint, in the order of their last
+// edit, and returns the next checkpoint. A nil or empty checkpoint emits
+// every object as created. If it fails, changes may have been emitted, and
+// are emitted again by the next sync from the same checkpoint.
+func (s *Syncer) Sync(ctx context.Context, checkpoint *SyncCheckpoint) (*SyncCheckpoint, error) {
+	if checkpoint == nil {
+		checkpoint = &SyncCheckpoint{}
+	}
+	next := &SyncCheckpoint{Time: checkpoint.Time, SyncTime: time.Now().UTC(), Objects: map[string]SyncedObject{}}
+	for id, o := range checkpoint.Objects {
+		next.Objects[id] = o
+	}
+	since := checkpoint.Time.Truncate(time.Minute)
+
+	edited, listed, err := s.edited(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for _, c := range edited {
+		known, ok := checkpoint.Objects[c.ID]
+		switch {
+		case !ok:
+			c.Type = ChangeCreated
+		case c.LastEditedTime.After(known.LastEditedTime):
+			c.Type = ChangeUpdated
+		case c.LastEditedTime.Add(time.Minute).After(checkpoint.SyncTime):
+			// The object may have been edited again within the same minute.
+			c.Type = ChangeUpdated
+		default:
+			continue
+		}
+		changes = append(changes, c)
+	}
+	if s.opts.FullScan {
+		archived, err := s.archived(ctx, checkpoint, listed)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, archived...)
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].LastEditedTime.Before(changes[j].LastEditedTime) })
+
+	for _, c := range changes {
+		if err := s.emit(ctx, next, c); err != nil {
+			return nil, err
+		}
+		if c.Object == ObjectTypePage && c.Type != ChangeArchived && s.opts.Blocks {
+			if err := s.blocks(ctx, checkpoint, next, PageID(c.ID)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return next, nil
+}
+
+// edited returns the pages and databases edited since the given time, or
+// all of them if since is zero, and the IDs listed by the search.
+func (s *Syncer) edited(ctx context.Context, since time.Time) ([]Change, map[string]bool, error) {
+	var changes []Change
+	seen := map[string]bool{}
+	add := func(c Change) {
+		if !seen[c.ID] {
+			seen[c.ID] = true
+			changes = append(changes, c)
+		}
+	}
+
+	it := s.client.Search.DoAll(ctx, &SearchRequest{
+		Sort:     &SortObject{Timestamp: TimestampLastEdited, Direction: SortOrderDESC},
+		PageSize: 100,
+	})
+	listed := map[string]bool{}
+	for it.Next() {
+		var c Change
+		switch o := it.Value().(type) {
+		case *Page:
+			c = Change{Object: ObjectTypePage, ID: o.ID.String(), LastEditedTime: o.LastEditedTime, Page: o}
+		case *Database:
+			c = Change{Object: ObjectTypeDatabase, ID: o.ID.String(), LastEditedTime: o.LastEditedTime, Database: o}
+		default:
+			continue
+		}
+		if c.LastEditedTime.Before(since) {
+			if !s.opts.FullScan {
+				break
+			}
+			listed[c.ID] = true
+			continue
+		}
+		listed[c.ID] = true
+		add(c)
+	}
+	if err := it.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	for _, id := range s.opts.Databases {
+		req := &DatabaseQueryRequest{Sorts: []SortObject{{Timestamp: TimestampLastEdited, Direction: SortOrderASC}}}
+		if !since.IsZero() {
+			after := Date(since)
+			req.Filter = &TimestampFilter{Timestamp: TimestampLastEdited, LastEditedTime: &DateFilterCondition{OnOrAfter: &after}}
+		}
+		pages, err := s.client.Database.QueryAll(ctx, id, req).All()
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range pages {
+			p := &pages[i]
+			add(Change{Object: ObjectTypePage, ID: p.ID.String(), LastEditedTime: p.LastEditedTime, Page: p})
+		}
+	}
+	return changes, listed, nil
+}
+
+// archived returns the known pages and databases which were not listed,
+// once confirmed archived.
+func (s *Syncer) archived(ctx context.Context, checkpoint *SyncCheckpoint, listed map[string]bool) ([]Change, error) {
+	var changes []Change
+	for _, id := range sortedObjectIDs(checkpoint.Objects) {
+		o := checkpoint.Objects[id]
+		if listed[id] || (o.Object != ObjectTypePage && o.Object != ObjectTypeDatabase) {
+			continue
+		}
+		var err error
+		archived := true
+		if o.Object == ObjectTypePage {
+			var p *Page
+			if p, err = s.client.Page.Get(ctx, PageID(id)); err == nil {
+				archived = p.Archived
+			}
+		} else {
+			var db *Database
+			if db, err = s.client.Database.Get(ctx, DatabaseID(id)); err == nil {
+				archived = db.Archived
+			}
+		}
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		if archived {
+			changes = append(changes, Change{Type: ChangeArchived, Object: o.Object, ID: id, LastEditedTime: time.Now().UTC()})
+		}
+	}
+	return changes, nil
+}
+
+// blocks emits the changes of the blocks of a page since the checkpoint.
+func (s *Syncer) blocks(ctx context.Context, checkpoint, next *SyncCheckpoint, page PageID) error {
+	blocks, err := s.client.Block.GetTree(ctx, BlockID(page))
+	if err != nil {
+		return err
+	}
+
+	current := map[string]bool{}
+	var changes []Change
+	walkBlocks(blocks, func(b Block) {
+		id := b.GetID().String()
+		current[id] = true
+		c := Change{Object: ObjectTypeBlock, ID: id, Block: b, PageID: page}
+		if t := b.GetLastEditedTime(); t != nil {
+			c.LastEditedTime = *t
+		}
+		known, ok := checkpoint.Objects[id]
+		switch {
+		case !ok:
+			c.Type = ChangeCreated
+		case c.LastEditedTime.After(known.LastEditedTime), c.LastEditedTime.Add(time.Minute).After(checkpoint.SyncTime):
+			c.Type = ChangeUpdated
+		default:
+			return
+		}
+		changes = append(changes, c)
+	})
+	for _, id := range sortedObjectIDs(checkpoint.Objects) {
+		o := checkpoint.Objects[id]
+		if o.Object != ObjectTypeBlock || o.PageID != page || current[id] {
+			continue
+		}
+		changes = append(changes, Change{Type: ChangeArchived, Object: ObjectTypeBlock, ID: id, PageID: page, LastEditedTime: time.Now().UTC()})
+		// The block of a child page has the ID of the page.
+		if sub, ok := next.Objects[id]; ok && sub.Object == ObjectTypePage {
+			changes = append(changes, Change{Type: ChangeArchived, Object: ObjectTypePage, ID: id, LastEditedTime: time.Now().UTC()})
+		}
+	}
+
+	for _, c := range changes {
+		if err := s.emit(ctx, next, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emit sends a change to the sink and records it in the checkpoint.
+func (s *Syncer) emit(ctx context.Context, next *SyncCheckpoint, c Change) error {
+	if err := s.sink.Emit(ctx, c); err != nil {
+		return fmt.Errorf("notionapi: cannot emit change of %s %s: %w", c.Object, c.ID, err)
+	}
+	if c.Type == ChangeArchived {
+		delete(next.Objects, c.ID)
+		if c.Object == ObjectTypePage {
+			for id, o := range next.Objects {
+				if o.Object == ObjectTypeBlock && o.PageID.String() == c.ID {
+					delete(next.Objects, id)
+				}
+			}
+		}
+		return nil
+	}
+	next.Objects[c.ID] = SyncedObject{Object: c.Object, LastEditedTime: c.LastEditedTime, PageID: c.PageID}
+	if c.LastEditedTime.After(next.Time) {
+		next.Time = c.LastEditedTime
+	}
+	return nil
+}
+
+func sortedObjectIDs(objects map[string]SyncedObject) []string {
+	ids := make([]string, 0, len(objects))
+	for id := range objects {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// DirectorySink mirrors the synced objects in a directory, holding a JSON
+// file per object, under pages, databases and blocks, removed once the
+// object is archived, and appends the changes to changes.jsonl.
+type DirectorySink struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewDirectorySink returns a sink writing to dir, which is created if needed.
+func NewDirectorySink(dir string) (*DirectorySink, error) {
+	for _, sub := range []string{"pages", "databases", "blocks"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &DirectorySink{dir: dir}, nil
+}
+
+type changeRecord struct {
+	Type           ChangeType `json:"type"`
+	Object         ObjectType `json:"object"`
+	ID             string     `json:"id"`
+	LastEditedTime time.Time  `json:"last_edited_time"`
+	PageID         PageID     `json:"page_id,omitempty"`
+}
+
+func (d *DirectorySink) Emit(_ context.Context, c Change) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	name := filepath.Join(d.dir, c.Object.String()+"s", c.ID+".json")
+	if c.Type == ChangeArchived {
+		if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	} else {
+		var v interface{} = c.Page
+		switch c.Object {
+		case ObjectTypeDatabase:
+			v = c.Database
+		case ObjectTypeBlock:
+			v = c.Block
+		}
+		if err := writeJSONFile(name, v); err != nil {
+			return err
+		}
+	}
+
+	line, err := json.Marshal(changeRecord{Type: c.Type, Object: c.Object, ID: c.ID, LastEditedTime: c.LastEditedTime, PageID: c.PageID})
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(d.dir, "changes.jsonl"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
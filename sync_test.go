@@ -0,0 +1,242 @@
+package notionapi_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/jomei/notionapi/notionapitest"
+)
+
+func TestSyncer(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	srv := notionapitest.NewServer(notionapitest.WithClock(func() time.Time { return now }))
+	defer srv.Close()
+
+	var bodies []string
+	client := srv.Client(notionapi.WithMiddleware(func(next notionapi.Handler) notionapi.Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Method == http.MethodPost && req.Body != nil {
+				b, _ := ioutil.ReadAll(req.Body)
+				req.Body = ioutil.NopCloser(bytes.NewReader(b))
+				bodies = append(bodies, req.URL.Path+" "+string(b))
+			}
+			return next(req)
+		}
+	}))
+
+	root := notionapi.Parent{Type: notionapi.ParentTypePageID, PageID: srv.RootPageID()}
+	db, err := client.Database.Create(ctx, &notionapi.DatabaseCreateRequest{
+		Parent:     root,
+		Title:      richText("Tasks"),
+		Properties: notionapi.PropertyConfigs{"Name": notionapi.TitlePropertyConfig{Type: notionapi.PropertyConfigTypeTitle}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, err := client.Page.Create(ctx, &notionapi.PageCreateRequest{
+		Parent:     notionapi.Parent{Type: notionapi.ParentTypeDatabaseID, DatabaseID: notionapi.DatabaseID(db.ID)},
+		Properties: notionapi.Properties{"Name": notionapi.TitleProperty{Title: richText("Design")}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	notes, err := client.Page.Create(ctx, &notionapi.PageCreateRequest{
+		Parent:     root,
+		Properties: notionapi.Properties{"title": notionapi.TitleProperty{Title: richText("Notes")}},
+		Children:   []notionapi.Block{paragraph("Intro"), paragraph("Outdated")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err := client.Page.Create(ctx, &notionapi.PageCreateRequest{
+		Parent:     notionapi.Parent{Type: notionapi.ParentTypePageID, PageID: notionapi.PageID(notes.ID)},
+		Properties: notionapi.Properties{"title": notionapi.TitleProperty{Title: richText("Sub-page")}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := map[string]string{
+		srv.RootPageID().String(): "root",
+		db.ID.String():            "tasks",
+		task.ID.String():          "design",
+		notes.ID.String():         "notes",
+		sub.ID.String():           "sub-page",
+	}
+	var changes []string
+	syncer := notionapi.NewSyncer(client, notionapi.ChangeSinkFunc(func(_ context.Context, c notionapi.Change) error {
+		if _, ok := names[c.ID]; !ok && c.Block != nil {
+			names[c.ID] = c.Block.GetRichTextString()
+		}
+		changes = append(changes, fmt.Sprintf("%s %s %s", c.Type, c.Object, names[c.ID]))
+		return nil
+	}), notionapi.SyncOptions{Databases: []notionapi.DatabaseID{notionapi.DatabaseID(db.ID)}, Blocks: true, FullScan: true})
+
+	checkpoint, err := syncer.Sync(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(changes)
+	want := []string{
+		"created block Intro",
+		"created block Outdated",
+		"created block notes",
+		"created block sub-page",
+		"created block tasks",
+		"created database tasks",
+		"created page design",
+		"created page notes",
+		"created page root",
+		"created page sub-page",
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("first Sync() changes =\n%s\nwant\n%s", strings.Join(changes, "\n"), strings.Join(want, "\n"))
+	}
+
+	now = now.Add(5 * time.Minute)
+	changes, bodies = nil, nil
+	_, err = client.Page.Update(ctx, notionapi.PageID(task.ID), &notionapi.PageUpdateRequest{
+		Properties: notionapi.Properties{"Name": notionapi.TitleProperty{Title: richText("Design v2")}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Page.Update(ctx, notionapi.PageID(sub.ID), &notionapi.PageUpdateRequest{Archived: true}); err != nil {
+		t.Fatal(err)
+	}
+	tree, err := client.Block.GetTree(ctx, notionapi.BlockID(notes.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Block.Delete(ctx, tree[1].GetID()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Page.Update(ctx, notionapi.PageID(notes.ID), &notionapi.PageUpdateRequest{
+		Properties: notionapi.Properties{"title": notionapi.TitleProperty{Title: richText("Notes")}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	checkpoint, err = syncer.Sync(ctx, checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(changes)
+	want = []string{
+		"archived block Outdated",
+		"archived page sub-page",
+		"updated page design",
+		"updated page notes",
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("second Sync() changes =\n%s\nwant\n%s", strings.Join(changes, "\n"), strings.Join(want, "\n"))
+	}
+	wantSearch := `/v1/search {"sort":{"timestamp":"last_edited_time","direction":"descending"},"page_size":100}`
+	wantQuery := fmt.Sprintf(`/v1/databases/%s/query {"sorts":[{"timestamp":"last_edited_time","direction":"ascending"}],"filter":{"timestamp":"last_edited_time","last_edited_time":{"on_or_after":"2024-03-01T10:00:00Z"}}}`, db.ID)
+	if len(bodies) < 2 || bodies[0] != wantSearch || bodies[1] != wantQuery {
+		t.Errorf("second Sync() requests =\n%s\nwant\n%s\n%s", strings.Join(bodies, "\n"), wantSearch, wantQuery)
+	}
+
+	changes = nil
+	if checkpoint, err = syncer.Sync(ctx, checkpoint); err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Sync() without edits changes = %v", changes)
+	}
+
+	// The blocks of archived pages are forgotten with them.
+	now = now.Add(5 * time.Minute)
+	if _, err := client.Page.Update(ctx, notionapi.PageID(notes.ID), &notionapi.PageUpdateRequest{Archived: true}); err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint, err = syncer.Sync(ctx, checkpoint); err != nil {
+		t.Fatal(err)
+	}
+	for id, o := range checkpoint.Objects {
+		if id == notes.ID.String() || o.PageID.String() == notes.ID.String() {
+			t.Errorf("checkpoint object %s %+v of the archived notes", id, o)
+		}
+	}
+}
+
+func TestSyncCheckpoint(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "checkpoint.json")
+	c, err := notionapi.ReadSyncCheckpoint(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Time.IsZero() || len(c.Objects) != 0 {
+		t.Errorf("ReadSyncCheckpoint() of a missing file = %+v", c)
+	}
+
+	c.Time = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	c.Objects = map[string]notionapi.SyncedObject{"b": {Object: notionapi.ObjectTypeBlock, LastEditedTime: c.Time, PageID: "p"}}
+	if err := c.Save(name); err != nil {
+		t.Fatal(err)
+	}
+	got, err := notionapi.ReadSyncCheckpoint(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, c) {
+		t.Errorf("ReadSyncCheckpoint() = %+v, want %+v", got, c)
+	}
+}
+
+func TestDirectorySink(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	sink, err := notionapi.NewDirectorySink(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	page := &notionapi.Page{Object: notionapi.ObjectTypePage, ID: "p"}
+	for _, c := range []notionapi.Change{
+		{Type: notionapi.ChangeCreated, Object: notionapi.ObjectTypePage, ID: "p", LastEditedTime: edited, Page: page},
+		{Type: notionapi.ChangeCreated, Object: notionapi.ObjectTypeBlock, ID: "b", LastEditedTime: edited, Block: paragraph("Intro"), PageID: "p"},
+		{Type: notionapi.ChangeArchived, Object: notionapi.ObjectTypePage, ID: "p", LastEditedTime: edited},
+	} {
+		if err := sink.Emit(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "pages", "p.json")); !os.IsNotExist(err) {
+		t.Errorf("archived page file error = %v, want not exist", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "blocks", "b.json")); err != nil {
+		t.Error(err)
+	}
+	f, err := os.Open(filepath.Join(dir, "changes.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var lines []string
+	for s := bufio.NewScanner(f); s.Scan(); {
+		lines = append(lines, s.Text())
+	}
+	want := []string{
+		`{"type":"created","object":"page","id":"p","last_edited_time":"2024-03-01T10:00:00Z"}`,
+		`{"type":"created","object":"block","id":"b","last_edited_time":"2024-03-01T10:00:00Z","page_id":"p"}`,
+		`{"type":"archived","object":"page","id":"p","last_edited_time":"2024-03-01T10:00:00Z"}`,
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("changes.jsonl =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -18,6 +18,7 @@ type UserService interface {
 	List(context.Context, *Pagination) (*UsersListResponse, error)
 	Get(context.Context, UserID) (*User, error)
 	Me(context.Context) (*User, error)
+	ListAll(context.Context, ...IteratorOption) *UserIterator
 }
 
 type UserClient struct {
@@ -49,6 +50,21 @@ func (uc *UserClient) List(ctx context.Context, pagination *Pagination) (*UsersL
 	return &response, nil
 }
 
+// ListAll returns an iterator over all the users of the workspace, following
+// next_cursor until every page of results has been read.
+func (uc *UserClient) ListAll(ctx context.Context, opts ...IteratorOption) *UserIterator {
+	it := &UserIterator{}
+	it.cursorIterator = newCursorIterator(ctx, "", opts, func(ctx context.Context, cursor Cursor, pageSize int) (int, Cursor, bool, error) {
+		res, err := uc.List(ctx, &Pagination{StartCursor: cursor, PageSize: pageSize})
+		if err != nil {
+			return 0, "", false, err
+		}
+		it.results = res.Results
+		return len(res.Results), res.NextCursor, res.HasMore, nil
+	})
+	return it
+}
+
 // Retrieves a User using the ID specified.
 //
 // See https://developers.notion.com/reference/get-user
@@ -0,0 +1,449 @@
+package notionapi
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WebhookSignatureHeader is the header holding the signature of webhook
+// requests: "sha256=" followed by the hex encoded HMAC-SHA256 of the body,
+// keyed with the verification token of the subscription.
+const WebhookSignatureHeader = "X-Notion-Signature"
+
+// maxWebhookBodySize bounds the size of the webhook requests read.
+const maxWebhookBodySize = 1 << 20
+
+type WebhookEventType string
+
+const (
+	WebhookEventPageCreated           WebhookEventType = "page.created"
+	WebhookEventPageContentUpdated    WebhookEventType = "page.content_updated"
+	WebhookEventPagePropertiesUpdated WebhookEventType = "page.properties_updated"
+	WebhookEventPageMoved             WebhookEventType = "page.moved"
+	WebhookEventPageDeleted           WebhookEventType = "page.deleted"
+	WebhookEventPageUndeleted         WebhookEventType = "page.undeleted"
+	WebhookEventPageLocked            WebhookEventType = "page.locked"
+	WebhookEventPageUnlocked          WebhookEventType = "page.unlocked"
+
+	WebhookEventDatabaseCreated        WebhookEventType = "database.created"
+	WebhookEventDatabaseContentUpdated WebhookEventType = "database.content_updated"
+	WebhookEventDatabaseSchemaUpdated  WebhookEventType = "database.schema_updated"
+	WebhookEventDatabaseMoved          WebhookEventType = "database.moved"
+	WebhookEventDatabaseDeleted        WebhookEventType = "database.deleted"
+	WebhookEventDatabaseUndeleted      WebhookEventType = "database.undeleted"
+
+	WebhookEventCommentCreated WebhookEventType = "comment.created"
+	WebhookEventCommentUpdated WebhookEventType = "comment.updated"
+	WebhookEventCommentDeleted WebhookEventType = "comment.deleted"
+)
+
+// Object returns the type of the objects events of this type are about, such
+// as "page" for page.created.
+func (t WebhookEventType) Object() ObjectType {
+	object, _, _ := strings.Cut(string(t), ".")
+	return ObjectType(object)
+}
+
+// WebhookEvent is an event delivered by a webhook: a *PageEvent, a
+// *DatabaseEvent, a *CommentEvent, or an *UnknownWebhookEvent for the types
+// of events this package does not know about.
+type WebhookEvent interface {
+	GetID() string
+	GetType() WebhookEventType
+	GetEntity() WebhookEntity
+	GetTimestamp() time.Time
+}
+
+// BasicWebhookEvent holds the fields common to all the webhook events.
+type BasicWebhookEvent struct {
+	ID             string           `json:"id"`
+	Timestamp      time.Time        `json:"timestamp"`
+	WorkspaceID    string           `json:"workspace_id"`
+	WorkspaceName  string           `json:"workspace_name"`
+	SubscriptionID string           `json:"subscription_id"`
+	IntegrationID  string           `json:"integration_id"`
+	Type           WebhookEventType `json:"type"`
+	// Authors are the users and bots who made the change, with only their ID
+	// and type set.
+	Authors      []User `json:"authors"`
+	AccessibleBy []User `json:"accessible_by,omitempty"`
+	// AttemptNumber is 1 for the first delivery of the event, and counts the
+	// retries after.
+	AttemptNumber int           `json:"attempt_number"`
+	Entity        WebhookEntity `json:"entity"`
+}
+
+func (e *BasicWebhookEvent) GetID() string {
+	return e.ID
+}
+
+func (e *BasicWebhookEvent) GetType() WebhookEventType {
+	return e.Type
+}
+
+func (e *BasicWebhookEvent) GetEntity() WebhookEntity {
+	return e.Entity
+}
+
+func (e *BasicWebhookEvent) GetTimestamp() time.Time {
+	return e.Timestamp
+}
+
+// WebhookEntity is the object an event is about.
+type WebhookEntity struct {
+	ID   string     `json:"id"`
+	Type ObjectType `json:"type"`
+}
+
+// PageEvent is an event about a page.
+type PageEvent struct {
+	BasicWebhookEvent
+	// Parent is the parent of the page, the new one for page.moved.
+	Parent *Parent
+	// UpdatedProperties are the properties changed by
+	// page.properties_updated.
+	UpdatedProperties []PropertyID
+	// UpdatedBlocks are the blocks changed by page.content_updated.
+	UpdatedBlocks []BlockID
+	// Page is the page as of the delivery, when WebhookHandler.Client is set
+	// and the page can still be read.
+	Page *Page
+}
+
+// DatabaseEvent is an event about a database.
+type DatabaseEvent struct {
+	BasicWebhookEvent
+	// Parent is the parent of the database, the new one for database.moved.
+	Parent *Parent
+	// UpdatedProperties are the properties changed by
+	// database.schema_updated.
+	UpdatedProperties []WebhookPropertyChange
+	// UpdatedBlocks are the blocks changed by database.content_updated.
+	UpdatedBlocks []BlockID
+	// Database is the database as of the delivery, when WebhookHandler.Client
+	// is set and the database can still be read.
+	Database *Database
+}
+
+// WebhookPropertyChange is a property of a database changed by
+// database.schema_updated.
+type WebhookPropertyChange struct {
+	ID   PropertyID `json:"id"`
+	Name string     `json:"name"`
+	// Action is "created", "updated" or "deleted".
+	Action string `json:"action"`
+}
+
+// CommentEvent is an event about a comment.
+type CommentEvent struct {
+	BasicWebhookEvent
+	// PageID is the page the comment was made on.
+	PageID PageID
+	// Parent is the page or the block the comment is attached to.
+	Parent *Parent
+	// Comment is the comment as of the delivery, when WebhookHandler.Client is
+	// set and the comment can still be read.
+	Comment *Comment
+}
+
+// UnknownWebhookEvent is an event of a type this package does not decode.
+type UnknownWebhookEvent struct {
+	BasicWebhookEvent
+	Data json.RawMessage
+}
+
+// webhookEventData is the union of the data of the webhook events.
+type webhookEventData struct {
+	Parent *struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	} `json:"parent"`
+	PageID        PageID `json:"page_id"`
+	UpdatedBlocks []struct {
+		ID BlockID `json:"id"`
+	} `json:"updated_blocks"`
+	// UpdatedProperties are property IDs for pages, and property changes for
+	// databases.
+	UpdatedProperties json.RawMessage `json:"updated_properties"`
+}
+
+func (d *webhookEventData) parent() *Parent {
+	if d.Parent == nil {
+		return nil
+	}
+	switch d.Parent.Type {
+	case "page":
+		return &Parent{Type: ParentTypePageID, PageID: PageID(d.Parent.ID)}
+	case "database":
+		return &Parent{Type: ParentTypeDatabaseID, DatabaseID: DatabaseID(d.Parent.ID)}
+	case "block":
+		return &Parent{Type: ParentTypeBlockID, BlockID: BlockID(d.Parent.ID)}
+	case "space", "workspace":
+		return &Parent{Type: ParentTypeWorkspace, Workspace: true}
+	}
+	return &Parent{Type: ParentType(d.Parent.Type)}
+}
+
+func (d *webhookEventData) updatedBlocks() []BlockID {
+	var ids []BlockID
+	for _, b := range d.UpdatedBlocks {
+		ids = append(ids, b.ID)
+	}
+	return ids
+}
+
+// DecodeWebhookEvent decodes the body of a webhook request. It does not
+// verify the signature of the request, see VerifyWebhookSignature.
+func DecodeWebhookEvent(data []byte) (WebhookEvent, error) {
+	var raw struct {
+		BasicWebhookEvent
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if raw.Type == "" {
+		return nil, errors.New("webhook event has no type")
+	}
+
+	var d webhookEventData
+	switch raw.Type.Object() {
+	case ObjectTypePage, ObjectTypeDatabase, ObjectTypeComment:
+		if len(raw.Data) > 0 {
+			if err := json.Unmarshal(raw.Data, &d); err != nil {
+				return nil, fmt.Errorf("decode data of %s event: %w", raw.Type, err)
+			}
+		}
+	}
+
+	switch raw.Type.Object() {
+	case ObjectTypePage:
+		e := &PageEvent{BasicWebhookEvent: raw.BasicWebhookEvent, Parent: d.parent(), UpdatedBlocks: d.updatedBlocks()}
+		if len(d.UpdatedProperties) > 0 {
+			if err := json.Unmarshal(d.UpdatedProperties, &e.UpdatedProperties); err != nil {
+				return nil, fmt.Errorf("decode updated properties of %s event: %w", raw.Type, err)
+			}
+		}
+		return e, nil
+	case ObjectTypeDatabase:
+		e := &DatabaseEvent{BasicWebhookEvent: raw.BasicWebhookEvent, Parent: d.parent(), UpdatedBlocks: d.updatedBlocks()}
+		if len(d.UpdatedProperties) > 0 {
+			if err := json.Unmarshal(d.UpdatedProperties, &e.UpdatedProperties); err != nil {
+				return nil, fmt.Errorf("decode updated properties of %s event: %w", raw.Type, err)
+			}
+		}
+		return e, nil
+	case ObjectTypeComment:
+		return &CommentEvent{BasicWebhookEvent: raw.BasicWebhookEvent, PageID: d.PageID, Parent: d.parent()}, nil
+	}
+	return &UnknownWebhookEvent{BasicWebhookEvent: raw.BasicWebhookEvent, Data: raw.Data}, nil
+}
+
+// VerifyWebhookSignature reports whether signature, the value of the
+// X-Notion-Signature header, is the signature of body with the verification
+// token.
+func VerifyWebhookSignature(verificationToken string, body []byte, signature string) bool {
+	mac := hmac.New(sha256.New, []byte(verificationToken))
+	mac.Write(body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(want), []byte(signature))
+}
+
+// WebhookEventHandler handles a webhook event. An error makes the request
+// fail, for Notion to deliver the event again.
+type WebhookEventHandler func(ctx context.Context, event WebhookEvent) error
+
+// WebhookHandler is an http.Handler receiving the events of a webhook
+// subscription and dispatching them to the handlers registered.
+//
+// When a subscription is created, Notion sends a verification token to the
+// webhook, which is passed to OnVerification. It must then be entered in the
+// settings of the integration to activate the subscription, and set as the
+// VerificationToken of the handler: events are signed with it, and rejected
+// until it is set.
+type WebhookHandler struct {
+	// VerificationToken is the key of the signature of the events.
+	VerificationToken string
+	// OnVerification receives the verification token of new subscriptions.
+	// As the verification request is not signed, it may be sent by anyone.
+	OnVerification func(ctx context.Context, token string) error
+	// Client, when set, is used to read the page, the database or the comment
+	// of events before dispatching them.
+	Client *Client
+
+	handlers map[WebhookEventType][]WebhookEventHandler
+	all      []WebhookEventHandler
+}
+
+// NewWebhookHandler returns a handler verifying events with the token, which
+// may be empty until the subscription is verified.
+func NewWebhookHandler(verificationToken string) *WebhookHandler {
+	return &WebhookHandler{
+		VerificationToken: verificationToken,
+		handlers:          map[WebhookEventType][]WebhookEventHandler{},
+	}
+}
+
+// Handle registers a handler for the events of a type, or for all the events
+// when the type is empty. Handlers are called in the order they are
+// registered.
+func (h *WebhookHandler) Handle(t WebhookEventType, handler WebhookEventHandler) {
+	if t == "" {
+		h.all = append(h.all, handler)
+		return
+	}
+	if h.handlers == nil {
+		h.handlers = map[WebhookEventType][]WebhookEventHandler{}
+	}
+	h.handlers[t] = append(h.handlers[t], handler)
+}
+
+// HandlePage registers a handler for all the events about pages.
+func (h *WebhookHandler) HandlePage(handler func(ctx context.Context, event *PageEvent) error) {
+	h.Handle("", func(ctx context.Context, event WebhookEvent) error {
+		if e, ok := event.(*PageEvent); ok {
+			return handler(ctx, e)
+		}
+		return nil
+	})
+}
+
+// HandleDatabase registers a handler for all the events about databases.
+func (h *WebhookHandler) HandleDatabase(handler func(ctx context.Context, event *DatabaseEvent) error) {
+	h.Handle("", func(ctx context.Context, event WebhookEvent) error {
+		if e, ok := event.(*DatabaseEvent); ok {
+			return handler(ctx, e)
+		}
+		return nil
+	})
+}
+
+// HandleComment registers a handler for all the events about comments.
+func (h *WebhookHandler) HandleComment(handler func(ctx context.Context, event *CommentEvent) error) {
+	h.Handle("", func(ctx context.Context, event WebhookEvent) error {
+		if e, ok := event.(*CommentEvent); ok {
+			return handler(ctx, e)
+		}
+		return nil
+	})
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	if r.Header.Get(WebhookSignatureHeader) == "" {
+		var verification struct {
+			VerificationToken string `json:"verification_token"`
+		}
+		if json.Unmarshal(body, &verification) == nil && verification.VerificationToken != "" {
+			h.verify(w, r, verification.VerificationToken)
+			return
+		}
+	}
+
+	if h.VerificationToken == "" {
+		http.Error(w, "subscription not verified", http.StatusUnauthorized)
+		return
+	}
+	if !VerifyWebhookSignature(h.VerificationToken, body, r.Header.Get(WebhookSignatureHeader)) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	event, err := DecodeWebhookEvent(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.dispatch(r.Context(), event); err != nil {
+		http.Error(w, "failed to handle event", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *WebhookHandler) verify(w http.ResponseWriter, r *http.Request, token string) {
+	if h.OnVerification == nil {
+		http.Error(w, "verification not handled", http.StatusInternalServerError)
+		return
+	}
+	if err := h.OnVerification(r.Context(), token); err != nil {
+		http.Error(w, "failed to handle verification", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *WebhookHandler) dispatch(ctx context.Context, event WebhookEvent) error {
+	var handlers []WebhookEventHandler
+	handlers = append(handlers, h.handlers[event.GetType()]...)
+	handlers = append(handlers, h.all...)
+	if len(handlers) == 0 {
+		return nil
+	}
+	if h.Client != nil {
+		if err := h.fetch(ctx, event); err != nil {
+			return err
+		}
+	}
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetch reads the object of an event, leaving it nil when it cannot be read
+// anymore.
+func (h *WebhookHandler) fetch(ctx context.Context, event WebhookEvent) error {
+	var err error
+	switch e := event.(type) {
+	case *PageEvent:
+		e.Page, err = h.Client.Page.Get(ctx, PageID(e.Entity.ID))
+	case *DatabaseEvent:
+		e.Database, err = h.Client.Database.Get(ctx, DatabaseID(e.Entity.ID))
+	case *CommentEvent:
+		e.Comment, err = h.comment(ctx, e)
+	}
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// comment finds the comment of an event among the comments of its parent, as
+// comments cannot be read by ID.
+func (h *WebhookHandler) comment(ctx context.Context, e *CommentEvent) (*Comment, error) {
+	parent := BlockID(e.PageID)
+	if e.Parent != nil && e.Parent.Type == ParentTypeBlockID {
+		parent = e.Parent.BlockID
+	}
+	if parent == "" {
+		return nil, nil
+	}
+	it := h.Client.Comment.GetAll(ctx, parent)
+	for it.Next() {
+		if c := it.Value(); c.ID.String() == e.Entity.ID {
+			return c, nil
+		}
+	}
+	return nil, it.Err()
+}
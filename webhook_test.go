@@ -0,0 +1,315 @@
+package notionapi_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/jomei/notionapi/notionapitest"
+)
+
+const webhookToken = "secret_token"
+
+func sign(body string) string {
+	mac := hmac.New(sha256.New, []byte(webhookToken))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func webhookRequest(body, signature string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	if signature != "" {
+		req.Header.Set(notionapi.WebhookSignatureHeader, signature)
+	}
+	return req
+}
+
+func webhookEvent(eventType, entityID, entityType, data string) string {
+	return fmt.Sprintf(`{
+		"id": "event_id",
+		"timestamp": "2024-12-05T23:57:05.379Z",
+		"workspace_id": "workspace_id",
+		"workspace_name": "Workspace",
+		"subscription_id": "subscription_id",
+		"integration_id": "integration_id",
+		"type": %q,
+		"authors": [{"id": "user_id", "type": "person"}],
+		"attempt_number": 1,
+		"entity": {"id": %q, "type": %q},
+		"data": %s
+	}`, eventType, entityID, entityType, data)
+}
+
+func TestDecodeWebhookEvent(t *testing.T) {
+	basic := func(eventType notionapi.WebhookEventType, entityID string, entityType notionapi.ObjectType) notionapi.BasicWebhookEvent {
+		return notionapi.BasicWebhookEvent{
+			ID:             "event_id",
+			Timestamp:      time.Date(2024, 12, 5, 23, 57, 5, 379000000, time.UTC),
+			WorkspaceID:    "workspace_id",
+			WorkspaceName:  "Workspace",
+			SubscriptionID: "subscription_id",
+			IntegrationID:  "integration_id",
+			Type:           eventType,
+			Authors:        []notionapi.User{{ID: "user_id", Type: notionapi.UserTypePerson}},
+			AttemptNumber:  1,
+			Entity:         notionapi.WebhookEntity{ID: entityID, Type: entityType},
+		}
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want notionapi.WebhookEvent
+	}{
+		{
+			name: "page.properties_updated",
+			body: webhookEvent("page.properties_updated", "page_id", "page",
+				`{"parent": {"id": "db_id", "type": "database"}, "updated_properties": ["XGe%40", "title"]}`),
+			want: &notionapi.PageEvent{
+				BasicWebhookEvent: basic(notionapi.WebhookEventPagePropertiesUpdated, "page_id", notionapi.ObjectTypePage),
+				Parent:            &notionapi.Parent{Type: notionapi.ParentTypeDatabaseID, DatabaseID: "db_id"},
+				UpdatedProperties: []notionapi.PropertyID{"XGe%40", "title"},
+			},
+		},
+		{
+			name: "page.content_updated",
+			body: webhookEvent("page.content_updated", "page_id", "page",
+				`{"parent": {"id": "workspace_id", "type": "space"}, "updated_blocks": [{"id": "block_id", "type": "block"}]}`),
+			want: &notionapi.PageEvent{
+				BasicWebhookEvent: basic(notionapi.WebhookEventPageContentUpdated, "page_id", notionapi.ObjectTypePage),
+				Parent:            &notionapi.Parent{Type: notionapi.ParentTypeWorkspace, Workspace: true},
+				UpdatedBlocks:     []notionapi.BlockID{"block_id"},
+			},
+		},
+		{
+			name: "database.schema_updated",
+			body: webhookEvent("database.schema_updated", "db_id", "database",
+				`{"parent": {"id": "page_id", "type": "page"}, "updated_properties": [{"id": "abc", "name": "Status", "action": "created"}]}`),
+			want: &notionapi.DatabaseEvent{
+				BasicWebhookEvent: basic(notionapi.WebhookEventDatabaseSchemaUpdated, "db_id", notionapi.ObjectTypeDatabase),
+				Parent:            &notionapi.Parent{Type: notionapi.ParentTypePageID, PageID: "page_id"},
+				UpdatedProperties: []notionapi.WebhookPropertyChange{{ID: "abc", Name: "Status", Action: "created"}},
+			},
+		},
+		{
+			name: "comment.created",
+			body: webhookEvent("comment.created", "comment_id", "comment",
+				`{"page_id": "page_id", "parent": {"id": "block_id", "type": "block"}}`),
+			want: &notionapi.CommentEvent{
+				BasicWebhookEvent: basic(notionapi.WebhookEventCommentCreated, "comment_id", notionapi.ObjectTypeComment),
+				PageID:            "page_id",
+				Parent:            &notionapi.Parent{Type: notionapi.ParentTypeBlockID, BlockID: "block_id"},
+			},
+		},
+		{
+			name: "unknown type",
+			body: webhookEvent("view.created", "view_id", "view", `{"a":1}`),
+			want: &notionapi.UnknownWebhookEvent{
+				BasicWebhookEvent: basic("view.created", "view_id", "view"),
+				Data:              []byte(`{"a":1}`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := notionapi.DecodeWebhookEvent([]byte(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeWebhookEvent() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := notionapi.DecodeWebhookEvent([]byte(`{"id": "event_id"}`)); err == nil {
+		t.Error("DecodeWebhookEvent() of an event without type error = nil")
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	event := webhookEvent("page.created", "page_id", "page", `{"parent": {"id": "page_id", "type": "page"}}`)
+	handlerErr := errors.New("failed")
+
+	tests := []struct {
+		name       string
+		token      string
+		method     string
+		body       string
+		signature  string
+		handlerErr error
+		wantStatus int
+		wantEvents []string
+	}{
+		{
+			name:       "dispatches signed events",
+			token:      webhookToken,
+			body:       event,
+			signature:  sign(event),
+			wantStatus: http.StatusOK,
+			wantEvents: []string{"page.created page_id", "page page_id", "all page.created"},
+		},
+		{
+			name:       "rejects invalid signatures",
+			token:      webhookToken,
+			body:       event,
+			signature:  sign(event + " "),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "rejects unsigned events",
+			token:      webhookToken,
+			body:       event,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "rejects events before verification",
+			body:       event,
+			signature:  sign(event),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "rejects other methods",
+			token:      webhookToken,
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "fails with the handler",
+			token:      webhookToken,
+			body:       event,
+			signature:  sign(event),
+			handlerErr: handlerErr,
+			wantStatus: http.StatusInternalServerError,
+			wantEvents: []string{"page.created page_id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []string
+			h := notionapi.NewWebhookHandler(tt.token)
+			h.Handle(notionapi.WebhookEventPageCreated, func(ctx context.Context, e notionapi.WebhookEvent) error {
+				events = append(events, fmt.Sprintf("%s %s", e.GetType(), e.GetEntity().ID))
+				return tt.handlerErr
+			})
+			h.HandleDatabase(func(ctx context.Context, e *notionapi.DatabaseEvent) error {
+				events = append(events, "database "+e.Entity.ID)
+				return nil
+			})
+			h.HandlePage(func(ctx context.Context, e *notionapi.PageEvent) error {
+				events = append(events, "page "+e.Parent.PageID.String())
+				return nil
+			})
+			h.Handle("", func(ctx context.Context, e notionapi.WebhookEvent) error {
+				events = append(events, fmt.Sprintf("all %s", e.GetType()))
+				return nil
+			})
+
+			req := webhookRequest(tt.body, tt.signature)
+			if tt.method != "" {
+				req.Method = tt.method
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(events, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", events, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestWebhookHandler_Verification(t *testing.T) {
+	body := `{"verification_token": "secret_token"}`
+
+	h := notionapi.NewWebhookHandler("")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, webhookRequest(body, ""))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status without OnVerification = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+
+	var token string
+	h.OnVerification = func(ctx context.Context, t string) error {
+		token = t
+		return nil
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, webhookRequest(body, ""))
+	if rec.Code != http.StatusOK || token != webhookToken {
+		t.Errorf("status = %d, token = %q, want %d and %q", rec.Code, token, http.StatusOK, webhookToken)
+	}
+}
+
+func TestWebhookHandler_Client(t *testing.T) {
+	ctx := context.Background()
+	srv := notionapitest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	page, err := client.Page.Create(ctx, &notionapi.PageCreateRequest{
+		Parent:     notionapi.Parent{Type: notionapi.ParentTypePageID, PageID: srv.RootPageID()},
+		Properties: notionapi.Properties{"title": notionapi.TitleProperty{Title: richText("Notes")}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	comment, err := client.Comment.Create(ctx, &notionapi.CommentCreateRequest{
+		Parent:   notionapi.Parent{Type: notionapi.ParentTypePageID, PageID: notionapi.PageID(page.ID)},
+		RichText: richText("Looks good"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := notionapi.NewWebhookHandler(webhookToken)
+	h.Client = client
+	var got []string
+	h.HandlePage(func(ctx context.Context, e *notionapi.PageEvent) error {
+		if e.Page == nil {
+			got = append(got, "page <nil>")
+		} else {
+			got = append(got, "page "+e.Page.ID.String())
+		}
+		return nil
+	})
+	h.HandleComment(func(ctx context.Context, e *notionapi.CommentEvent) error {
+		if e.Comment == nil {
+			got = append(got, "comment <nil>")
+		} else {
+			got = append(got, "comment "+e.Comment.RichText[0].PlainText)
+		}
+		return nil
+	})
+
+	for _, body := range []string{
+		webhookEvent("page.content_updated", page.ID.String(), "page", `{"updated_blocks": []}`),
+		webhookEvent("page.deleted", "missing_page_id", "page", `{}`),
+		webhookEvent("comment.created", comment.ID.String(), "comment",
+			fmt.Sprintf(`{"page_id": %q, "parent": {"id": %q, "type": "page"}}`, page.ID, page.ID)),
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, webhookRequest(body, sign(body)))
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %d: %s", rec.Code, rec.Body)
+		}
+	}
+	want := []string{"page " + page.ID.String(), "page <nil>", "comment Looks good"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+}